@@ -1,16 +1,20 @@
 package app
 
 import (
-	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"strings"
 
 	"github.com/Ali-Farhadnia/goshell/internal/config"
 	"github.com/Ali-Farhadnia/goshell/internal/database"
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	frecencyRepository "github.com/Ali-Farhadnia/goshell/internal/service/frecency/repository"
 	"github.com/Ali-Farhadnia/goshell/internal/service/history"
 	historyRepository "github.com/Ali-Farhadnia/goshell/internal/service/history/repository"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
@@ -19,12 +23,17 @@ import (
 	"github.com/Ali-Farhadnia/goshell/internal/service/user"
 	userRepository "github.com/Ali-Farhadnia/goshell/internal/service/user/repository"
 	"github.com/Ali-Farhadnia/goshell/pkg/inputprocessor"
+	"github.com/Ali-Farhadnia/goshell/pkg/lineeditor"
 )
 
 // Shell is the main shell application
 type App struct {
 	shellSVC    *shell.Service
+	historySVC  *history.Service
 	sessionRepo shell.SessionRepository
+	editor      *lineeditor.Editor
+	keyBindings map[string]map[string]string
+	ps2         string
 }
 
 // NewShell creates and initializes a new shell
@@ -42,38 +51,102 @@ func New(cfg *config.Config) (*App, error) {
 	guestHisotryCache := historyRepository.NewInMemory()
 	cmdRepo := shellRepository.NewInMemoryCommandRepository()
 
+	keyBindings := map[string]map[string]string{
+		lineeditor.KeymapEmacs:     cfg.KeyBindings.Emacs,
+		lineeditor.KeymapViInsert:  cfg.KeyBindings.ViInsert,
+		lineeditor.KeymapViCommand: cfg.KeyBindings.ViCommand,
+	}
+
 	userSVC := user.New(usrRepo)
 	historySVC := history.New(historyRepo, guestHisotryCache, -1)
+	frecencySVC := frecency.New(frecencyRepository.New(db), frecencyRepository.NewInMemory(), -1)
+	redactor, err := history.NewRedactor(history.RedactionConfig{
+		Arguments: cfg.History.RedactArguments,
+		Patterns:  cfg.History.RedactPatterns,
+		Ignore:    cfg.History.Ignore,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid history config: %w", err)
+	}
+	historySVC.SetRedactor(redactor)
+	switch cfg.History.MergeGuest {
+	case history.MergeGuestAsk, history.MergeGuestAlways, history.MergeGuestNever:
+	default:
+		return nil, fmt.Errorf("invalid history config: unknown mergeGuest mode %q", cfg.History.MergeGuest)
+	}
+	historySVC.SetRetention(history.RetentionPolicy{
+		MaxEntries:       cfg.Shell.HistorySize,
+		MaxAge:           cfg.History.MaxAge,
+		DedupConsecutive: cfg.History.DedupConsecutive,
+	})
 	shellSVC := shell.NewService(historySVC, sessionRepo, cmdRepo, shell.NewSystemCommand(sessionRepo, os.Getenv("PATH")), os.Getenv("PATH"))
 
+	// the line editor also prompts for passwords
+	editor := lineeditor.New(os.Stdin, os.Stdout)
+
 	// register commands
 
 	// exit
-	shellSVC.RegisterCommand(commands.NewExitCommand(nil, nil))
+	shellSVC.RegisterCommand(commands.NewExitCommand())
 	// echo
 	shellSVC.RegisterCommand(commands.NewEchoCommand())
+	// printf
+	shellSVC.RegisterCommand(commands.NewPrintfCommand())
 	// cat
 	shellSVC.RegisterCommand(commands.NewCatCommand(sessionRepo))
-	// type
+	// type, which, command and builtin
 	shellSVC.RegisterCommand(commands.NewTypeCommand(cmdRepo, os.Getenv("PATH")))
+	shellSVC.RegisterCommand(commands.NewWhichCommand(os.Getenv("PATH")))
+	shellSVC.RegisterCommand(commands.NewCommandCommand(cmdRepo, sessionRepo, os.Getenv("PATH")))
+	shellSVC.RegisterCommand(commands.NewBuiltinCommand(cmdRepo))
+	// hash
+	shellSVC.RegisterCommand(commands.NewHashCommand(cmdRepo, sessionRepo, os.Getenv("PATH")))
 	// pwd
 	shellSVC.RegisterCommand(commands.NewPWDCommand(sessionRepo))
 	// login
-	shellSVC.RegisterCommand(commands.NewLoginCommand(userSVC, sessionRepo))
+	shellSVC.RegisterCommand(commands.NewLoginCommand(userSVC, historySVC, sessionRepo, editor, cfg.History.MergeGuest))
 	// adduser
-	shellSVC.RegisterCommand(commands.NewAddUserCommand(userSVC))
+	shellSVC.RegisterCommand(commands.NewAddUserCommand(userSVC, editor))
 	// logout
 	shellSVC.RegisterCommand(commands.NewLogoutCommand(sessionRepo))
 	// ls
 	shellSVC.RegisterCommand(commands.NewLSCommand(sessionRepo))
+	// mkdir, rm, cp, mv, touch, ln
+	shellSVC.RegisterCommand(commands.NewMkdirCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewRmCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewCpCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewMvCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewTouchCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewLnCommand(sessionRepo))
+	// head, tail, wc, grep, sort, uniq, tee
+	shellSVC.RegisterCommand(commands.NewHeadCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewTailCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewWcCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewGrepCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewSortCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewUniqCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewTeeCommand(sessionRepo))
+	// read
+	shellSVC.RegisterCommand(commands.NewReadCommand(editor))
 	// cd
-	shellSVC.RegisterCommand(commands.NewCDCommand(sessionRepo))
+	shellSVC.RegisterCommand(commands.NewCDCommand(sessionRepo, frecencySVC))
+	// pushd, popd, dirs
+	shellSVC.RegisterCommand(commands.NewPushdCommand(sessionRepo, frecencySVC))
+	shellSVC.RegisterCommand(commands.NewPopdCommand(sessionRepo, frecencySVC))
+	shellSVC.RegisterCommand(commands.NewDirsCommand(sessionRepo))
+	// z, zi
+	shellSVC.RegisterCommand(commands.NewZCommand(sessionRepo, frecencySVC))
+	shellSVC.RegisterCommand(commands.NewZICommand(sessionRepo, frecencySVC, editor))
 	// history
-	shellSVC.RegisterCommand(commands.NewHistoryCommand(historySVC, sessionRepo))
+	shellSVC.RegisterCommand(commands.NewHistoryCommand(historySVC, sessionRepo, userSVC, cfg.Shell.Admins))
 	// help
-	shellSVC.RegisterCommand(commands.NewHelpCommand(cmdRepo))
+	shellSVC.RegisterCommand(commands.NewHelpCommand(cmdRepo, sessionRepo))
 	// users
 	shellSVC.RegisterCommand(commands.NewUsersCommand(userSVC))
+	// set
+	shellSVC.RegisterCommand(commands.NewSetCommand(sessionRepo))
+	// bind
+	shellSVC.RegisterCommand(commands.NewBindCommand(userSVC, sessionRepo, keyBindings))
 
 	curDir, err := os.Getwd()
 	if err != nil {
@@ -81,19 +154,39 @@ func New(cfg *config.Config) (*App, error) {
 	}
 
 	// create guest user
+	sessionID, err := newSessionID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session id: %w", err)
+	}
 	sessionRepo.SetSession(shell.Session{
-		User:       nil,
-		WorkingDir: curDir,
+		ID:          sessionID,
+		User:        nil,
+		WorkingDir:  curDir,
+		EditingMode: cfg.KeyBindings.Mode,
+		Hash:        shell.NewCommandHash(),
 	})
 
+	// line editor with syntax highlighting and autosuggestions
+	editor.SetHighlighter(shell.NewHighlighter(cmdRepo, os.Getenv("PATH")))
+	editor.SetSuggester(shell.NewSuggester(historySVC, sessionRepo))
+	if err := editor.SetMode(cfg.KeyBindings.Mode); err != nil {
+		return nil, fmt.Errorf("invalid keybindings config: %w", err)
+	}
+	if err := editor.SetBindings(keyBindings); err != nil {
+		return nil, fmt.Errorf("invalid keybindings config: %w", err)
+	}
+
 	return &App{
 		shellSVC:    shellSVC,
+		historySVC:  historySVC,
 		sessionRepo: sessionRepo,
+		editor:      editor,
+		keyBindings: keyBindings,
+		ps2:         cfg.Shell.PS2,
 	}, nil
 }
 
 func (a *App) Run() error {
-	reader := bufio.NewReader(os.Stdin)
 	ctx := context.Background()
 
 	for {
@@ -101,26 +194,54 @@ func (a *App) Run() error {
 		if err != nil {
 			return err
 		}
+		prompt := "$ "
 		if session.User != nil {
-			fmt.Printf("%s:$ ", session.User.Username)
-		} else {
-			fmt.Print("$ ")
-
+			prompt = fmt.Sprintf("%s:$ ", session.User.Username)
 		}
 
-		input, err := reader.ReadString('\n')
+		a.configureEditor(session)
+		input, err := a.readCommand(prompt)
+		a.syncEditingMode()
 		if err != nil {
+			if errors.Is(err, lineeditor.ErrInterrupted) {
+				continue
+			}
+			if errors.Is(err, inputprocessor.ErrIncompleteInput) {
+				fmt.Println("error:", err)
+				continue
+			}
 			if errors.Is(err, io.EOF) {
 				fmt.Println("\nExiting...")
+				a.historySVC.Wait()
 				return nil
 			}
 			return fmt.Errorf("error reading input: %w", err)
 		}
 
-		input = strings.TrimSpace(input)
-		if input == "" {
+		// a leading space is kept: it means the command is not recorded in history
+		input = strings.TrimRight(input, " \t\n")
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+
+		// History expansion happens before parsing; the expanded line is echoed like in bash
+		var userID *int64
+		if session.User != nil {
+			userID = &session.User.ID
+		}
+		expanded, changed, err := a.historySVC.ExpandHistory(userID, input)
+		if err != nil {
+			fmt.Println("error:", err)
 			continue
 		}
+		if changed {
+			input = expanded
+			fmt.Println(input)
+		}
+
+		if !strings.HasPrefix(input, " ") {
+			a.editor.AddHistory(a.shellSVC.RedactedLine(input))
+		}
 
 		// Parse input line into arguments (handles quotes and escaping)
 		args, err := inputprocessor.ParseArguments(input)
@@ -137,10 +258,92 @@ func (a *App) Run() error {
 			continue
 		}
 
+		// Ctrl-C cancels the running command instead of exiting the shell
 		commandName, commandArgs := cleanArgs[0], cleanArgs[1:]
-		err = a.shellSVC.ExecuteCommand(ctx, commandName, commandArgs, inputReader, outputWriter, errorOutputWriter)
+		cmdCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		err = a.shellSVC.ExecuteCommand(shell.WithCommandLine(cmdCtx, input), commandName, commandArgs, inputReader, outputWriter, errorOutputWriter)
+		stop()
+		var exitShell *shell.ExitShell
+		if errors.As(err, &exitShell) {
+			// let background history pruning finish before the process exits
+			cleanup()
+			a.historySVC.Wait()
+			os.Exit(exitShell.Code)
+		}
 		if err != nil {
 			fmt.Fprintln(errorOutputWriter, "error:", err)
 		}
 	}
 }
+
+// newSessionID returns a random identifier for this shell process
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// readCommand reads a logical command line, asking for continuation lines with
+// the PS2 prompt while the input is incomplete (open quotes, trailing \, |, &&,
+// unclosed if, { or here-documents).
+func (a *App) readCommand(prompt string) (string, error) {
+	input, err := a.editor.ReadLine(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		incomplete := inputprocessor.CheckComplete(input)
+		if incomplete == nil {
+			return input, nil
+		}
+
+		line, err := a.editor.ReadLine(a.ps2)
+		if errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("unexpected end of file: %w", incomplete)
+		}
+		if err != nil {
+			return "", err
+		}
+		input = inputprocessor.JoinLines(input, line)
+	}
+}
+
+// configureEditor applies the session editing mode and key bindings to the line editor.
+// Bindings saved for the user take precedence over the configured ones.
+func (a *App) configureEditor(session shell.Session) {
+	if session.EditingMode != "" {
+		if err := a.editor.SetMode(session.EditingMode); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+	}
+
+	bindings := make(map[string]map[string]string)
+	for _, source := range []map[string]map[string]string{a.keyBindings, session.KeyBindings} {
+		for keymap, keys := range source {
+			for key, action := range keys {
+				if bindings[keymap] == nil {
+					bindings[keymap] = make(map[string]string)
+				}
+				bindings[keymap][key] = action
+			}
+		}
+	}
+
+	if err := a.editor.SetBindings(bindings); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+	}
+}
+
+// syncEditingMode stores a mode switched from within the editor back in the session.
+func (a *App) syncEditingMode() {
+	session, err := a.sessionRepo.GetSession()
+	if err != nil || session.EditingMode == a.editor.Mode() {
+		return
+	}
+
+	session.EditingMode = a.editor.Mode()
+	a.sessionRepo.SetSession(session)
+}
@@ -2,20 +2,25 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Shell    ShellConfig    `mapstructure:"shell"`
-	Database DatabaseConfig `mapstructure:"database"`
+	Shell       ShellConfig       `mapstructure:"shell"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	KeyBindings KeyBindingsConfig `mapstructure:"keybindings"`
+	History     HistoryConfig     `mapstructure:"history"`
 }
 
 // ShellConfig holds shell-specific configuration
 type ShellConfig struct {
-	Verbose     bool `mapstructure:"verbose"`
-	HistorySize int  `mapstructure:"historySize"`
+	Verbose     bool     `mapstructure:"verbose"`
+	HistorySize int      `mapstructure:"historySize"`
+	PS2         string   `mapstructure:"ps2"`
+	Admins      []string `mapstructure:"admins"`
 }
 
 // DatabaseConfig holds database configuration
@@ -26,6 +31,27 @@ type DatabaseConfig struct {
 	LogLevel    string `mapstructure:"logLevel"`
 }
 
+// KeyBindingsConfig holds line editor configuration. Each keymap maps key
+// names such as "ctrl-a" or "alt-right" to editing actions.
+type KeyBindingsConfig struct {
+	Mode      string            `mapstructure:"mode"`
+	Emacs     map[string]string `mapstructure:"emacs"`
+	ViInsert  map[string]string `mapstructure:"viInsert"`
+	ViCommand map[string]string `mapstructure:"viCommand"`
+}
+
+// HistoryConfig holds command history configuration. Redaction rules are
+// added to the built-in ones for login, adduser and common token formats.
+// Retention limits apply per user together with shell.historySize.
+type HistoryConfig struct {
+	Ignore           []string         `mapstructure:"ignore"`
+	RedactPatterns   []string         `mapstructure:"redactPatterns"`
+	RedactArguments  map[string][]int `mapstructure:"redactArguments"`
+	MaxAge           time.Duration    `mapstructure:"maxAge"`
+	DedupConsecutive bool             `mapstructure:"dedupConsecutive"`
+	MergeGuest       string           `mapstructure:"mergeGuest"`
+}
+
 // Load loads the configuration from a file
 func Load(path string) (*Config, error) {
 	viper.SetConfigFile(path)
@@ -34,6 +60,11 @@ func Load(path string) (*Config, error) {
 	// Set defaults
 	viper.SetDefault("shell.verbose", false)
 	viper.SetDefault("shell.historySize", 1000)
+	viper.SetDefault("shell.ps2", "> ")
+
+	viper.SetDefault("keybindings.mode", "emacs")
+
+	viper.SetDefault("history.mergeGuest", "ask")
 
 	viper.SetDefault("database.driver", "postgres")
 	viper.SetDefault("database.dsn", "host=localhost user=goshell password=password dbname=goshell port=5432 sslmode=disable")
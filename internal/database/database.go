@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/Ali-Farhadnia/goshell/internal/config"
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
 	"github.com/Ali-Farhadnia/goshell/internal/service/history"
 	"github.com/Ali-Farhadnia/goshell/internal/service/user"
 	"gorm.io/driver/postgres"
@@ -57,11 +58,24 @@ func New(cfg *config.DatabaseConfig) (*DB, error) {
 	if cfg.AutoMigrate {
 		err = db.AutoMigrate(
 			&user.User{},
+			&user.KeyBinding{},
 			&history.CommandHistory{},
+			&frecency.DirectoryScore{},
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to run migrations: %w", err)
 		}
+
+		// history rows saved before execution times were recorded use their creation time
+		err = db.Model(&history.CommandHistory{}).
+			Where("started_at IS NULL").
+			Updates(map[string]any{
+				"started_at":  gorm.Expr("created_at"),
+				"finished_at": gorm.Expr("created_at"),
+			}).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to backfill command history: %w", err)
+		}
 	}
 
 	return &DB{DB: db}, nil
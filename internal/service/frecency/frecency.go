@@ -0,0 +1,150 @@
+package frecency
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxRank is the total rank of a user after which all ranks are aged, so
+// directories that are no longer visited eventually drop out
+const MaxRank = 10000
+
+// Repository defines operations on directory scores
+type Repository interface {
+	// Visit adds one to the rank of path, creating it when needed
+	Visit(userID int64, path string, at time.Time) error
+	List(userID int64) ([]DirectoryScore, error)
+	Remove(userID int64, path string) error
+	// Age multiplies the ranks of a user by factor and deletes the
+	// directories whose rank falls below one
+	Age(userID int64, factor float64) error
+}
+
+// Service tracks the directories users visit and finds the best match for
+// a query by frequency and recency
+type Service struct {
+	repo       Repository
+	guestCache Repository
+	guestID    int64
+}
+
+// New creates a new Service instance. Guest visits go to guestCache.
+func New(repo Repository, guestCache Repository, guestID int64) *Service {
+	return &Service{
+		repo:       repo,
+		guestCache: guestCache,
+		guestID:    guestID,
+	}
+}
+
+// repository returns the repository and ID to use for a user, nil meaning
+// the guest
+func (s *Service) repository(userID *int64) (Repository, int64) {
+	if userID == nil {
+		return s.guestCache, s.guestID
+	}
+	return s.repo, *userID
+}
+
+// Visit records a visit to dir
+func (s *Service) Visit(userID *int64, dir string) error {
+	repo, id := s.repository(userID)
+
+	if err := repo.Visit(id, filepath.Clean(dir), time.Now()); err != nil {
+		return err
+	}
+
+	scores, err := repo.List(id)
+	if err != nil {
+		return err
+	}
+
+	total := 0.0
+	for _, score := range scores {
+		total += score.Rank
+	}
+	if total > MaxRank {
+		return repo.Age(id, 0.9*MaxRank/total)
+	}
+
+	return nil
+}
+
+// Query returns the directories matching keywords, best first. exclude,
+// usually the working directory, is left out. Directories that no longer
+// exist are removed.
+func (s *Service) Query(userID *int64, keywords []string, exclude string) ([]Match, error) {
+	repo, id := s.repository(userID)
+
+	scores, err := repo.List(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var matches []Match
+	lastAccess := make(map[string]time.Time)
+	for _, score := range scores {
+		if score.Path == exclude || !Matches(score.Path, keywords) {
+			continue
+		}
+
+		if info, err := os.Stat(score.Path); err != nil || !info.IsDir() {
+			// stale entries are dropped on a best effort basis
+			_ = repo.Remove(id, score.Path)
+			continue
+		}
+
+		matches = append(matches, Match{Path: score.Path, Score: Score(score, now)})
+		lastAccess[score.Path] = score.LastAccess
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return lastAccess[matches[i].Path].After(lastAccess[matches[j].Path])
+	})
+
+	return matches, nil
+}
+
+// Score weighs the rank of a directory by how recently it was visited
+func Score(score DirectoryScore, now time.Time) float64 {
+	age := now.Sub(score.LastAccess)
+	switch {
+	case age < time.Hour:
+		return score.Rank * 4
+	case age < 24*time.Hour:
+		return score.Rank * 2
+	case age < 7*24*time.Hour:
+		return score.Rank / 2
+	default:
+		return score.Rank / 4
+	}
+}
+
+// Matches reports whether path contains the keywords in order, ignoring
+// case. The last keyword must match the last component of the path, so
+// "z foo" prefers /src/foo over /src/foo/bar.
+func Matches(path string, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+
+	lower := strings.ToLower(path)
+	pos := 0
+	for _, keyword := range keywords {
+		i := strings.Index(lower[pos:], strings.ToLower(keyword))
+		if i < 0 {
+			return false
+		}
+		pos += i + len(keyword)
+	}
+
+	last := strings.ToLower(keywords[len(keywords)-1])
+	return strings.Contains(strings.ToLower(filepath.Base(path)), last)
+}
@@ -0,0 +1,110 @@
+package frecency_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMatches(t *testing.T) {
+	cases := []struct {
+		path     string
+		keywords []string
+		expected bool
+	}{
+		{"/home/u/src/goshell", []string{"gosh"}, true},
+		{"/home/u/src/goshell", []string{"SRC", "shell"}, true},
+		{"/home/u/src/goshell", []string{"shell", "src"}, false},
+		{"/home/u/src/goshell/internal", []string{"goshell"}, false},
+		{"/home/u/src/goshell", nil, true},
+		{"/home/u/src/goshell", []string{"go", "go"}, false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, frecency.Matches(tc.path, tc.keywords), "%s %v", tc.path, tc.keywords)
+	}
+}
+
+func TestScore(t *testing.T) {
+	now := time.Now()
+	score := frecency.DirectoryScore{Rank: 8}
+
+	cases := []struct {
+		age      time.Duration
+		expected float64
+	}{
+		{time.Minute, 32},
+		{2 * time.Hour, 16},
+		{48 * time.Hour, 4},
+		{30 * 24 * time.Hour, 2},
+	}
+
+	for _, tc := range cases {
+		score.LastAccess = now.Add(-tc.age)
+		assert.Equal(t, tc.expected, frecency.Score(score, now), tc.age.String())
+	}
+}
+
+func TestService_Query(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "src", "project")
+	projectDocs := filepath.Join(root, "src", "project-docs")
+	other := filepath.Join(root, "other", "project")
+	for _, dir := range []string{project, projectDocs, other} {
+		assert.NoError(t, os.MkdirAll(dir, 0o755))
+	}
+
+	service := frecency.New(nil, repository.NewInMemory(), -1)
+	for _, dir := range []string{project, project, project, projectDocs, other, other} {
+		assert.NoError(t, service.Visit(nil, dir))
+	}
+
+	matches, err := service.Query(nil, []string{"project"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{project, other, projectDocs}, paths(matches))
+
+	// the working directory is skipped
+	matches, err = service.Query(nil, []string{"project"}, project)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{other, projectDocs}, paths(matches))
+
+	matches, err = service.Query(nil, []string{"src", "proj"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{project, projectDocs}, paths(matches))
+
+	// directories that no longer exist are forgotten
+	assert.NoError(t, os.RemoveAll(other))
+	matches, err = service.Query(nil, []string{"project"}, project)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{projectDocs}, paths(matches))
+}
+
+func TestService_Visit_Ages(t *testing.T) {
+	userID := int64(7)
+	mockRepo := new(repository.FrecencyRepositoryMock)
+	service := frecency.New(mockRepo, nil, -1)
+
+	mockRepo.On("Visit", userID, "/src", mock.Anything).Return(nil).Once()
+	mockRepo.On("List", userID).Return([]frecency.DirectoryScore{
+		{Path: "/src", Rank: 9000},
+		{Path: "/tmp", Rank: 3000},
+	}, nil).Once()
+	mockRepo.On("Age", userID, 0.75).Return(nil).Once()
+
+	assert.NoError(t, service.Visit(&userID, "/src/"))
+	mockRepo.AssertExpectations(t)
+}
+
+func paths(matches []frecency.Match) []string {
+	result := make([]string, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, match.Path)
+	}
+	return result
+}
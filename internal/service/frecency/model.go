@@ -0,0 +1,22 @@
+package frecency
+
+import "time"
+
+// DirectoryScore is a directory visited by a user. Rank grows by one on
+// every visit and is aged once the ranks of the user add up to MaxRank.
+type DirectoryScore struct {
+	ID        int64     `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID     int64     `gorm:"uniqueIndex:idx_directory_scores_user_path;not null;references:users(id)" json:"user_id"`
+	Path       string    `gorm:"uniqueIndex:idx_directory_scores_user_path;not null" json:"path"`
+	Rank       float64   `gorm:"not null;default:0" json:"rank"`
+	LastAccess time.Time `gorm:"index;not null" json:"last_access"`
+}
+
+// Match is a directory matching a query, with its frecency score
+type Match struct {
+	Path  string  `json:"path"`
+	Score float64 `json:"score"`
+}
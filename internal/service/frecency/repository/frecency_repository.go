@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/database"
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository implements frecency.Repository with GORM
+type Repository struct {
+	db *database.DB
+}
+
+// New creates a new Repository
+func New(db *database.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Visit adds one to the rank of path, creating it when needed
+func (r *Repository) Visit(userID int64, path string, at time.Time) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "path"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"rank":        gorm.Expr("directory_scores.rank + 1"),
+			"last_access": at,
+			"updated_at":  at,
+		}),
+	}).Create(&frecency.DirectoryScore{
+		UserID:     userID,
+		Path:       path,
+		Rank:       1,
+		LastAccess: at,
+	}).Error
+}
+
+// List returns the visited directories of a user
+func (r *Repository) List(userID int64) ([]frecency.DirectoryScore, error) {
+	var scores []frecency.DirectoryScore
+
+	result := r.db.Where("user_id = ?", userID).Find(&scores)
+	return scores, result.Error
+}
+
+// Remove forgets a directory
+func (r *Repository) Remove(userID int64, path string) error {
+	return r.db.Where("user_id = ? AND path = ?", userID, path).Delete(&frecency.DirectoryScore{}).Error
+}
+
+// Age multiplies the ranks of a user by factor and deletes the directories
+// whose rank falls below one
+func (r *Repository) Age(userID int64, factor float64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&frecency.DirectoryScore{}).
+			Where("user_id = ?", userID).
+			Update("rank", gorm.Expr("rank * ?", factor)).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND rank < 1", userID).Delete(&frecency.DirectoryScore{}).Error
+	})
+}
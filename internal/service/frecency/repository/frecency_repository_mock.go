@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	"github.com/stretchr/testify/mock"
+)
+
+type FrecencyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *FrecencyRepositoryMock) Visit(userID int64, path string, at time.Time) error {
+	args := m.Called(userID, path, at)
+	return args.Error(0)
+}
+
+func (m *FrecencyRepositoryMock) List(userID int64) ([]frecency.DirectoryScore, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]frecency.DirectoryScore), args.Error(1)
+}
+
+func (m *FrecencyRepositoryMock) Remove(userID int64, path string) error {
+	args := m.Called(userID, path)
+	return args.Error(0)
+}
+
+func (m *FrecencyRepositoryMock) Age(userID int64, factor float64) error {
+	args := m.Called(userID, factor)
+	return args.Error(0)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+)
+
+// InMemoryRepository implements frecency.Repository with in-memory storage
+type InMemoryRepository struct {
+	mu     sync.RWMutex
+	scores map[int64]map[string]frecency.DirectoryScore // map[userID][path]DirectoryScore
+	lastID int64
+}
+
+// NewInMemory creates a new in-memory Repository
+func NewInMemory() *InMemoryRepository {
+	return &InMemoryRepository{
+		scores: make(map[int64]map[string]frecency.DirectoryScore),
+	}
+}
+
+// Visit adds one to the rank of path, creating it when needed
+func (r *InMemoryRepository) Visit(userID int64, path string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.scores[userID]; !exists {
+		r.scores[userID] = make(map[string]frecency.DirectoryScore)
+	}
+
+	score, exists := r.scores[userID][path]
+	if !exists {
+		r.lastID++
+		score = frecency.DirectoryScore{ID: r.lastID, CreatedAt: at, UserID: userID, Path: path}
+	}
+	score.Rank++
+	score.LastAccess = at
+	score.UpdatedAt = at
+	r.scores[userID][path] = score
+
+	return nil
+}
+
+// List returns the visited directories of a user
+func (r *InMemoryRepository) List(userID int64) ([]frecency.DirectoryScore, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scores := make([]frecency.DirectoryScore, 0, len(r.scores[userID]))
+	for _, score := range r.scores[userID] {
+		scores = append(scores, score)
+	}
+
+	return scores, nil
+}
+
+// Remove forgets a directory
+func (r *InMemoryRepository) Remove(userID int64, path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.scores[userID], path)
+
+	return nil
+}
+
+// Age multiplies the ranks of a user by factor and deletes the directories
+// whose rank falls below one
+func (r *InMemoryRepository) Age(userID int64, factor float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for path, score := range r.scores[userID] {
+		score.Rank *= factor
+		if score.Rank < 1 {
+			delete(r.scores[userID], path)
+			continue
+		}
+		r.scores[userID][path] = score
+	}
+
+	return nil
+}
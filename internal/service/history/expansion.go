@@ -0,0 +1,156 @@
+package history
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/pkg/inputprocessor"
+)
+
+// NeedsExpansion reports whether line may contain a history expansion, so the
+// history only has to be loaded when it does.
+func NeedsExpansion(line string) bool {
+	return strings.Contains(line, "!") || strings.HasPrefix(line, "^")
+}
+
+// Expand applies bash-style history expansion to line using commands, the
+// history ordered oldest first. It supports !!, !n, !-n, !prefix, !$, !* and a
+// leading ^old^new substitution, and reports whether anything was expanded.
+func Expand(line string, commands []string) (string, bool, error) {
+	if strings.HasPrefix(line, "^") {
+		expanded, err := substitute(line, commands)
+		return expanded, err == nil, err
+	}
+
+	var b strings.Builder
+	expanded := false
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		if c == '\\' && i+1 < len(line) && line[i+1] == '!' {
+			b.WriteByte('!')
+			i++
+			continue
+		}
+
+		if c != '!' || i+1 == len(line) || strings.ContainsRune(" \t\n=(\"", rune(line[i+1])) {
+			b.WriteByte(c)
+			continue
+		}
+
+		event, n, err := expandEvent(line[i+1:], commands)
+		if err != nil {
+			return "", false, err
+		}
+		b.WriteString(event)
+		i += n
+		expanded = true
+	}
+
+	return b.String(), expanded, nil
+}
+
+// expandEvent expands the event designator at the start of spec, which
+// follows a '!'. It returns the replacement and the length of spec consumed.
+func expandEvent(spec string, commands []string) (string, int, error) {
+	last := func() (string, error) {
+		if len(commands) == 0 {
+			return "", fmt.Errorf("event not found: !%s", spec[:1])
+		}
+		return commands[len(commands)-1], nil
+	}
+
+	switch spec[0] {
+	case '!':
+		command, err := last()
+		return command, 1, err
+
+	case '$':
+		command, err := last()
+		if err != nil {
+			return "", 0, err
+		}
+		words := commandWords(command)
+		return words[len(words)-1], 1, nil
+
+	case '*':
+		command, err := last()
+		if err != nil {
+			return "", 0, err
+		}
+		return strings.Join(commandWords(command)[1:], " "), 1, nil
+	}
+
+	// !n and !-n
+	end := 0
+	if spec[0] == '-' {
+		end++
+	}
+	for end < len(spec) && spec[end] >= '0' && spec[end] <= '9' {
+		end++
+	}
+	if end > 0 && spec[:end] != "-" {
+		n, _ := strconv.Atoi(spec[:end])
+		index := n - 1
+		if n < 0 {
+			index = len(commands) + n
+		}
+		if n == 0 || index < 0 || index >= len(commands) {
+			return "", 0, fmt.Errorf("event not found: !%s", spec[:end])
+		}
+		return commands[index], end, nil
+	}
+
+	// !prefix runs up to the next blank
+	end = strings.IndexAny(spec, " \t\n")
+	if end < 0 {
+		end = len(spec)
+	}
+	prefix := spec[:end]
+	for i := len(commands) - 1; i >= 0; i-- {
+		if strings.HasPrefix(commands[i], prefix) {
+			return commands[i], end, nil
+		}
+	}
+	return "", 0, fmt.Errorf("event not found: !%s", prefix)
+}
+
+// substitute handles ^old^new[^], which reruns the last command with the
+// first occurrence of old replaced by new.
+func substitute(line string, commands []string) (string, error) {
+	parts := strings.SplitN(line[1:], "^", 3)
+	if len(parts) < 2 || parts[0] == "" {
+		return "", fmt.Errorf("bad substitution: %s", line)
+	}
+	if len(commands) == 0 {
+		return "", fmt.Errorf("event not found: %s", line)
+	}
+
+	command := commands[len(commands)-1]
+	if !strings.Contains(command, parts[0]) {
+		return "", fmt.Errorf("substitution failed: %s", line)
+	}
+
+	expanded := strings.Replace(command, parts[0], parts[1], 1)
+	if len(parts) == 3 {
+		expanded += parts[2]
+	}
+	return expanded, nil
+}
+
+// commandWords splits a command into its words as typed, keeping quotes
+func commandWords(command string) []string {
+	var words []string
+	for _, tok := range inputprocessor.Tokenize(command) {
+		if tok.Arg == len(words) {
+			words = append(words, "")
+		}
+		words[tok.Arg] += tok.Text
+	}
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	return words
+}
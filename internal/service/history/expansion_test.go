@@ -0,0 +1,61 @@
+package history_test
+
+import (
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	commands := []string{
+		"cd /tmp",
+		"git commit -m \"first try\"",
+		"ls -la /var/log",
+	}
+
+	cases := []struct {
+		name     string
+		line     string
+		expected string
+		expanded bool
+		err      string
+	}{
+		{name: "no expansion", line: "echo hi", expected: "echo hi"},
+		{name: "last command", line: "!!", expected: "ls -la /var/log", expanded: true},
+		{name: "last command inside a line", line: "sudo !!", expected: "sudo ls -la /var/log", expanded: true},
+		{name: "absolute number", line: "!1", expected: "cd /tmp", expanded: true},
+		{name: "relative number", line: "!-2", expected: "git commit -m \"first try\"", expanded: true},
+		{name: "prefix", line: "!git", expected: "git commit -m \"first try\"", expanded: true},
+		{name: "last word", line: "cd !$", expected: "cd /var/log", expanded: true},
+		{name: "all arguments", line: "echo !*", expected: "echo -la /var/log", expanded: true},
+		{name: "substitution", line: "^la^l", expected: "ls -l /var/log", expanded: true},
+		{name: "substitution with suffix", line: "^/var/log^/tmp^ | wc", expected: "ls -la /tmp | wc", expanded: true},
+		{name: "lone exclamation mark", line: "echo hi !", expected: "echo hi !"},
+		{name: "exclamation before space", line: "echo ! x", expected: "echo ! x"},
+		{name: "exclamation before closing quote", line: `echo "wow!"`, expected: `echo "wow!"`},
+		{name: "escaped exclamation mark", line: `echo \!!`, expected: "echo !!"},
+		{name: "unknown number", line: "!42", err: "event not found: !42"},
+		{name: "unknown prefix", line: "!docker", err: "event not found: !docker"},
+		{name: "failed substitution", line: "^foo^bar", err: "substitution failed: ^foo^bar"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			expanded, ok, err := history.Expand(tc.line, commands)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, expanded)
+			assert.Equal(t, tc.expanded, ok)
+		})
+	}
+
+	t.Run("empty history", func(t *testing.T) {
+		_, _, err := history.Expand("!!", nil)
+		assert.EqualError(t, err, "event not found: !!")
+	})
+}
@@ -1,15 +1,31 @@
 package history
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 )
 
 // HistoryRepository defines operations on command history
 type HistoryRepository interface {
 	SaveCommand(command *CommandHistory) error
+	// SaveCommands saves several commands using batched inserts
+	SaveCommands(commands []CommandHistory) error
 	GetUserHistory(userID int64, limit int) ([]CommandHistory, error)
 	GetUserCommandStats(userID int64) ([]CommandStats, error)
 	ClearUserHistory(userID int64) error
+	// FindByPrefix returns the newest commands that start with prefix and are
+	// longer than it. An empty workingDir matches every directory.
+	FindByPrefix(userID int64, prefix, workingDir string, limit int) ([]CommandHistory, error)
+	// Search returns one page of the entries matching filter, newest first
+	Search(filter SearchFilter) ([]CommandHistory, error)
+	// Prune permanently deletes the entries of a user that policy does not
+	// keep and returns how many were deleted
+	Prune(userID int64, policy RetentionPolicy) (int64, error)
 }
 
 // Service provides high-level functionality for the shell
@@ -17,6 +33,11 @@ type Service struct {
 	historyRepo       HistoryRepository
 	guestHistoryCache HistoryRepository
 	guestID           int64
+	redactor          *Redactor
+	hostname          string
+	retention         RetentionPolicy
+	pruning           sync.Map // pruneKey of the prunes running
+	prunes            sync.WaitGroup
 }
 
 // New creates a new Service instance
@@ -25,29 +46,106 @@ func New(
 	guestHistoryCache HistoryRepository,
 	guestID int64,
 ) *Service {
+	// the default rules always compile
+	redactor, _ := NewRedactor(RedactionConfig{})
+	hostname, _ := os.Hostname()
+
 	return &Service{
 		historyRepo:       historyRepo,
 		guestHistoryCache: guestHistoryCache,
 		guestID:           guestID,
+		redactor:          redactor,
+		hostname:          hostname,
 	}
 }
 
-// SaveCommandHistory saves a command to history
-func (s *Service) SaveCommandHistory(userID *int64, command string) error {
-	history := &CommandHistory{
-		Command:   command,
-		CreatedAt: time.Now(),
+// SetRedactor replaces the redactor applied to commands before they are saved
+func (s *Service) SetRedactor(redactor *Redactor) {
+	s.redactor = redactor
+}
+
+// SetRetention sets the retention policy applied in the background after
+// each save. The zero policy keeps everything.
+func (s *Service) SetRetention(policy RetentionPolicy) {
+	s.retention = policy
+}
+
+// SaveCommandHistory saves a finished command to history. Secrets are
+// redacted first and ignored commands, or ones starting with a space, are not
+// saved. The duration and hostname are filled in when missing.
+func (s *Service) SaveCommandHistory(userID *int64, record CommandHistory) error {
+	command, ok := s.redactor.Redact(record.Command)
+	if !ok {
+		return nil
+	}
+
+	history := record
+	history.Command = command
+	history.Pipeline = s.redactor.RedactPipeline(record.Pipeline)
+	history.CreatedAt = time.Now()
+	if history.Duration == 0 && !history.StartedAt.IsZero() && !history.FinishedAt.IsZero() {
+		history.Duration = history.FinishedAt.Sub(history.StartedAt)
+	}
+	if history.Hostname == "" {
+		history.Hostname = s.hostname
 	}
 
+	repo := s.historyRepo
 	if userID == nil {
-		history.UserID = s.guestID
+		repo, history.UserID = s.guestHistoryCache, s.guestID
+	} else {
+		history.UserID = *userID
+	}
+
+	if err := repo.SaveCommand(&history); err != nil {
+		return err
+	}
+
+	s.pruneInBackground(repo, history.UserID)
 
-		return s.guestHistoryCache.SaveCommand(history)
+	return nil
+}
+
+// pruneKey identifies the history of a user in a repository
+type pruneKey struct {
+	repo   HistoryRepository
+	userID int64
+}
+
+// pruneInBackground applies the retention policy without blocking the
+// caller. Saves made while the same history is being pruned do not start
+// another prune, and errors are dropped since the next save prunes again.
+func (s *Service) pruneInBackground(repo HistoryRepository, userID int64) {
+	if !s.retention.Enabled() {
+		return
+	}
+	key := pruneKey{repo: repo, userID: userID}
+	if _, running := s.pruning.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	s.prunes.Add(1)
+	go func() {
+		defer s.prunes.Done()
+		defer s.pruning.Delete(key)
+
+		_, _ = repo.Prune(userID, s.retention)
+	}()
+}
+
+// PruneCommandHistory applies the retention policy to the history of a user
+// right away and returns the number of deleted entries
+func (s *Service) PruneCommandHistory(userID *int64) (int64, error) {
+	if userID == nil {
+		return s.guestHistoryCache.Prune(s.guestID, s.retention)
 	}
 
-	history.UserID = *userID
+	return s.historyRepo.Prune(*userID, s.retention)
+}
 
-	return s.historyRepo.SaveCommand(history)
+// Wait blocks until background pruning has finished
+func (s *Service) Wait() {
+	s.prunes.Wait()
 }
 
 // GetCommandHistory retrieves command history for a user
@@ -59,6 +157,190 @@ func (s *Service) GetCommandHistoryStats(userID *int64, limit int) ([]CommandSta
 	return s.historyRepo.GetUserCommandStats(*userID)
 }
 
+// GetCommandHistory returns the whole command history of a user, oldest first.
+// Entry i is number i+1 in listings and in !n expansions.
+func (s *Service) GetCommandHistory(userID *int64) ([]CommandHistory, error) {
+	repo, id := s.historyRepo, int64(0)
+	if userID == nil {
+		repo, id = s.guestHistoryCache, s.guestID
+	} else {
+		id = *userID
+	}
+
+	entries, err := repo.GetUserHistory(id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// the repository returns the newest first
+	chronological := make([]CommandHistory, len(entries))
+	for i, entry := range entries {
+		chronological[len(entries)-1-i] = entry
+	}
+
+	return chronological, nil
+}
+
+// ExpandHistory applies history expansion (!!, !n, !-n, !prefix, !$, !* and
+// ^old^new) to line. It reports whether the line was changed.
+func (s *Service) ExpandHistory(userID *int64, line string) (string, bool, error) {
+	if !NeedsExpansion(line) {
+		return line, false, nil
+	}
+
+	entries, err := s.GetCommandHistory(userID)
+	if err != nil {
+		return "", false, err
+	}
+
+	commands := make([]string, len(entries))
+	for i, entry := range entries {
+		commands[i] = entry.Command
+	}
+
+	return Expand(line, commands)
+}
+
+// ImportCommandHistory adds entries parsed from another shell's history
+// file, keeping their timestamps. Entries already in the history, or repeated
+// in the import, are skipped, as are ignored commands; secrets are redacted.
+// It returns the number of imported and skipped entries.
+func (s *Service) ImportCommandHistory(userID *int64, entries []CommandHistory) (int, int, error) {
+	repo, id := s.historyRepo, int64(0)
+	if userID == nil {
+		repo, id = s.guestHistoryCache, s.guestID
+	} else {
+		id = *userID
+	}
+
+	existing, err := repo.GetUserHistory(id, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// timed entries are duplicates of the same command run at the same second,
+	// untimed ones of the same command run at any time
+	type key struct {
+		command string
+		when    int64
+	}
+	seen := make(map[key]bool, len(existing))
+	seenCommands := make(map[string]bool, len(existing))
+	for _, entry := range existing {
+		seen[key{entry.Command, entryTime(entry).Unix()}] = true
+		seenCommands[entry.Command] = true
+	}
+
+	// untimed entries keep their file order just before the import time
+	now := time.Now()
+	base := now.Add(-time.Duration(len(entries)) * time.Microsecond)
+
+	var imported []CommandHistory
+	for i, entry := range entries {
+		command, ok := s.redactor.Redact(entry.Command)
+		if !ok {
+			continue
+		}
+
+		if entry.StartedAt.IsZero() {
+			if seenCommands[command] {
+				continue
+			}
+			entry.CreatedAt = base.Add(time.Duration(i) * time.Microsecond)
+		} else {
+			k := key{command, entry.StartedAt.Unix()}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			entry.CreatedAt = entry.StartedAt
+		}
+		seenCommands[command] = true
+
+		entry.ID = 0
+		entry.UserID = id
+		entry.Command = command
+		entry.Pipeline = s.redactor.RedactPipeline(entry.Pipeline)
+		imported = append(imported, entry)
+	}
+
+	if len(imported) > 0 {
+		if err := repo.SaveCommands(imported); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return len(imported), len(entries) - len(imported), nil
+}
+
+// ExportCommandHistory writes the whole history of a user, oldest first, in
+// one of the formats understood by WriteHistory
+func (s *Service) ExportCommandHistory(userID *int64, w io.Writer, format string) error {
+	entries, err := s.GetCommandHistory(userID)
+	if err != nil {
+		return err
+	}
+
+	return WriteHistory(w, format, entries)
+}
+
+// SearchCommandHistory returns one page of the history entries matching
+// filter, newest first. userID selects whose history is searched, nil meaning
+// the guest; it overrides filter.UserID.
+func (s *Service) SearchCommandHistory(userID *int64, filter SearchFilter) ([]CommandHistory, error) {
+	if _, err := regexp.Compile(filter.Pattern); err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	if filter.Status != "" && filter.Status != StatusSucceeded && filter.Status != StatusFailed {
+		return nil, fmt.Errorf("invalid status: %s", filter.Status)
+	}
+
+	if userID == nil {
+		filter.UserID = s.guestID
+		return s.guestHistoryCache.Search(filter)
+	}
+
+	filter.UserID = *userID
+	return s.historyRepo.Search(filter)
+}
+
+// GuestHistoryCount returns the number of commands typed as guest
+func (s *Service) GuestHistoryCount() (int, error) {
+	entries, err := s.guestHistoryCache.GetUserHistory(s.guestID, 0)
+	return len(entries), err
+}
+
+// MergeGuestHistory moves the commands typed as guest into the history of a
+// user, keeping their timestamps, and clears the guest history. It returns the
+// number of moved commands.
+func (s *Service) MergeGuestHistory(userID int64) (int, error) {
+	entries, err := s.guestHistoryCache.GetUserHistory(s.guestID, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	merged := make([]CommandHistory, len(entries))
+	for i, entry := range entries {
+		entry.ID = 0
+		entry.UserID = userID
+		merged[i] = entry
+	}
+
+	if len(merged) > 0 {
+		if err := s.historyRepo.SaveCommands(merged); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := s.guestHistoryCache.ClearUserHistory(s.guestID); err != nil {
+		return 0, err
+	}
+
+	s.pruneInBackground(s.historyRepo, userID)
+
+	return len(merged), nil
+}
+
 // ClearCommandHistory clears command history for a user
 func (s *Service) ClearCommandHistory(userID *int64) error {
 	if userID == nil {
@@ -67,3 +349,31 @@ func (s *Service) ClearCommandHistory(userID *int64) error {
 
 	return s.historyRepo.ClearUserHistory(*userID)
 }
+
+// SuggestCommand returns the most recent command that extends prefix,
+// preferring commands that were run in workingDir. It returns an empty
+// string when nothing matches.
+func (s *Service) SuggestCommand(userID *int64, prefix, workingDir string) (string, error) {
+	if strings.TrimSpace(prefix) == "" {
+		return "", nil
+	}
+
+	repo, id := s.historyRepo, int64(0)
+	if userID == nil {
+		repo, id = s.guestHistoryCache, s.guestID
+	} else {
+		id = *userID
+	}
+
+	for _, dir := range []string{workingDir, ""} {
+		matches, err := repo.FindByPrefix(id, prefix, dir, 1)
+		if err != nil {
+			return "", err
+		}
+		if len(matches) > 0 {
+			return matches[0].Command, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,255 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats understood by ParseHistory and WriteHistory
+const (
+	FormatBash = "bash"
+	FormatZsh  = "zsh"
+	FormatFish = "fish"
+	FormatJSON = "json"
+)
+
+// maxHistoryLine is the longest history file line accepted when importing
+const maxHistoryLine = 1024 * 1024
+
+// ParseHistory reads a history file written by bash (optionally with
+// HISTTIMEFORMAT timestamps), zsh (plain or extended history) or fish.
+// Entries without a timestamp have a zero StartedAt.
+func ParseHistory(r io.Reader, format string) ([]CommandHistory, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxHistoryLine)
+
+	var entries []CommandHistory
+	switch format {
+	case FormatBash:
+		entries = parseBashHistory(scanner)
+	case FormatZsh:
+		entries = parseZshHistory(scanner)
+	case FormatFish:
+		entries = parseFishHistory(scanner)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// parseBashHistory parses one command per line. A "#<epoch>" line sets the
+// time of the command that follows it.
+func parseBashHistory(scanner *bufio.Scanner) []CommandHistory {
+	var entries []CommandHistory
+	var when time.Time
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if ts, ok := strings.CutPrefix(line, "#"); ok {
+			if sec, err := strconv.ParseInt(ts, 10, 64); err == nil {
+				when = time.Unix(sec, 0)
+				continue
+			}
+		}
+
+		if strings.TrimSpace(line) != "" {
+			entries = append(entries, CommandHistory{Command: line, StartedAt: when, FinishedAt: when})
+		}
+		when = time.Time{}
+	}
+
+	return entries
+}
+
+// parseZshHistory parses zsh history, where extended entries look like
+// ": <start>:<elapsed>;<command>" and multi-line commands end their lines
+// with a backslash
+func parseZshHistory(scanner *bufio.Scanner) []CommandHistory {
+	var entries []CommandHistory
+	var command strings.Builder
+	continued := false
+
+	for scanner.Scan() {
+		line := unmetafy(scanner.Text())
+		if continued {
+			command.WriteByte('\n')
+		} else {
+			command.Reset()
+		}
+
+		if body, ok := strings.CutSuffix(line, "\\"); ok {
+			command.WriteString(body)
+			continued = true
+			continue
+		}
+		command.WriteString(line)
+		continued = false
+
+		if entry, ok := parseZshEntry(command.String()); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	if continued {
+		if entry, ok := parseZshEntry(command.String()); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// parseZshEntry parses one possibly extended zsh history entry
+func parseZshEntry(text string) (CommandHistory, bool) {
+	if rest, ok := strings.CutPrefix(text, ": "); ok {
+		if meta, command, ok := strings.Cut(rest, ";"); ok {
+			start, elapsed, _ := strings.Cut(meta, ":")
+			sec, errStart := strconv.ParseInt(strings.TrimSpace(start), 10, 64)
+			dur, errElapsed := strconv.ParseInt(strings.TrimSpace(elapsed), 10, 64)
+			if errStart == nil && errElapsed == nil {
+				startedAt := time.Unix(sec, 0)
+				duration := time.Duration(dur) * time.Second
+				return CommandHistory{
+					Command:    command,
+					StartedAt:  startedAt,
+					FinishedAt: startedAt.Add(duration),
+					Duration:   duration,
+				}, strings.TrimSpace(command) != ""
+			}
+		}
+	}
+
+	return CommandHistory{Command: text}, strings.TrimSpace(text) != ""
+}
+
+// zshMeta marks a metafied byte in zsh history files
+const zshMeta = 0x83
+
+// unmetafy decodes the bytes zsh escapes in history files
+func unmetafy(line string) string {
+	if strings.IndexByte(line, zshMeta) < 0 {
+		return line
+	}
+
+	b := make([]byte, 0, len(line))
+	for i := 0; i < len(line); i++ {
+		if line[i] == zshMeta && i+1 < len(line) {
+			i++
+			b = append(b, line[i]^32)
+			continue
+		}
+		b = append(b, line[i])
+	}
+	return string(b)
+}
+
+// parseFishHistory parses the YAML-like fish history format, where each
+// entry starts with a "- cmd: <command>" line followed by indented "when:"
+// and "paths:" keys
+func parseFishHistory(scanner *bufio.Scanner) []CommandHistory {
+	var entries []CommandHistory
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if cmd, ok := strings.CutPrefix(line, "- cmd: "); ok {
+			entries = append(entries, CommandHistory{Command: unescapeFish(cmd)})
+			continue
+		}
+
+		if when, ok := strings.CutPrefix(line, "  when: "); ok && len(entries) > 0 {
+			if sec, err := strconv.ParseInt(strings.TrimSpace(when), 10, 64); err == nil {
+				last := &entries[len(entries)-1]
+				last.StartedAt = time.Unix(sec, 0)
+				last.FinishedAt = last.StartedAt
+			}
+		}
+	}
+
+	// commands made only of whitespace are dropped like in the other formats
+	result := entries[:0]
+	for _, entry := range entries {
+		if strings.TrimSpace(entry.Command) != "" {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
+
+// unescapeFish decodes the \\ and \n escapes fish writes in history commands
+func unescapeFish(cmd string) string {
+	if !strings.Contains(cmd, "\\") {
+		return cmd
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(cmd); i++ {
+		if cmd[i] == '\\' && i+1 < len(cmd) {
+			switch cmd[i+1] {
+			case 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			case '\\':
+				b.WriteByte('\\')
+				i++
+				continue
+			}
+		}
+		b.WriteByte(cmd[i])
+	}
+	return b.String()
+}
+
+// WriteHistory writes entries, oldest first, as JSON or as a bash or zsh
+// (extended) history file
+func WriteHistory(w io.Writer, format string, entries []CommandHistory) error {
+	switch format {
+	case FormatJSON:
+		if entries == nil {
+			entries = []CommandHistory{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+
+	case FormatBash:
+		bw := bufio.NewWriter(w)
+		for _, entry := range entries {
+			if startedAt := entryTime(entry); !startedAt.IsZero() {
+				fmt.Fprintf(bw, "#%d\n", startedAt.Unix())
+			}
+			fmt.Fprintln(bw, entry.Command)
+		}
+		return bw.Flush()
+
+	case FormatZsh:
+		bw := bufio.NewWriter(w)
+		for _, entry := range entries {
+			command := strings.ReplaceAll(entry.Command, "\n", "\\\n")
+			fmt.Fprintf(bw, ": %d:%d;%s\n", entryTime(entry).Unix(), int64(entry.Duration/time.Second), command)
+		}
+		return bw.Flush()
+
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// entryTime returns when the command was run, falling back to when it was saved
+func entryTime(entry CommandHistory) time.Time {
+	if entry.StartedAt.IsZero() {
+		return entry.CreatedAt
+	}
+	return entry.StartedAt
+}
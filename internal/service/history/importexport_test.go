@@ -0,0 +1,129 @@
+package history_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHistory(t *testing.T) {
+	at := func(sec int64) time.Time { return time.Unix(sec, 0) }
+
+	cases := []struct {
+		name     string
+		format   string
+		input    string
+		expected []history.CommandHistory
+		err      string
+	}{
+		{
+			name:   "bash with timestamps",
+			format: history.FormatBash,
+			input:  "ls\n#1700000000\ngit status\n\n# comment\n",
+			expected: []history.CommandHistory{
+				{Command: "ls"},
+				{Command: "git status", StartedAt: at(1700000000), FinishedAt: at(1700000000)},
+				{Command: "# comment"},
+			},
+		},
+		{
+			name:   "zsh extended history",
+			format: history.FormatZsh,
+			input:  ": 1700000000:3;make test\n: 1700000010:0;for f in *; do\\\necho $f\\\ndone\nplain\n",
+			expected: []history.CommandHistory{
+				{Command: "make test", StartedAt: at(1700000000), FinishedAt: at(1700000003), Duration: 3 * time.Second},
+				{Command: "for f in *; do\necho $f\ndone", StartedAt: at(1700000010), FinishedAt: at(1700000010)},
+				{Command: "plain"},
+			},
+		},
+		{
+			name:   "zsh metafied bytes",
+			format: history.FormatZsh,
+			input:  ": 1700000000:0;echo caf\xc3\x83\xa9\n",
+			expected: []history.CommandHistory{
+				{Command: "echo caf\xc3\x89", StartedAt: at(1700000000), FinishedAt: at(1700000000)},
+			},
+		},
+		{
+			name:   "fish",
+			format: history.FormatFish,
+			input:  "- cmd: echo a\\\\b\n  when: 1700000000\n- cmd: printf 'x\\ny'\n  when: 1700000005\n  paths:\n    - x\n",
+			expected: []history.CommandHistory{
+				{Command: `echo a\b`, StartedAt: at(1700000000), FinishedAt: at(1700000000)},
+				{Command: "printf 'x\ny'", StartedAt: at(1700000005), FinishedAt: at(1700000005)},
+			},
+		},
+		{
+			name:   "unsupported format",
+			format: "csh",
+			err:    "unsupported import format: csh",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := history.ParseHistory(strings.NewReader(tc.input), tc.format)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, entries)
+		})
+	}
+}
+
+func TestWriteHistory(t *testing.T) {
+	startedAt := time.Unix(1700000000, 0)
+	entries := []history.CommandHistory{
+		{Command: "make test", StartedAt: startedAt, Duration: 3 * time.Second},
+		{Command: "echo a\necho b", StartedAt: startedAt.Add(time.Minute)},
+	}
+
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format:   history.FormatBash,
+			expected: "#1700000000\nmake test\n#1700000060\necho a\necho b\n",
+		},
+		{
+			format:   history.FormatZsh,
+			expected: ": 1700000000:3;make test\n: 1700000060:0;echo a\\\necho b\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, history.WriteHistory(&out, tc.format, entries))
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+
+	t.Run("zsh round trip", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, history.WriteHistory(&out, history.FormatZsh, entries))
+
+		parsed, err := history.ParseHistory(&out, history.FormatZsh)
+		assert.NoError(t, err)
+		assert.Len(t, parsed, 2)
+		assert.Equal(t, "echo a\necho b", parsed[1].Command)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, history.WriteHistory(&out, history.FormatJSON, nil))
+		assert.Equal(t, "[]\n", out.String())
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		assert.EqualError(t, history.WriteHistory(&bytes.Buffer{}, "csv", entries), "unsupported export format: csv")
+	})
+}
@@ -13,8 +13,24 @@ type CommandHistory struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
-	Command string `gorm:"not null" json:"command"`
-	UserID  int64  `gorm:"index;not null;references:users(id)" json:"user_id"`
+	Command    string `gorm:"not null;index:idx_command_history_prefix,priority:2,expression:command text_pattern_ops" json:"command"`
+	UserID     int64  `gorm:"index;index:idx_command_history_prefix,priority:1;not null;references:users(id)" json:"user_id"`
+	WorkingDir string `gorm:"index" json:"working_dir"`
+
+	ExitCode   int             `gorm:"index;not null;default:0" json:"exit_code"`
+	StartedAt  time.Time       `gorm:"index" json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Duration   time.Duration   `gorm:"not null;default:0" json:"duration"`
+	Hostname   string          `gorm:"index;not null;default:''" json:"hostname"`
+	SessionID  string          `gorm:"index;not null;default:''" json:"session_id"`
+	Pipeline   []PipelineStage `gorm:"type:jsonb;serializer:json" json:"pipeline"`
+}
+
+// PipelineStage is one command of a pipeline as it was executed
+type PipelineStage struct {
+	Name         string   `json:"name"`
+	Args         []string `json:"args"`
+	Redirections []string `json:"redirections,omitempty"`
 }
 
 // CommandStats represents aggregated command history
@@ -22,3 +38,41 @@ type CommandStats struct {
 	Command string `json:"command"`
 	Count   int64  `json:"count"`
 }
+
+// Statuses accepted by SearchFilter.Status
+const (
+	StatusSucceeded = "success"
+	StatusFailed    = "failed"
+)
+
+// SearchFilter selects history entries of a user. Zero fields do not filter.
+type SearchFilter struct {
+	UserID     int64
+	Pattern    string // regular expression matched against the command, by PostgreSQL for logged in users
+	Since      time.Time
+	Until      time.Time
+	WorkingDir string
+	Status     string // StatusSucceeded or StatusFailed
+	Offset     int
+	Limit      int
+}
+
+// RetentionPolicy limits the history kept for each user. Zero fields do not
+// limit anything.
+type RetentionPolicy struct {
+	MaxEntries       int           // number of newest entries kept
+	MaxAge           time.Duration // entries saved longer ago are removed
+	DedupConsecutive bool          // only the last of consecutive identical commands is kept
+}
+
+// Enabled reports whether the policy removes anything
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxEntries > 0 || p.MaxAge > 0 || p.DedupConsecutive
+}
+
+// Guest history merge modes, applied when a user logs in
+const (
+	MergeGuestAsk    = "ask"
+	MergeGuestAlways = "always"
+	MergeGuestNever  = "never"
+)
@@ -0,0 +1,209 @@
+package history
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/pkg/inputprocessor"
+)
+
+// Redacted replaces secrets in saved commands
+const Redacted = "********"
+
+// DefaultRedactArguments lists, per command, the 1-based positions of
+// arguments that are always redacted.
+var DefaultRedactArguments = map[string][]int{
+	"login":   {2},
+	"adduser": {2},
+}
+
+// DefaultRedactPatterns match common secrets. When a pattern has capture
+// groups only the groups are redacted, otherwise the whole match is.
+var DefaultRedactPatterns = []string{
+	`(?i)\b[A-Z0-9_]*(?:SECRET|TOKEN|PASSWORD|PASSWD|API_?KEY)[A-Z0-9_]*=("[^"]*"|\S+)`,
+	`(?i)\bBearer\s+([A-Za-z0-9._~+/=-]+)`,
+	`(?i)--(?:password|passwd|token|secret)[= ]("[^"]*"|\S+)`,
+	`\bAKIA[0-9A-Z]{16}\b`,
+}
+
+// RedactionConfig configures the redaction of commands before they are saved.
+// Its rules are added to the defaults.
+type RedactionConfig struct {
+	// Arguments maps a command name to the 1-based positions of its secret arguments
+	Arguments map[string][]int
+	// Patterns are regular expressions matching secrets anywhere in the command
+	Patterns []string
+	// Ignore lists HISTIGNORE-style glob patterns; matching commands are not saved
+	Ignore []string
+}
+
+// Redactor removes secrets from commands and decides which commands are not
+// recorded at all.
+type Redactor struct {
+	arguments map[string][]int
+	patterns  []*regexp.Regexp
+	ignore    []*regexp.Regexp
+}
+
+// NewRedactor creates a redactor from the default rules and cfg
+func NewRedactor(cfg RedactionConfig) (*Redactor, error) {
+	r := &Redactor{
+		arguments: make(map[string][]int),
+	}
+
+	for name, positions := range DefaultRedactArguments {
+		r.arguments[name] = positions
+	}
+	for name, positions := range cfg.Arguments {
+		r.arguments[name] = positions
+	}
+
+	for _, pattern := range append(append([]string(nil), DefaultRedactPatterns...), cfg.Patterns...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	for _, glob := range cfg.Ignore {
+		r.ignore = append(r.ignore, globToRegexp(glob))
+	}
+
+	return r, nil
+}
+
+// Redact returns the command with its secrets replaced. It reports false when
+// the command must not be recorded: it starts with a space or matches an
+// ignore pattern.
+func (r *Redactor) Redact(command string) (string, bool) {
+	if strings.HasPrefix(command, " ") {
+		return "", false
+	}
+
+	for _, re := range r.ignore {
+		if re.MatchString(command) {
+			return "", false
+		}
+	}
+
+	command = r.redactArguments(command)
+
+	for _, re := range r.patterns {
+		command = redactMatches(re, command)
+	}
+
+	return command, true
+}
+
+// RedactPipeline returns a copy of the pipeline with secret arguments redacted
+// by the per-command rules and the patterns.
+func (r *Redactor) RedactPipeline(pipeline []PipelineStage) []PipelineStage {
+	if pipeline == nil {
+		return nil
+	}
+
+	redacted := make([]PipelineStage, len(pipeline))
+	for i, stage := range pipeline {
+		stage.Args = append([]string(nil), stage.Args...)
+		for _, position := range r.arguments[stage.Name] {
+			if position >= 1 && position <= len(stage.Args) {
+				stage.Args[position-1] = Redacted
+			}
+		}
+		for j := range stage.Args {
+			for _, re := range r.patterns {
+				stage.Args[j] = redactMatches(re, stage.Args[j])
+			}
+		}
+		redacted[i] = stage
+	}
+
+	return redacted
+}
+
+// redactArguments replaces the arguments listed for the command
+func (r *Redactor) redactArguments(command string) string {
+	tokens := inputprocessor.Tokenize(command)
+	if len(tokens) == 0 {
+		return command
+	}
+
+	var name strings.Builder
+	for _, tok := range tokens {
+		if tok.Arg != 0 {
+			break
+		}
+		name.WriteString(tok.Text)
+	}
+
+	positions := r.arguments[name.String()]
+	if len(positions) == 0 {
+		return command
+	}
+
+	secret := make(map[int]bool, len(positions))
+	for _, position := range positions {
+		secret[position] = true
+	}
+
+	// replace the spans from the end so earlier offsets stay valid
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if !secret[tokens[i].Arg] {
+			continue
+		}
+
+		start, end := tokens[i].Start, tokens[i].End
+		for i > 0 && tokens[i-1].Arg == tokens[i].Arg {
+			i--
+			start = tokens[i].Start
+		}
+		command = command[:start] + Redacted + command[end:]
+	}
+
+	return command
+}
+
+// redactMatches replaces the capture groups of each match, or the whole match
+// when the pattern has no groups.
+func redactMatches(re *regexp.Regexp, command string) string {
+	matches := re.FindAllStringSubmatchIndex(command, -1)
+
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		if len(m) == 2 {
+			command = command[:m[0]] + Redacted + command[m[1]:]
+			continue
+		}
+
+		for g := len(m)/2 - 1; g >= 1; g-- {
+			start, end := m[2*g], m[2*g+1]
+			if start < 0 {
+				continue
+			}
+			command = command[:start] + Redacted + command[end:]
+		}
+	}
+
+	return command
+}
+
+// globToRegexp converts a HISTIGNORE glob, where * matches any text and ?
+// a single character, to an anchored regular expression.
+func globToRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
@@ -1,8 +1,15 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/Ali-Farhadnia/goshell/internal/database"
 	"github.com/Ali-Farhadnia/goshell/internal/service/history"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
 )
 
 // repository implements the Repository interface
@@ -20,6 +27,14 @@ func (r *Repository) SaveCommand(command *history.CommandHistory) error {
 	return r.db.Create(command).Error
 }
 
+// importBatchSize is the number of rows inserted per statement by SaveCommands
+const importBatchSize = 500
+
+// SaveCommands saves several commands using batched inserts
+func (r *Repository) SaveCommands(commands []history.CommandHistory) error {
+	return r.db.CreateInBatches(commands, importBatchSize).Error
+}
+
 // GetUserHistory gets command history for a user
 func (r *Repository) GetUserHistory(userID int64, limit int) ([]history.CommandHistory, error) {
 	var history []history.CommandHistory
@@ -51,3 +66,123 @@ func (r *Repository) GetUserCommandStats(userID int64) ([]history.CommandStats,
 
 	return stats, result.Error
 }
+
+// FindByPrefix returns the newest commands that start with prefix and are longer than it
+func (r *Repository) FindByPrefix(userID int64, prefix, workingDir string, limit int) ([]history.CommandHistory, error) {
+	var history []history.CommandHistory
+
+	// "_%" requires at least one more character than the prefix
+	query := r.db.Where("user_id = ? AND command LIKE ?", userID, escapeLike(prefix)+"_%")
+	if workingDir != "" {
+		query = query.Where("working_dir = ?", workingDir)
+	}
+	query = query.Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	result := query.Find(&history)
+	return history, result.Error
+}
+
+// Search returns one page of the entries matching filter, newest first
+func (r *Repository) Search(filter history.SearchFilter) ([]history.CommandHistory, error) {
+	var entries []history.CommandHistory
+
+	query := r.db.Where("user_id = ?", filter.UserID)
+	if filter.Pattern != "" {
+		query = query.Where("command ~ ?", filter.Pattern)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("started_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("started_at < ?", filter.Until)
+	}
+	if filter.WorkingDir != "" {
+		query = query.Where("working_dir = ?", filter.WorkingDir)
+	}
+	switch filter.Status {
+	case history.StatusSucceeded:
+		query = query.Where("exit_code = 0")
+	case history.StatusFailed:
+		query = query.Where("exit_code <> 0")
+	}
+
+	query = query.Order("started_at DESC, id DESC").Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	result := query.Find(&entries)
+	return entries, invalidPatternError(result.Error)
+}
+
+// invalidRegularExpression is the SQLSTATE of a pattern PostgreSQL rejects
+const invalidRegularExpression = "2201B"
+
+// invalidPatternError reports a pattern rejected by PostgreSQL like one
+// rejected by regexp. PostgreSQL matches with its own regular expressions,
+// which differ from Go's in some features, such as named groups.
+func invalidPatternError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == invalidRegularExpression {
+		return fmt.Errorf("invalid pattern: %s", pgErr.Message)
+	}
+	return err
+}
+
+// Prune permanently deletes the entries of a user that policy does not keep,
+// together with entries removed earlier by ClearUserHistory
+func (r *Repository) Prune(userID int64, policy history.RetentionPolicy) (int64, error) {
+	var deleted int64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		del := func(query *gorm.DB) error {
+			result := query.Delete(&history.CommandHistory{})
+			deleted += result.RowsAffected
+			return result.Error
+		}
+
+		if err := del(tx.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID)); err != nil {
+			return err
+		}
+
+		if policy.DedupConsecutive {
+			// an entry is dropped when the next entry of the user repeats it
+			ordered := tx.Model(&history.CommandHistory{}).
+				Select("id, command, LEAD(command) OVER (ORDER BY created_at, id) AS next_command").
+				Where("user_id = ?", userID)
+			repeated := tx.Table("(?) AS ordered", ordered).Select("id").Where("command = next_command")
+			if err := del(tx.Unscoped().Where("id IN (?)", repeated)); err != nil {
+				return err
+			}
+		}
+
+		if policy.MaxAge > 0 {
+			if err := del(tx.Unscoped().Where("user_id = ? AND created_at < ?", userID, time.Now().Add(-policy.MaxAge))); err != nil {
+				return err
+			}
+		}
+
+		if policy.MaxEntries > 0 {
+			older := tx.Model(&history.CommandHistory{}).
+				Select("id").
+				Where("user_id = ?", userID).
+				Order("created_at DESC, id DESC").
+				Offset(policy.MaxEntries)
+			if err := del(tx.Unscoped().Where("id IN (?)", older)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return deleted, err
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
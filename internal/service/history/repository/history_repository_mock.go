@@ -34,3 +34,29 @@ func (m *HistoryRepositoryMock) ClearUserHistory(userID int64) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
+
+func (m *HistoryRepositoryMock) FindByPrefix(userID int64, prefix, workingDir string, limit int) ([]history.CommandHistory, error) {
+	args := m.Called(userID, prefix, workingDir, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]history.CommandHistory), args.Error(1)
+}
+
+func (m *HistoryRepositoryMock) Search(filter history.SearchFilter) ([]history.CommandHistory, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]history.CommandHistory), args.Error(1)
+}
+
+func (m *HistoryRepositoryMock) SaveCommands(commands []history.CommandHistory) error {
+	args := m.Called(commands)
+	return args.Error(0)
+}
+
+func (m *HistoryRepositoryMock) Prune(userID int64, policy history.RetentionPolicy) (int64, error) {
+	args := m.Called(userID, policy)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"regexp"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,6 +32,25 @@ func (r *InMemoryRepository) SaveCommand(command *history.CommandHistory) error
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.save(command)
+
+	return nil
+}
+
+// SaveCommands saves several commands to history
+func (r *InMemoryRepository) SaveCommands(commands []history.CommandHistory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range commands {
+		r.save(&commands[i])
+	}
+
+	return nil
+}
+
+// save stores a command, the caller must hold the write lock
+func (r *InMemoryRepository) save(command *history.CommandHistory) {
 	// Auto-increment ID if not provided
 	if command.ID == 0 {
 		r.lastID++
@@ -49,9 +71,9 @@ func (r *InMemoryRepository) SaveCommand(command *history.CommandHistory) error
 	if _, exists := r.commandMeta[command.UserID]; !exists {
 		r.commandMeta[command.UserID] = make(map[string]time.Time)
 	}
-	r.commandMeta[command.UserID][command.Command] = command.CreatedAt
-
-	return nil
+	if last, ok := r.commandMeta[command.UserID][command.Command]; !ok || command.CreatedAt.After(last) {
+		r.commandMeta[command.UserID][command.Command] = command.CreatedAt
+	}
 }
 
 // GetUserHistory gets command history for a user
@@ -59,12 +81,14 @@ func (r *InMemoryRepository) GetUserHistory(userID int64, limit int) ([]history.
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	commands, exists := r.commands[userID]
+	stored, exists := r.commands[userID]
 	if !exists {
 		return []history.CommandHistory{}, nil
 	}
 
-	// Sort commands by creation time (newest first)
+	// Sort a copy by creation time (newest first): callers keep the result
+	// while other entries are saved and pruned
+	commands := slices.Clone(stored)
 	sort.Slice(commands, func(i, j int) bool {
 		return commands[i].CreatedAt.After(commands[j].CreatedAt)
 	})
@@ -125,3 +149,124 @@ func (r *InMemoryRepository) GetUserCommandStats(userID int64) ([]history.Comman
 
 	return stats, nil
 }
+
+// FindByPrefix returns the newest commands that start with prefix and are longer than it
+func (r *InMemoryRepository) FindByPrefix(userID int64, prefix, workingDir string, limit int) ([]history.CommandHistory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []history.CommandHistory
+	for _, cmd := range r.commands[userID] {
+		if len(cmd.Command) <= len(prefix) || !strings.HasPrefix(cmd.Command, prefix) {
+			continue
+		}
+		if workingDir != "" && cmd.WorkingDir != workingDir {
+			continue
+		}
+		result = append(result, cmd)
+	}
+
+	// Sort commands by creation time (newest first)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+
+	if limit > 0 && limit < len(result) {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// Search returns one page of the entries matching filter, newest first
+func (r *InMemoryRepository) Search(filter history.SearchFilter) ([]history.CommandHistory, error) {
+	pattern, err := regexp.Compile(filter.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []history.CommandHistory
+	for _, cmd := range r.commands[filter.UserID] {
+		if !pattern.MatchString(cmd.Command) {
+			continue
+		}
+		if !filter.Since.IsZero() && cmd.StartedAt.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && !cmd.StartedAt.Before(filter.Until) {
+			continue
+		}
+		if filter.WorkingDir != "" && cmd.WorkingDir != filter.WorkingDir {
+			continue
+		}
+		if (filter.Status == history.StatusSucceeded && cmd.ExitCode != 0) || (filter.Status == history.StatusFailed && cmd.ExitCode == 0) {
+			continue
+		}
+		result = append(result, cmd)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].StartedAt.Equal(result[j].StartedAt) {
+			return result[i].StartedAt.After(result[j].StartedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+
+	if filter.Offset >= len(result) {
+		return []history.CommandHistory{}, nil
+	}
+	result = result[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(result) {
+		result = result[:filter.Limit]
+	}
+
+	return result, nil
+}
+
+// Prune deletes the entries of a user that policy does not keep
+func (r *InMemoryRepository) Prune(userID int64, policy history.RetentionPolicy) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// sort a copy, as slices returned earlier may still be read
+	commands := slices.Clone(r.commands[userID])
+	sort.SliceStable(commands, func(i, j int) bool {
+		if !commands[i].CreatedAt.Equal(commands[j].CreatedAt) {
+			return commands[i].CreatedAt.Before(commands[j].CreatedAt)
+		}
+		return commands[i].ID < commands[j].ID
+	})
+
+	cutoff := time.Now().Add(-policy.MaxAge)
+	kept := make([]history.CommandHistory, 0, len(commands))
+	for i, cmd := range commands {
+		if policy.DedupConsecutive && i+1 < len(commands) && commands[i+1].Command == cmd.Command {
+			continue
+		}
+		if policy.MaxAge > 0 && cmd.CreatedAt.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, cmd)
+	}
+	if policy.MaxEntries > 0 && len(kept) > policy.MaxEntries {
+		kept = kept[len(kept)-policy.MaxEntries:]
+	}
+
+	deleted := int64(len(commands) - len(kept))
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	// rebuild the stats metadata from the remaining entries
+	meta := make(map[string]time.Time, len(kept))
+	for _, cmd := range kept {
+		meta[cmd.Command] = cmd.CreatedAt
+	}
+	r.commands[userID] = kept
+	r.commandMeta[userID] = meta
+
+	return deleted, nil
+}
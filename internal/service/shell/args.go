@@ -0,0 +1,95 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// ArgumentDeclarer is implemented by commands that declare their flags,
+// positional arguments and subcommands. The Service parses the arguments
+// before Execute, so invalid arguments and --help are handled the same way
+// for every such command, and MaxArguments is not used. The command reads the
+// values with ParseArguments.
+type ArgumentDeclarer interface {
+	Arguments() argparse.Spec
+}
+
+// usageExitCode is the exit status of a command given invalid arguments
+const usageExitCode = 2
+
+// WriteArgsError reports an error returned by argparse.Parse, followed by the
+// usage lines of the command
+func WriteArgsError(w io.Writer, err error) error {
+	var argsErr *argparse.Error
+	if errors.As(err, &argsErr) {
+		_, err = fmt.Fprintf(w, "%v\nusage: %s\n", argsErr, strings.Join(argsErr.Usage, "\n       "))
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, err)
+	return err
+}
+
+// parsedArgumentsKey is the context key of the arguments of a builtin parsed
+// before Execute
+type parsedArgumentsKey struct{}
+
+// parsedArguments holds the arguments of the builtin name parsed from args
+type parsedArguments struct {
+	name   string
+	args   []string
+	parsed *argparse.Args
+}
+
+// withParsedArguments parses the arguments of cmd when it declares them and
+// returns a context passing them to Execute, with the parse error
+func withParsedArguments(ctx context.Context, cmd Command, args []string) (context.Context, error) {
+	declarer, ok := cmd.(ArgumentDeclarer)
+	if !ok {
+		return ctx, nil
+	}
+
+	parsed, err := argparse.Parse(cmd.Name(), declarer.Arguments(), args)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, parsedArgumentsKey{}, parsedArguments{name: cmd.Name(), args: args, parsed: parsed}), nil
+}
+
+// ParseArguments returns args parsed according to spec, like argparse.Parse.
+// The arguments of the builtin name that the Service already parsed are
+// taken from ctx, so they are only parsed again when Execute is called
+// directly.
+func ParseArguments(ctx context.Context, name string, spec argparse.Spec, args []string) (*argparse.Args, error) {
+	if p, ok := ctx.Value(parsedArgumentsKey{}).(parsedArguments); ok && p.name == name && slices.Equal(p.args, args) {
+		return p.parsed, nil
+	}
+	return argparse.Parse(name, spec, args)
+}
+
+// argumentHelp documents the flags and subcommands of spec. The flags of a
+// subcommand are shown after it, prefixed with its name.
+func argumentHelp(spec argparse.Spec, prefix string) []HelpFlag {
+	var flags []HelpFlag
+	for _, flag := range spec.Flags {
+		flags = append(flags, HelpFlag{Flag: prefix + flag.Usage(), Description: flag.Description})
+	}
+	for _, positional := range spec.Positionals {
+		if positional.Description != "" {
+			flags = append(flags, HelpFlag{Flag: prefix + positional.Name, Description: positional.Description})
+		}
+	}
+
+	for _, sub := range spec.Subcommands {
+		flags = append(flags, HelpFlag{Flag: prefix + sub.Name, Description: sub.Description})
+		flags = append(flags, argumentHelp(sub.Spec, prefix+sub.Name+" ")...)
+	}
+
+	return flags
+}
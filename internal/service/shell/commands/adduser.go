@@ -2,21 +2,26 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
 	"github.com/Ali-Farhadnia/goshell/internal/service/user"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // AddUserCommand implements the adduser command
 type AddUserCommand struct {
-	userSVC *user.Service
+	userSVC        *user.Service
+	passwordReader shell.PasswordReader
 }
 
 // New creates a new adduser command
-func NewAddUserCommand(userSVC *user.Service) *AddUserCommand {
+func NewAddUserCommand(userSVC *user.Service, passwordReader shell.PasswordReader) *AddUserCommand {
 	return &AddUserCommand{
-		userSVC: userSVC,
+		userSVC:        userSVC,
+		passwordReader: passwordReader,
 	}
 }
 
@@ -27,23 +32,45 @@ func (c *AddUserCommand) Name() string {
 
 // MaxArguments returns the maximum number of arguments allowed for the Command.
 func (c *AddUserCommand) MaxArguments() int {
-	return 2
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *AddUserCommand) Arguments() argparse.Spec {
+	return passwordArguments()
+}
+
+// SecretArguments returns the indices of a password given as an argument
+func (c *AddUserCommand) SecretArguments(args []string) []int {
+	return passwordArgumentIndices(args)
 }
 
 // Execute runs the command
 func (c *AddUserCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
-	if len(args) == 0 {
-		_, err := fmt.Fprintf(errorOutputWriter, "usage: adduser <username> [password]\n")
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	credentials, err := readPasswordArgs(parsed, inputReader, c.passwordReader)
+	if errors.Is(err, errUsage) {
+		_, err = fmt.Fprintf(errorOutputWriter, "usage: adduser <username> [--password-stdin | --password-fd <fd>]\n")
+		return err
+	}
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error creating user: %v\n", err)
 		return err
 	}
 
-	username := args[0]
-	password := ""
-	if len(args) > 1 {
-		password = args[1]
+	if !credentials.hasPassword {
+		credentials.password, err = c.readNewPassword()
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error creating user: %v\n", err)
+			return err
+		}
 	}
 
-	_, err := c.userSVC.CreateUser(username, password)
+	_, err = c.userSVC.CreateUser(credentials.username, credentials.password)
 	if err != nil {
 		_, err = fmt.Fprintf(errorOutputWriter, "error creating user: %v\n", err)
 		return err
@@ -58,7 +85,40 @@ func (c *AddUserCommand) Execute(ctx context.Context, args []string, inputReader
 	return nil
 }
 
+// readNewPassword prompts twice for the new password. An empty password creates a user without one.
+func (c *AddUserCommand) readNewPassword() (string, error) {
+	password, err := promptPassword(c.passwordReader, "New password (empty for none): ")
+	if err != nil || password == "" {
+		return "", err
+	}
+
+	confirmation, err := promptPassword(c.passwordReader, "Retype new password: ")
+	if err != nil {
+		return "", err
+	}
+	if confirmation != password {
+		return "", errors.New("passwords do not match")
+	}
+
+	return password, nil
+}
+
 // Help returns the help text
 func (c *AddUserCommand) Help() string {
-	return "adduser <username> [password] - Add a new user. Password is optional."
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of adduser
+func (c *AddUserCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "adduser",
+		Summary:     "Add a new user.",
+		Synopsis:    []string{"adduser <username> [--password-stdin | --password-fd <fd>]"},
+		Description: "Creates a user account. The password is prompted for without echo and may be left empty for an account without a password.",
+		Examples: []shell.HelpExample{
+			{Command: "adduser alice", Description: "Create alice, asking for a password."},
+			{Command: "adduser bob --password-stdin < password.txt", Description: "Create bob with the password stored in a file."},
+		},
+		SeeAlso: []string{"login", "users"},
+	}
 }
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
@@ -19,6 +21,8 @@ func TestAddUserCommand_Execute(t *testing.T) {
 	cases := []struct {
 		name           string
 		args           []string
+		stdin          string
+		passwords      []string
 		setupUserRepo  func(repo *userRepository.UserRepositoryMock)
 		expectedOutput string
 		expectedError  string
@@ -52,7 +56,62 @@ func TestAddUserCommand_Execute(t *testing.T) {
 			args:           []string{},
 			setupUserRepo:  func(repo *userRepository.UserRepositoryMock) {},
 			expectedOutput: "",
-			expectedError:  "usage: adduser <username> [password]\n",
+			expectedError:  "adduser: missing username\nusage: adduser [--password-stdin] [--password-fd <fd>] <username> [password]\n",
+		},
+		{
+			name:      "success - password prompted twice",
+			args:      []string{"newuser"},
+			passwords: []string{"securepassword", "securepassword"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("FindUserByUsername", "newuser").Return(nil, user.ErrUserNotFound).Once()
+				repo.On("CreateUser", mock.MatchedBy(func(u user.User) bool {
+					return u.Username == "newuser" && u.PasswordHash != nil
+				})).Return(nil).Once()
+			},
+			expectedOutput: "User created successfully\n",
+			expectedError:  "",
+		},
+		{
+			name:           "failure - prompted passwords do not match",
+			args:           []string{"newuser"},
+			passwords:      []string{"securepassword", "typo"},
+			setupUserRepo:  func(repo *userRepository.UserRepositoryMock) {},
+			expectedOutput: "",
+			expectedError:  "error creating user: passwords do not match\n",
+		},
+		{
+			name:  "success - password from stdin",
+			args:  []string{"newuser", "--password-stdin"},
+			stdin: "securepassword\n",
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("FindUserByUsername", "newuser").Return(nil, user.ErrUserNotFound).Once()
+				repo.On("CreateUser", mock.MatchedBy(func(u user.User) bool {
+					return u.Username == "newuser" && u.PasswordHash != nil
+				})).Return(nil).Once()
+			},
+			expectedOutput: "User created successfully\n",
+			expectedError:  "",
+		},
+		{
+			name:           "failure - invalid password file descriptor",
+			args:           []string{"newuser", "--password-fd", "abc"},
+			setupUserRepo:  func(repo *userRepository.UserRepositoryMock) {},
+			expectedOutput: "",
+			expectedError:  "adduser: invalid fd: abc\nusage: adduser [--password-stdin] [--password-fd <fd>] <username> [password]\n",
+		},
+		{
+			name:           "failure - password file descriptor is the standard output",
+			args:           []string{"newuser", "--password-fd", "1"},
+			setupUserRepo:  func(repo *userRepository.UserRepositoryMock) {},
+			expectedOutput: "",
+			expectedError:  "error creating user: error reading password from file descriptor 1: invalid file descriptor: 1\n",
+		},
+		{
+			name:           "failure - password file descriptor is the standard error",
+			args:           []string{"newuser", "--password-fd", "2"},
+			setupUserRepo:  func(repo *userRepository.UserRepositoryMock) {},
+			expectedOutput: "",
+			expectedError:  "error creating user: error reading password from file descriptor 2: invalid file descriptor: 2\n",
 		},
 		{
 			name: "failure - user already exists",
@@ -93,8 +152,8 @@ func TestAddUserCommand_Execute(t *testing.T) {
 			var errorBuffer bytes.Buffer
 
 			userSvc := user.New(mockUserRepo)
-			cmd := commands.NewAddUserCommand(userSvc)
-			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+			cmd := commands.NewAddUserCommand(userSvc, &PasswordReaderStub{Passwords: tc.passwords})
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.stdin), &outputBuffer, &errorBuffer)
 
 			assert.NoError(t, err)
 
@@ -105,3 +164,32 @@ func TestAddUserCommand_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestAddUserCommand_SecretArguments(t *testing.T) {
+	cmd := commands.NewAddUserCommand(nil, nil)
+
+	assert.Equal(t, []int{1}, cmd.SecretArguments([]string{"newuser", "securepassword"}))
+	assert.Nil(t, cmd.SecretArguments([]string{"newuser", "--password-fd", "3"}))
+	assert.Nil(t, cmd.SecretArguments([]string{"newuser"}))
+	assert.Equal(t, []int{2}, cmd.SecretArguments([]string{"--", "newuser", "-secret"}))
+	assert.Equal(t, []int{2}, cmd.SecretArguments([]string{"--password-fd=3", "newuser", "-secret"}))
+}
+
+func TestAddUserCommand_PasswordStdinLeavesInput(t *testing.T) {
+	mockUserRepo := new(userRepository.UserRepositoryMock)
+	mockUserRepo.On("FindUserByUsername", "newuser").Return(nil, user.ErrUserNotFound).Once()
+	mockUserRepo.On("CreateUser", mock.Anything).Return(nil).Once()
+
+	var outputBuffer, errorBuffer bytes.Buffer
+	stdin := strings.NewReader("securepassword\nnext command\n")
+	cmd := commands.NewAddUserCommand(user.New(mockUserRepo), nil)
+	err := cmd.Execute(context.Background(), []string{"newuser", "--password-stdin"}, stdin, &outputBuffer, &errorBuffer)
+
+	assert.NoError(t, err)
+	assert.Empty(t, errorBuffer.String())
+
+	// only the password line is read, the rest is left for the next command
+	rest, err := io.ReadAll(stdin)
+	assert.NoError(t, err)
+	assert.Equal(t, "next command\n", string(rest))
+}
@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/user"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+	"github.com/Ali-Farhadnia/goshell/pkg/lineeditor"
+)
+
+// BindCommand implements the bind command
+type BindCommand struct {
+	userSVC        *user.Service
+	sessionRepo    shell.SessionRepository
+	configBindings map[string]map[string]string
+}
+
+// NewBindCommand creates a new bind command. configBindings are the bindings
+// from the configuration file, given as keymap to key to action.
+func NewBindCommand(userSVC *user.Service, sessionRepo shell.SessionRepository, configBindings map[string]map[string]string) *BindCommand {
+	return &BindCommand{
+		userSVC:        userSVC,
+		sessionRepo:    sessionRepo,
+		configBindings: configBindings,
+	}
+}
+
+// Name returns the command name
+func (c *BindCommand) Name() string {
+	return "bind"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *BindCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *BindCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'm', Type: argparse.String, Value: "keymap", Description: "Use keymap (emacs, vi-insert or vi-command) instead of the one of the current editing mode."},
+			{Short: 'l', Description: "List the names of the editor actions."},
+			{Short: 'p', Description: "Print the bindings of the keymap."},
+			{Short: 'r', Type: argparse.String, Value: "key", Description: "Remove the custom binding of key."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "key", Optional: true, Description: "Bind key, for example C-x or M-f, to action."},
+			{Name: "action", Optional: true},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *BindCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "session error: %v\n", err)
+		return err
+	}
+
+	keymap := lineeditor.KeymapEmacs
+	if session.EditingMode == lineeditor.ModeVi {
+		keymap = lineeditor.KeymapViInsert
+	}
+	if parsed.Has("m") {
+		keymap = parsed.String("m")
+		if err := lineeditor.ValidateKeymap(keymap); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "bind: %v\n", err)
+			return err
+		}
+	}
+
+	// -l, -p, -r and a binding are exclusive
+	actions := 0
+	for _, name := range []string{"l", "p", "r", "key"} {
+		if parsed.Has(name) {
+			actions++
+		}
+	}
+
+	switch {
+	case actions > 1 || (parsed.Has("key") && !parsed.Has("action")):
+		_, err = fmt.Fprintf(errorOutputWriter, "usage: bind [-m keymap] [-l|-p|-r key|key action]\n")
+		return err
+
+	case actions == 0 || parsed.Bool("p"):
+		return c.listBindings(session, keymap, outputWriter, errorOutputWriter)
+
+	case parsed.Bool("l"):
+		for _, action := range lineeditor.Actions() {
+			_, err = fmt.Fprintln(outputWriter, action)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case parsed.Has("r"):
+		return c.unbind(session, keymap, parsed.String("r"), errorOutputWriter)
+
+	default:
+		return c.bind(session, keymap, parsed.String("key"), parsed.String("action"), errorOutputWriter)
+	}
+}
+
+// listBindings prints the effective bindings of a keymap
+func (c *BindCommand) listBindings(session shell.Session, keymap string, outputWriter, errorOutputWriter io.Writer) error {
+	bindings := lineeditor.DefaultBindings(keymap)
+	for key, action := range c.configBindings[keymap] {
+		bindings[key] = action
+	}
+	for key, action := range session.KeyBindings[keymap] {
+		bindings[key] = action
+	}
+
+	keys := make([]string, 0, len(bindings))
+	for key := range bindings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(outputWriter, 0, 0, 3, ' ', 0)
+	for _, key := range keys {
+		_, err := fmt.Fprintf(w, "%s\t%s\n", key, bindings[key])
+		if err != nil {
+			return err
+		}
+	}
+
+	err := w.Flush()
+	if err != nil {
+		_, err := fmt.Fprintf(errorOutputWriter, "error flushing tab writer: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// bind adds a binding to the session and saves it for the logged in user
+func (c *BindCommand) bind(session shell.Session, keymap, key, action string, errorOutputWriter io.Writer) error {
+	err := lineeditor.ValidateBinding(keymap, key, action)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "bind: %v\n", err)
+		return err
+	}
+
+	if session.User != nil {
+		err = c.userSVC.BindKey(session.User.ID, keymap, key, action)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "bind: error saving binding: %v\n", err)
+			return err
+		}
+	}
+
+	bindings := copyBindings(session.KeyBindings)
+	if bindings[keymap] == nil {
+		bindings[keymap] = make(map[string]string)
+	}
+	bindings[keymap][key] = action
+	session.KeyBindings = bindings
+
+	err = c.sessionRepo.SetSession(session)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "session save error: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// unbind removes a custom binding from the session and from the logged in user
+func (c *BindCommand) unbind(session shell.Session, keymap, key string, errorOutputWriter io.Writer) error {
+	if _, ok := session.KeyBindings[keymap][key]; !ok {
+		_, err := fmt.Fprintf(errorOutputWriter, "bind: no custom binding for %s in %s\n", key, keymap)
+		return err
+	}
+
+	if session.User != nil {
+		err := c.userSVC.UnbindKey(session.User.ID, keymap, key)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "bind: error removing binding: %v\n", err)
+			return err
+		}
+	}
+
+	bindings := copyBindings(session.KeyBindings)
+	delete(bindings[keymap], key)
+	session.KeyBindings = bindings
+
+	err := c.sessionRepo.SetSession(session)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "session save error: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// copyBindings returns a deep copy of keymap bindings so the stored session is not modified in place
+func copyBindings(bindings map[string]map[string]string) map[string]map[string]string {
+	copied := make(map[string]map[string]string, len(bindings))
+	for keymap, keys := range bindings {
+		copied[keymap] = make(map[string]string, len(keys))
+		for key, action := range keys {
+			copied[keymap][key] = action
+		}
+	}
+	return copied
+}
+
+// Help returns the help text
+func (c *BindCommand) Help() string {
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of bind
+func (c *BindCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "bind",
+		Summary:     "List, add or remove line editor key bindings.",
+		Synopsis:    []string{"bind [-m keymap] [-l | -p | -r key | key action]"},
+		Description: "Without arguments bind shows the bindings of the keymap. Bindings added or removed while logged in are saved for the user.",
+		Examples: []shell.HelpExample{
+			{Command: "bind C-k kill-line", Description: "Make Ctrl-K delete to the end of the line."},
+			{Command: "bind -m vi-command -p", Description: "Show the bindings of vi command mode."},
+		},
+		SeeAlso: []string{"set"},
+	}
+}
@@ -0,0 +1,208 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	shellRepository "github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/Ali-Farhadnia/goshell/internal/service/user"
+	userRepository "github.com/Ali-Farhadnia/goshell/internal/service/user/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBindCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	configBindings := map[string]map[string]string{
+		"emacs": {"ctrl-o": "accept-line"},
+	}
+
+	cases := []struct {
+		name           string
+		args           []string
+		setupUserRepo  func(repo *userRepository.UserRepositoryMock)
+		setupSession   func(repo *shellRepository.SessionRepositoryMock)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:          "success - guest binding is kept in the session only",
+			args:          []string{"ctrl-j", "accept-line"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+				repo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.KeyBindings["emacs"]["ctrl-j"] == "accept-line"
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name: "success - binding is saved for the logged in user",
+			args: []string{"-m", "vi-insert", "ctrl-a", "beginning-of-line"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("SaveKeyBinding", user.KeyBinding{
+					UserID: 1,
+					Keymap: "vi-insert",
+					Key:    "ctrl-a",
+					Action: "beginning-of-line",
+				}).Return(nil).Once()
+			},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{User: &user.User{ID: 1}}, nil).Once()
+				repo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.KeyBindings["vi-insert"]["ctrl-a"] == "beginning-of-line"
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name:          "success - vi mode binds in the vi-insert keymap",
+			args:          []string{"ctrl-a", "beginning-of-line"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{EditingMode: "vi"}, nil).Once()
+				repo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.KeyBindings["vi-insert"]["ctrl-a"] == "beginning-of-line"
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name: "success - remove binding",
+			args: []string{"-r", "ctrl-j"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("DeleteKeyBinding", int64(1), "emacs", "ctrl-j").Return(nil).Once()
+			},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{
+					User:        &user.User{ID: 1},
+					KeyBindings: map[string]map[string]string{"emacs": {"ctrl-j": "accept-line"}},
+				}, nil).Once()
+				repo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					_, ok := s.KeyBindings["emacs"]["ctrl-j"]
+					return !ok
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name:          "failure - unknown action",
+			args:          []string{"ctrl-j", "launch-rocket"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "bind: unknown action: launch-rocket\n",
+		},
+		{
+			name:          "failure - unknown keymap",
+			args:          []string{"-m", "vim", "ctrl-j", "accept-line"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "bind: unknown keymap: vim\n",
+		},
+		{
+			name:          "failure - remove binding that is not custom",
+			args:          []string{"-r", "ctrl-a"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "bind: no custom binding for ctrl-a in emacs\n",
+		},
+		{
+			name:          "failure - list and bind combined",
+			args:          []string{"-l", "ctrl-j", "accept-line"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "usage: bind [-m keymap] [-l|-p|-r key|key action]\n",
+		},
+		{
+			name: "failure - save error",
+			args: []string{"ctrl-j", "accept-line"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("SaveKeyBinding", mock.Anything).Return(errors.New("db error")).Once()
+			},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{User: &user.User{ID: 1}}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "bind: error saving binding: db error\n",
+		},
+		{
+			name:          "failure - session error",
+			args:          []string{},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, errors.New("session get error")).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "session error: session get error\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(userRepository.UserRepositoryMock)
+			mockSessionRepo := new(shellRepository.SessionRepositoryMock)
+			tc.setupUserRepo(mockUserRepo)
+			tc.setupSession(mockSessionRepo)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewBindCommand(user.New(mockUserRepo), mockSessionRepo, configBindings)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockUserRepo.AssertExpectations(t)
+			mockSessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBindCommand_List(t *testing.T) {
+	mockSessionRepo := new(shellRepository.SessionRepositoryMock)
+	mockSessionRepo.On("GetSession").Return(shell.Session{
+		KeyBindings: map[string]map[string]string{"emacs": {"ctrl-a": "end-of-line"}},
+	}, nil).Once()
+
+	configBindings := map[string]map[string]string{
+		"emacs": {"ctrl-o": "accept-line"},
+	}
+
+	var outputBuffer bytes.Buffer
+	var errorBuffer bytes.Buffer
+
+	cmd := commands.NewBindCommand(user.New(new(userRepository.UserRepositoryMock)), mockSessionRepo, configBindings)
+	err := cmd.Execute(context.Background(), []string{"-p"}, nil, &outputBuffer, &errorBuffer)
+
+	assert.NoError(t, err)
+	assert.Empty(t, errorBuffer.String())
+	// session bindings override the defaults and config bindings are added to them
+	assert.Regexp(t, regexp.MustCompile(`(?m)^ctrl-a +end-of-line$`), outputBuffer.String())
+	assert.Regexp(t, regexp.MustCompile(`(?m)^ctrl-o +accept-line$`), outputBuffer.String())
+	assert.Regexp(t, regexp.MustCompile(`(?m)^ctrl-e +end-of-line$`), outputBuffer.String())
+	mockSessionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// BuiltinCommand implements the builtin command
+type BuiltinCommand struct {
+	cmdRepo shell.CommandRepository
+}
+
+// NewBuiltinCommand creates a new builtin command
+func NewBuiltinCommand(cmdRepo shell.CommandRepository) *BuiltinCommand {
+	return &BuiltinCommand{
+		cmdRepo: cmdRepo,
+	}
+}
+
+// Name returns the command name
+func (c *BuiltinCommand) Name() string {
+	return "builtin"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *BuiltinCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *BuiltinCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Positionals: []argparse.Positional{
+			{Name: "name"},
+			{Name: "arg", Optional: true, Variadic: true},
+		},
+		FlagsFirst: true,
+	}
+}
+
+// SecretArguments redacts the secrets of the builtin being run
+func (c *BuiltinCommand) SecretArguments(args []string) []int {
+	return delegatedSecrets(c.cmdRepo, c.Arguments(), args)
+}
+
+// Execute runs the command
+func (c *BuiltinCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	name := parsed.String("name")
+	cmd, err := c.cmdRepo.Get(name)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "builtin: %s: not a shell builtin\n", name)
+		return err
+	}
+
+	_, err = shell.RunBuiltin(ctx, cmd, parsed.Strings("arg"), inputReader, outputWriter, errorOutputWriter)
+	return err
+}
+
+// Help returns the help text
+func (c *BuiltinCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of builtin
+func (c *BuiltinCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "builtin",
+		Summary:     "Run a shell builtin.",
+		Description: "Runs the builtin called name with the given arguments, never an executable from PATH, even when one has the same name.",
+		Examples: []shell.HelpExample{
+			{Command: "builtin echo -n hi", Description: "Run the echo builtin; an echo executable in PATH is never used."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "The builtin succeeded."},
+			{Code: 1, Description: "The builtin failed, or name is not a builtin."},
+		},
+		SeeAlso: []string{"command", "type"},
+	}
+}
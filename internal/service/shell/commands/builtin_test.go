@@ -0,0 +1,68 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name           string
+		args           []string
+		setupRepo      func(repo *repository.CommandRepositoryMock)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "success - runs the builtin",
+			args: []string{"echo", "-n", "hello"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", "echo").Return(commands.NewEchoCommand(), nil).Once()
+			},
+			expectedOutput: "hello",
+			expectedError:  "",
+		},
+		{
+			name: "failure - not a builtin",
+			args: []string{"git", "status"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", "git").Return(&MockCommand{}, errors.New("not found")).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "builtin: git: not a shell builtin\n",
+		},
+		{
+			name:           "failure - no name",
+			args:           []string{},
+			setupRepo:      func(repo *repository.CommandRepositoryMock) {},
+			expectedOutput: "",
+			expectedError:  "builtin: missing name\nusage: builtin <name> [arg ...]\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.CommandRepositoryMock)
+			tc.setupRepo(mockRepo)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewBuiltinCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -1,13 +1,16 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // CatCommand implements the cat command
@@ -29,14 +32,39 @@ func (c *CatCommand) Name() string {
 
 // MaxArguments returns the maximum number of arguments allowed for the Command.
 func (c *CatCommand) MaxArguments() int {
-	return 1
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *CatCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'A', Description: "Same as -vET."},
+			{Short: 'b', Description: "Number non-blank output lines; overrides -n."},
+			{Short: 'e', Description: "Same as -vE."},
+			{Short: 'E', Description: "Show $ at the end of each line."},
+			{Short: 'n', Description: "Number all output lines."},
+			{Short: 's', Description: "Squeeze repeated blank lines into one."},
+			{Short: 't', Description: "Same as -vT."},
+			{Short: 'T', Description: "Show tabs as ^I."},
+			{Short: 'v', Description: "Show non-printing characters with ^ and M- notation."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Optional: true, Variadic: true},
+		},
+	}
 }
 
 // Execute runs the command
 func (c *CatCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
-	if len(args) == 0 {
-		_, err := fmt.Fprintf(errorOutputWriter, "usage: cat <filename>\n")
-		return err
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	format := newCatFormatter(parsed)
+	operands := parsed.Operands
+	if len(operands) == 0 {
+		operands = []string{"-"}
 	}
 
 	session, err := c.sessionRepo.GetSession()
@@ -45,27 +73,215 @@ func (c *CatCommand) Execute(ctx context.Context, args []string, inputReader io.
 		return err
 	}
 
-	filePath := args[0]
-	if !filepath.IsAbs(filePath) {
-		filePath = filepath.Join(session.WorkingDir, args[0])
+	for _, operand := range operands {
+		err := c.catFile(ctx, session.WorkingDir, operand, format, inputReader, outputWriter)
+
+		var readErr *catReadError
+		switch {
+		case err == nil:
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+			return nil
+		case errors.As(err, &readErr):
+			// the other operands are still printed
+			if _, err := fmt.Fprintf(errorOutputWriter, "cat: %s: %v\n", operand, pathError(readErr.err)); err != nil {
+				return err
+			}
+		default:
+			_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+			return err
+		}
 	}
 
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		_, err = fmt.Fprintf(errorOutputWriter, "error reading file: %v\n", err)
-		return err
+	return nil
+}
+
+// Help returns the help text
+func (c *CatCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of cat
+func (c *CatCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "cat",
+		Summary: "Concatenate files to the output.",
+		Description: "Copies each file to the output in order, streaming so that files of any size use little memory. A file named - or no file at all reads the input. Output is binary safe unless a formatting option is used." +
+			"\n\n" +
+			"Ctrl-C stops cat. An unreadable file is reported and the others are still printed.",
+		Examples: []shell.HelpExample{
+			{Command: "cat notes.txt", Description: "Print a file."},
+			{Command: "cat -n header.txt - footer.txt < body.txt", Description: "Number the lines of three inputs joined together."},
+			{Command: "cat -A config.yaml", Description: "Reveal tabs, trailing spaces and control characters."},
+		},
+		SeeAlso: []string{"echo", "ls"},
 	}
+}
 
-	_, err = fmt.Fprintf(outputWriter, "%s\n", string(data))
-	if err != nil {
-		_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+// catReadError wraps failures to open or read an operand, as opposed to
+// failures to write the output
+type catReadError struct {
+	err error
+}
+
+func (e *catReadError) Error() string { return e.err.Error() }
+func (e *catReadError) Unwrap() error { return e.err }
+
+// catFile copies one operand to the output
+func (c *CatCommand) catFile(ctx context.Context, workingDir, operand string, format *catFormatter, inputReader io.Reader, outputWriter io.Writer) error {
+	var r io.Reader
+	if operand == "-" {
+		if inputReader == nil {
+			return nil
+		}
+		r = inputReader
+	} else {
+		f, err := os.Open(resolvePath(workingDir, operand))
+		if err != nil {
+			return &catReadError{err: err}
+		}
+		defer f.Close()
+
+		if info, err := f.Stat(); err == nil && info.IsDir() {
+			return &catReadError{err: errors.New("is a directory")}
+		}
+		r = f
+	}
+
+	// reads from a terminal only notice the cancellation once they return
+	r = &catReader{contextReader{ctx: ctx, r: r}}
+
+	if format == nil {
+		_, err := io.Copy(outputWriter, r)
 		return err
 	}
+	return format.copy(outputWriter, r)
+}
 
-	return nil
+// catReader stops reading once ctx is cancelled and marks read errors
+type catReader struct {
+	contextReader
 }
 
-// Help returns the help text
-func (c *CatCommand) Help() string {
-	return "cat <filename> - Displays the content of the specified file"
+func (r *catReader) Read(p []byte) (int, error) {
+	n, err := r.contextReader.Read(p)
+	if err != nil && err != io.EOF && !isCancelled(err) {
+		err = &catReadError{err: err}
+	}
+	return n, err
+}
+
+// newCatFormatter returns the formatter for the flags of cat, or nil when
+// the input is copied unchanged
+func newCatFormatter(parsed *argparse.Args) *catFormatter {
+	format := catFormatter{
+		numberAll:       parsed.Bool("n"),
+		numberNonBlank:  parsed.Bool("b"),
+		squeeze:         parsed.Bool("s"),
+		showNonPrinting: parsed.Bool("v") || parsed.Bool("A") || parsed.Bool("e") || parsed.Bool("t"),
+		showEnds:        parsed.Bool("E") || parsed.Bool("A") || parsed.Bool("e"),
+		showTabs:        parsed.Bool("T") || parsed.Bool("A") || parsed.Bool("t"),
+	}
+
+	if format == (catFormatter{}) {
+		return nil
+	}
+	format.atLineStart = true
+	return &format
+}
+
+// catFormatter numbers and transforms lines. Line numbers and blank line
+// squeezing carry on from one file to the next like in GNU cat.
+type catFormatter struct {
+	numberAll       bool
+	numberNonBlank  bool
+	squeeze         bool
+	showNonPrinting bool
+	showEnds        bool
+	showTabs        bool
+
+	line        int
+	atLineStart bool
+	prevBlank   bool
+}
+
+// copy writes the lines read from r, formatted, to w. Long lines are handled
+// in pieces so memory use stays bounded.
+func (f *catFormatter) copy(w io.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if len(chunk) > 0 {
+			f.write(bw, chunk)
+		}
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if flushErr := bw.Flush(); flushErr != nil {
+			return flushErr
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// write formats a piece of a line, ending with the newline when the line is
+// complete. Errors are reported by the final Flush.
+func (f *catFormatter) write(w *bufio.Writer, chunk []byte) {
+	text, complete := bytes.CutSuffix(chunk, []byte("\n"))
+
+	if f.atLineStart {
+		blank := complete && len(text) == 0
+		if blank && f.squeeze && f.prevBlank {
+			return
+		}
+		f.prevBlank = blank
+
+		if (f.numberNonBlank && !blank) || (f.numberAll && !f.numberNonBlank) {
+			f.line++
+			fmt.Fprintf(w, "%6d\t", f.line)
+		}
+	}
+	f.atLineStart = complete
+
+	for _, b := range text {
+		switch {
+		case b == '\t' && f.showTabs:
+			w.WriteString("^I")
+		case b == '\t' || !f.showNonPrinting:
+			w.WriteByte(b)
+		default:
+			w.WriteString(nonPrinting(b))
+		}
+	}
+
+	if complete {
+		if f.showEnds {
+			w.WriteByte('$')
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// nonPrinting shows a byte in the ^ and M- notation of cat -v
+func nonPrinting(b byte) string {
+	prefix := ""
+	if b >= 128 {
+		prefix, b = "M-", b-128
+	}
+
+	switch {
+	case b < 32:
+		return prefix + "^" + string(rune(b+64))
+	case b == 127:
+		return prefix + "^?"
+	default:
+		return prefix + string(rune(b))
+	}
 }
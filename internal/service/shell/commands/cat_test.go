@@ -6,6 +6,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
@@ -31,9 +32,17 @@ func TestCatCommand_Execute(t *testing.T) {
 	assert.NoError(t, err)
 	tempFile.Close()
 
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "lines.txt"), []byte("one\n\n\n\ntwo\tx\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "binary.bin"), []byte{0, 1, 0x7f, 0x80, 0xff, 'a', '\n'}, 0644)
+	longLine := strings.Repeat("a", 10000)
+	os.WriteFile(filepath.Join(dir, "long.txt"), []byte(longLine+"\nb"), 0644)
+
 	cases := []struct {
 		name           string
 		args           []string
+		input          string
+		cancelled      bool
 		setupRepo      func()
 		expectedOutput string
 		expectedError  string
@@ -44,7 +53,7 @@ func TestCatCommand_Execute(t *testing.T) {
 			setupRepo: func() {
 				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/tmp"}, nil).Once()
 			},
-			expectedOutput: content + "\n",
+			expectedOutput: content,
 			expectedError:  "",
 		},
 		{
@@ -53,7 +62,7 @@ func TestCatCommand_Execute(t *testing.T) {
 			setupRepo: func() {
 				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: filepath.Dir(tempFile.Name())}, nil).Once()
 			},
-			expectedOutput: content + "\n",
+			expectedOutput: content,
 			expectedError:  "",
 		},
 		{
@@ -63,7 +72,7 @@ func TestCatCommand_Execute(t *testing.T) {
 				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/tmp"}, nil).Once()
 			},
 			expectedOutput: "",
-			expectedError:  "error reading file",
+			expectedError:  "cat: /nonexistent/file.txt: no such file or directory\n",
 		},
 		{
 			name: "failure - session error",
@@ -75,11 +84,87 @@ func TestCatCommand_Execute(t *testing.T) {
 			expectedError:  "error getting session",
 		},
 		{
-			name:           "failure - no arguments",
-			args:           []string{},
+			name:  "success - no arguments reads the input",
+			input: "from stdin\n",
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+			},
+			expectedOutput: "from stdin\n",
+		},
+		{
+			name:  "success - several files and - for the input",
+			args:  []string{tempFile.Name(), "-", "lines.txt"},
+			input: "|",
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+			},
+			expectedOutput: content + "|one\n\n\n\ntwo\tx\n",
+		},
+		{
+			name: "success - binary data is copied unchanged",
+			args: []string{"binary.bin"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+			},
+			expectedOutput: "\x00\x01\x7f\x80\xffa\n",
+		},
+		{
+			name: "success - number lines across files",
+			args: []string{"-n", "lines.txt", "lines.txt"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+			},
+			expectedOutput: "     1\tone\n     2\t\n     3\t\n     4\t\n     5\ttwo\tx\n" +
+				"     6\tone\n     7\t\n     8\t\n     9\t\n    10\ttwo\tx\n",
+		},
+		{
+			name: "success - number non-blank lines and squeeze blank lines",
+			args: []string{"-bs", "lines.txt"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+			},
+			expectedOutput: "     1\tone\n\n     2\ttwo\tx\n",
+		},
+		{
+			name: "success - show non-printing characters, tabs and line ends",
+			args: []string{"-A", "lines.txt", "binary.bin"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+			},
+			expectedOutput: "one$\n$\n$\n$\ntwo^Ix$\n^@^A^?M-^@M-^?a$\n",
+		},
+		{
+			name: "success - long lines are numbered once",
+			args: []string{"-n", "long.txt"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+			},
+			expectedOutput: "     1\t" + longLine + "\n     2\tb",
+		},
+		{
+			name:      "success - cancelled context stops output",
+			args:      []string{"lines.txt"},
+			cancelled: true,
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+			},
+			expectedOutput: "",
+		},
+		{
+			name: "failure - directory operand does not stop the others",
+			args: []string{".", "lines.txt"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+			},
+			expectedOutput: "one\n\n\n\ntwo\tx\n",
+			expectedError:  "cat: .: is a directory\n",
+		},
+		{
+			name:           "failure - invalid option",
+			args:           []string{"-x"},
 			setupRepo:      func() {},
 			expectedOutput: "",
-			expectedError:  "usage: cat <filename>\n",
+			expectedError:  "cat: invalid option -- 'x'\nusage: cat [-AbeEnstTv] [file ...]\n",
 		},
 	}
 
@@ -91,8 +176,15 @@ func TestCatCommand_Execute(t *testing.T) {
 			var outputBuffer bytes.Buffer
 			var errorBuffer bytes.Buffer
 
+			ctx := ctx
+			if tc.cancelled {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cancel()
+			}
+
 			cmd := commands.NewCatCommand(mockRepo)
-			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.input), &outputBuffer, &errorBuffer)
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
@@ -2,23 +2,26 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // CDCommand implements the cd command
 type CDCommand struct {
 	sessionRepo shell.SessionRepository
+	frecencySVC *frecency.Service
 }
 
-// New creates a new cd command
-func NewCDCommand(sessionRepo shell.SessionRepository) *CDCommand {
+// New creates a new cd command. Visited directories are recorded for z.
+func NewCDCommand(sessionRepo shell.SessionRepository, frecencySVC *frecency.Service) *CDCommand {
 	return &CDCommand{
 		sessionRepo: sessionRepo,
+		frecencySVC: frecencySVC,
 	}
 }
 
@@ -29,15 +32,30 @@ func (c *CDCommand) Name() string {
 
 // MaxArguments returns the maximum number of arguments allowed for the Command.
 func (c *CDCommand) MaxArguments() int {
-	return 1
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *CDCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'L', Description: "Keep symbolic links in the new path, so .. goes back through them (default)."},
+			{Short: 'P', Description: "Resolve symbolic links to the physical directory."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "dir", Optional: true},
+		},
+	}
 }
 
 // Execute runs the command
 func (c *CDCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
-	if len(args) == 0 {
-		_, err := fmt.Fprintf(errorOutputWriter, "usage: cd <dir>\n")
-		return err
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
 	}
+	// of -L and -P the last one given is used
+	physical := parsed.Last("L", "P") == "P"
 
 	session, err := c.sessionRepo.GetSession()
 	if err != nil {
@@ -45,24 +63,41 @@ func (c *CDCommand) Execute(ctx context.Context, args []string, inputReader io.R
 		return err
 	}
 
-	newPath := filepath.Join(session.WorkingDir, args[0])
+	// no operand means home and - the previous directory, which is printed
+	operand, print := "", false
+	switch {
+	case !parsed.Has("dir"):
+		operand, err = homeDir()
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "cd: HOME not set\n")
+			return err
+		}
+	case parsed.String("dir") == "-":
+		operand, print = session.OldWorkingDir, true
+		if operand == "" {
+			_, err = fmt.Fprintf(errorOutputWriter, "cd: OLDPWD not set\n")
+			return err
+		}
+	default:
+		operand = parsed.String("dir")
+	}
 
-	info, err := os.Stat(newPath)
+	dir, found, err := findDirectory(session.WorkingDir, operand, physical)
+	if errors.Is(err, errNotDirectory) {
+		_, err = fmt.Fprintf(errorOutputWriter, "not a directory\n")
+		return err
+	}
 	if err != nil {
 		_, err = fmt.Fprintf(errorOutputWriter, "error accessing path: %v\n", err)
 		return err
 	}
 
-	if !info.IsDir() {
-		_, err = fmt.Fprintf(errorOutputWriter, "not a directory\n")
+	if err := changeWorkingDir(c.sessionRepo, c.frecencySVC, session, dir, errorOutputWriter); err != nil {
 		return err
 	}
 
-	session.WorkingDir = newPath
-
-	err = c.sessionRepo.SetSession(session)
-	if err != nil {
-		_, err = fmt.Fprintf(errorOutputWriter, "error updating session: %v\n", err)
+	if print || found {
+		_, err = fmt.Fprintln(outputWriter, dir)
 		return err
 	}
 
@@ -71,5 +106,23 @@ func (c *CDCommand) Execute(ctx context.Context, args []string, inputReader io.R
 
 // Help returns the help text
 func (c *CDCommand) Help() string {
-	return "cd [dir] - Changes the current working directory"
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of cd
+func (c *CDCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "cd",
+		Summary: "Change the working directory.",
+		Description: "Without dir cd changes to $HOME, and cd - changes to the previous directory and prints it. A leading ~ or ~user is expanded to a home directory." +
+			"\n\n" +
+			"Relative names that do not start with . or .. are also looked up in the colon separated directories of $CDPATH; the new directory is printed when it was found that way. PWD and OLDPWD are exported to commands.",
+		Examples: []shell.HelpExample{
+			{Command: "cd", Description: "Go to the home directory."},
+			{Command: "cd -", Description: "Go back to the previous directory."},
+			{Command: "cd ~alice/shared", Description: "Go to a directory in the home of alice."},
+			{Command: "cd -P /var/run", Description: "Go to the directory the symlink points to."},
+		},
+		SeeAlso: []string{"pushd", "popd", "dirs", "z", "pwd"},
+	}
 }
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	frecencyRepository "github.com/Ali-Farhadnia/goshell/internal/service/frecency/repository"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
@@ -27,7 +29,20 @@ func TestCDCommand_Execute(t *testing.T) {
 	// Create a temporary directory for testing
 	tempDir, err := os.MkdirTemp(curDir, "testdir")
 	assert.NoError(t, err)
-	defer os.Remove(tempDir)
+	defer os.RemoveAll(tempDir)
+
+	// a directory reachable through CDPATH and a symlink to it
+	projectDir := filepath.Join(tempDir, "projects", "app")
+	assert.NoError(t, os.MkdirAll(projectDir, 0o755))
+	linkDir := filepath.Join(tempDir, "link")
+	assert.NoError(t, os.Symlink(projectDir, linkDir))
+	realProjectDir, err := filepath.EvalSymlinks(projectDir)
+	assert.NoError(t, err)
+
+	t.Setenv("HOME", tempDir)
+	t.Setenv("CDPATH", filepath.Join(tempDir, "projects"))
+	t.Setenv("PWD", "")
+	t.Setenv("OLDPWD", "")
 
 	// Create a file for testing
 	tempFile, err := os.CreateTemp("", "testfile")
@@ -81,7 +96,7 @@ func TestCDCommand_Execute(t *testing.T) {
 				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/tmp"}, nil).Once()
 			},
 			expectedOutput: "",
-			expectedError:  "no such file or directory",
+			expectedError:  "not a directory",
 		},
 		{
 			name: "failure - session retrieval error",
@@ -103,11 +118,92 @@ func TestCDCommand_Execute(t *testing.T) {
 			expectedError:  "session update error",
 		},
 		{
-			name:           "failure - no arguments",
-			args:           []string{},
+			name: "success - no arguments changes to HOME",
+			args: []string{},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/"}, nil).Once()
+				mockRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == tempDir && s.OldWorkingDir == "/"
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name: "success - tilde is expanded",
+			args: []string{"~/projects"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/"}, nil).Once()
+				mockRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == filepath.Join(tempDir, "projects")
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name: "success - dash changes to the previous directory and prints it",
+			args: []string{"-"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/", OldWorkingDir: tempDir}, nil).Once()
+				mockRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == tempDir && s.OldWorkingDir == "/"
+				})).Return(nil).Once()
+			},
+			expectedOutput: tempDir + "\n",
+			expectedError:  "",
+		},
+		{
+			name: "failure - dash without a previous directory",
+			args: []string{"-"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/"}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "cd: OLDPWD not set\n",
+		},
+		{
+			name: "success - relative name found in CDPATH is printed",
+			args: []string{"app"},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/"}, nil).Once()
+				mockRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == projectDir
+				})).Return(nil).Once()
+			},
+			expectedOutput: projectDir + "\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - logical path keeps symlinks",
+			args: []string{linkDir},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/"}, nil).Once()
+				mockRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == linkDir
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name: "success - physical path resolves symlinks",
+			args: []string{"-P", linkDir},
+			setupRepo: func() {
+				mockRepo.On("GetSession").Return(shell.Session{WorkingDir: "/"}, nil).Once()
+				mockRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == realProjectDir
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name:           "failure - too many arguments",
+			args:           []string{"a", "b"},
 			setupRepo:      func() {},
 			expectedOutput: "",
-			expectedError:  "usage: cd <dir>",
+			expectedError:  "cd: too many arguments\nusage: cd [-LP] [dir]\n",
 		},
 	}
 
@@ -119,7 +215,7 @@ func TestCDCommand_Execute(t *testing.T) {
 			var outputBuffer bytes.Buffer
 			var errorBuffer bytes.Buffer
 
-			cmd := commands.NewCDCommand(mockRepo)
+			cmd := commands.NewCDCommand(mockRepo, frecency.New(nil, frecencyRepository.NewInMemory(), -1))
 			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
 
 			assert.NoError(t, err)
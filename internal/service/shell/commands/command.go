@@ -0,0 +1,184 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// defaultPath is the PATH searched by command -p, where the standard
+// utilities are found whatever PATH is set to
+const defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// CommandCommand implements the command command
+type CommandCommand struct {
+	cmdRepo     shell.CommandRepository
+	sessionRepo shell.SessionRepository
+	path        string
+}
+
+// NewCommandCommand creates a new command command. Executables are searched
+// in path.
+func NewCommandCommand(cmdRepo shell.CommandRepository, sessionRepo shell.SessionRepository, path string) *CommandCommand {
+	return &CommandCommand{
+		cmdRepo:     cmdRepo,
+		sessionRepo: sessionRepo,
+		path:        path,
+	}
+}
+
+// Name returns the command name
+func (c *CommandCommand) Name() string {
+	return "command"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *CommandCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *CommandCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'p', Description: "Search a default PATH that finds the standard utilities."},
+			{Short: 'v', Description: "Print the path of each executable, or the name of each builtin, instead of running it."},
+			{Short: 'V', Description: "Describe each name like type instead of running it."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "name"},
+			{Name: "arg", Optional: true, Variadic: true},
+		},
+		FlagsFirst: true,
+	}
+}
+
+// SecretArguments redacts the secrets of the builtin being run
+func (c *CommandCommand) SecretArguments(args []string) []int {
+	return delegatedSecrets(c.cmdRepo, c.Arguments(), args)
+}
+
+// Execute runs the command
+func (c *CommandCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	path := shell.SearchPath(c.path)
+	if parsed.Bool("p") {
+		path = defaultPath
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	if parsed.Bool("v") || parsed.Bool("V") {
+		return c.describe(parsed.Operands, session.WorkingDir, path, parsed.Bool("V"), outputWriter, errorOutputWriter)
+	}
+
+	name, cmdArgs := parsed.String("name"), parsed.Strings("arg")
+	matches := lookupCommand(c.cmdRepo, shell.CommandPath(name, session.WorkingDir), path, true, false)
+	if len(matches) == 0 {
+		_, err = fmt.Fprintf(errorOutputWriter, "command: %s: not found\n", name)
+		return err
+	}
+
+	if matches[0].kind == kindBuiltin {
+		cmd, err := c.cmdRepo.Get(name)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "command: %s: %v\n", name, err)
+			return err
+		}
+		_, err = shell.RunBuiltin(ctx, cmd, cmdArgs, inputReader, outputWriter, errorOutputWriter)
+		return err
+	}
+
+	// failures are reported on the error output by the system command
+	_, err = shell.NewSystemCommand(c.sessionRepo, path).Execute(ctx, name, cmdArgs, inputReader, outputWriter, errorOutputWriter)
+	return err
+}
+
+// describe prints how each name would run, like command -v or -V
+func (c *CommandCommand) describe(names []string, workingDir, path string, verbose bool, outputWriter, errorOutputWriter io.Writer) error {
+	for _, name := range names {
+		matches := lookupCommand(c.cmdRepo, shell.CommandPath(name, workingDir), path, true, false)
+		if len(matches) == 0 {
+			if _, err := fmt.Fprintf(errorOutputWriter, "command: %s: not found\n", name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		line := matches[0].path
+		switch {
+		case verbose:
+			line = matches[0].describe(name)
+		case matches[0].kind == kindBuiltin:
+			line = name
+		}
+
+		if _, err := fmt.Fprintln(outputWriter, line); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Help returns the help text
+func (c *CommandCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of command
+func (c *CommandCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "command",
+		Summary: "Run a command or describe it.",
+		Description: "Runs name with the given arguments as a builtin or, when there is no such builtin, as an executable from PATH. " +
+			"In shells with aliases and functions command bypasses them; goshell has neither, so command is mostly used with -p, -v and -V.",
+		Examples: []shell.HelpExample{
+			{Command: "command -v git", Description: "Print the path of git, or nothing and an error when it is not installed."},
+			{Command: "command -p ls -l", Description: "Run ls from the standard directories whatever PATH is."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "The command succeeded, or every name was found with -v or -V."},
+			{Code: 1, Description: "The command failed or was not found."},
+		},
+		SeeAlso: []string{"builtin", "type", "which"},
+	}
+}
+
+// delegatedSecrets returns the secret arguments of the builtin that a command
+// such as command or builtin runs, as indices into args
+func delegatedSecrets(cmdRepo shell.CommandRepository, spec argparse.Spec, args []string) []int {
+	parsed, err := argparse.Parse("", spec, args)
+	if err != nil || len(parsed.Operands) == 0 {
+		return nil
+	}
+
+	cmd, err := cmdRepo.Get(parsed.Operands[0])
+	if err != nil {
+		return nil
+	}
+	secretArgs, ok := cmd.(shell.SecretArguments)
+	if !ok {
+		return nil
+	}
+
+	// the operands are the last arguments, the builtin name first
+	offset := len(args) - len(parsed.Operands) + 1
+	var secrets []int
+	for _, i := range secretArgs.SecretArguments(parsed.Operands[1:]) {
+		secrets = append(secrets, offset+i)
+	}
+	return secrets
+}
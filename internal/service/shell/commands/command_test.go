@@ -0,0 +1,111 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "tool")
+	err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0755)
+	assert.NoError(t, err)
+	t.Setenv("PATH", dir)
+
+	cases := []struct {
+		name           string
+		args           []string
+		setupRepo      func(repo *repository.CommandRepositoryMock)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "success - runs a builtin",
+			args: []string{"echo", "hello"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", "echo").Return(commands.NewEchoCommand(), nil).Twice()
+			},
+			expectedOutput: "hello\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - describe names",
+			args: []string{"-v", "echo", "tool"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", "echo").Return(&MockCommand{}, nil).Once()
+				repo.On("Get", "tool").Return(&MockCommand{}, errors.New("not found")).Once()
+			},
+			expectedOutput: "echo\n" + tool + "\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - describe names verbosely",
+			args: []string{"-V", "echo", "tool"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", "echo").Return(&MockCommand{}, nil).Once()
+				repo.On("Get", "tool").Return(&MockCommand{}, errors.New("not found")).Once()
+			},
+			expectedOutput: "echo is a shell builtin\ntool is " + tool + "\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - relative path from the working directory",
+			args: []string{"-v", "./tool"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", tool).Return(&MockCommand{}, errors.New("not found")).Once()
+			},
+			expectedOutput: tool + "\n",
+			expectedError:  "",
+		},
+		{
+			name: "failure - not found",
+			args: []string{"-v", "nonexistent"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", "nonexistent").Return(&MockCommand{}, errors.New("not found")).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "command: nonexistent: not found\n",
+		},
+		{
+			name: "failure - run not found",
+			args: []string{"nonexistent", "-x"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", "nonexistent").Return(&MockCommand{}, errors.New("not found")).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "command: nonexistent: not found\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.CommandRepositoryMock)
+			tc.setupRepo(mockRepo)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			sessionRepo := new(repository.SessionRepositoryMock)
+			sessionRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			cmd := commands.NewCommandCommand(mockRepo, sessionRepo, dir)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
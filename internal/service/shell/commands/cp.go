@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// CpCommand implements the cp command
+type CpCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewCpCommand creates a new cp command
+func NewCpCommand(sessionRepo shell.SessionRepository) *CpCommand {
+	return &CpCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *CpCommand) Name() string {
+	return "cp"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *CpCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *CpCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'r', Description: "Copy directories and their contents."},
+			{Short: 'R', Description: "Same as -r."},
+			{Short: 'n', Description: "Do not overwrite existing files."},
+			{Short: 'v', Description: "Print each file copied."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "source", Variadic: true},
+			{Name: "dest", Description: "The file to copy to, or the directory to copy the sources into."},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *CpCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	recursive := parsed.Bool("r") || parsed.Bool("R")
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	sources := parsed.Strings("source")
+	targets, err := destinationPaths(session.WorkingDir, sources, parsed.String("dest"))
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "cp: %v\n", err)
+		return err
+	}
+
+	report := func(src, dst string) error {
+		if !parsed.Bool("v") {
+			return nil
+		}
+		_, err := fmt.Fprintf(outputWriter, "'%s' -> '%s'\n", displayPath(session.WorkingDir, src), displayPath(session.WorkingDir, dst))
+		return err
+	}
+
+	for i, source := range sources {
+		if ctx.Err() != nil {
+			return &shell.ExitStatus{Code: interruptedStatus}
+		}
+
+		src, dst := resolvePath(session.WorkingDir, source), targets[i]
+		// the sources named are followed when they are symlinks
+		info, err := os.Stat(src)
+		if err != nil {
+			if err := writeFileError(errorOutputWriter, "cp", "cannot stat", session.WorkingDir, source, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var problem string
+		switch {
+		case info.IsDir() && !recursive:
+			problem = fmt.Sprintf("-r not specified; omitting directory '%s'", source)
+		case sameFile(info, dst):
+			problem = fmt.Sprintf("'%s' and '%s' are the same file", source, displayPath(session.WorkingDir, dst))
+		case info.IsDir() && isInside(dst, src):
+			problem = fmt.Sprintf("cannot copy a directory, '%s', into itself, '%s'", source, displayPath(session.WorkingDir, dst))
+		}
+		if problem != "" {
+			if _, err := fmt.Fprintf(errorOutputWriter, "cp: %s\n", problem); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = copyTree(ctx, src, dst, info, parsed.Bool("n"), report)
+		switch {
+		case err == nil:
+		case isCancelled(err):
+			return &shell.ExitStatus{Code: interruptedStatus}
+		default:
+			if err := writeFileError(errorOutputWriter, "cp", "cannot copy", session.WorkingDir, source, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// sameFile reports whether path is the file described by info
+func sameFile(info os.FileInfo, path string) bool {
+	other, err := os.Stat(path)
+	return err == nil && os.SameFile(info, other)
+}
+
+// Help returns the help text
+func (c *CpCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of cp
+func (c *CpCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "cp",
+		Summary: "Copy files and directories.",
+		Description: "Copies source to dest, or each source into the directory dest, relative to the working directory. Permissions and access and modification times are kept. " +
+			"With -r directories are copied with their contents, merging into existing ones, and symlinks inside them are copied as symlinks." +
+			"\n\n" +
+			"Files are streamed, so large ones use little memory. Ctrl-C stops cp and removes the file being copied.",
+		Examples: []shell.HelpExample{
+			{Command: "cp -r src backup", Description: "Copy a directory tree."},
+			{Command: "cp -n a.txt b.txt docs", Description: "Copy files into docs, keeping those already there."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Every source was copied."},
+			{Code: 1, Description: "A source could not be copied."},
+			{Code: 130, Description: "Ctrl-C stopped cp before every source was copied."},
+		},
+		SeeAlso: []string{"mv", "ln", "rm"},
+	}
+}
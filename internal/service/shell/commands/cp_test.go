@@ -0,0 +1,156 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCpCommand_Execute(t *testing.T) {
+	modTime := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	// a script and a tree with a file and a symlink
+	setupTree := func(dir string) {
+		os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\n"), 0750)
+		os.Chtimes(filepath.Join(dir, "run.sh"), modTime, modTime)
+		os.MkdirAll(filepath.Join(dir, "src/sub"), 0755)
+		os.WriteFile(filepath.Join(dir, "src/sub/a.txt"), []byte("a"), 0600)
+		os.Symlink("sub/a.txt", filepath.Join(dir, "src/link"))
+		os.Mkdir(filepath.Join(dir, "dest"), 0755)
+	}
+
+	cases := []struct {
+		name           string
+		args           []string
+		cancelled      bool
+		check          func(t *testing.T, dir string)
+		expectedOutput string
+		expectedError  string
+		expectedStatus int
+	}{
+		{
+			name: "success - mode and times are kept",
+			args: []string{"run.sh", "copy.sh"},
+			check: func(t *testing.T, dir string) {
+				info, err := os.Stat(filepath.Join(dir, "copy.sh"))
+				assert.NoError(t, err)
+				assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+				assert.True(t, modTime.Equal(info.ModTime()))
+			},
+		},
+		{
+			name:           "success - copy a tree verbosely",
+			args:           []string{"-rv", "src", "copy"},
+			expectedOutput: "'src/link' -> 'copy/link'\n'src/sub/a.txt' -> 'copy/sub/a.txt'\n'src/sub' -> 'copy/sub'\n'src' -> 'copy'\n",
+			check: func(t *testing.T, dir string) {
+				content, err := os.ReadFile(filepath.Join(dir, "copy/sub/a.txt"))
+				assert.NoError(t, err)
+				assert.Equal(t, "a", string(content))
+
+				target, err := os.Readlink(filepath.Join(dir, "copy/link"))
+				assert.NoError(t, err)
+				assert.Equal(t, "sub/a.txt", target)
+			},
+		},
+		{
+			name: "success - several sources into a directory",
+			args: []string{"-r", "run.sh", "src", "dest"},
+			check: func(t *testing.T, dir string) {
+				assert.FileExists(t, filepath.Join(dir, "dest/run.sh"))
+				assert.FileExists(t, filepath.Join(dir, "dest/src/sub/a.txt"))
+			},
+		},
+		{
+			name: "success - no clobber",
+			args: []string{"-n", "run.sh", "src/sub/a.txt"},
+			check: func(t *testing.T, dir string) {
+				content, err := os.ReadFile(filepath.Join(dir, "src/sub/a.txt"))
+				assert.NoError(t, err)
+				assert.Equal(t, "a", string(content))
+			},
+		},
+		{
+			name:          "failure - directory without -r",
+			args:          []string{"src", "copy"},
+			expectedError: "cp: -r not specified; omitting directory 'src'\n",
+		},
+		{
+			name:          "failure - same file",
+			args:          []string{"run.sh", "."},
+			expectedError: "cp: 'run.sh' and 'run.sh' are the same file\n",
+		},
+		{
+			name:          "failure - into itself",
+			args:          []string{"-r", "src", "src/sub"},
+			expectedError: "cp: cannot copy a directory, 'src', into itself, 'src/sub/src'\n",
+		},
+		{
+			name:          "failure - missing source",
+			args:          []string{"missing", "run.sh", "dest"},
+			expectedError: "cp: cannot stat 'missing': no such file or directory\n",
+			check: func(t *testing.T, dir string) {
+				assert.FileExists(t, filepath.Join(dir, "dest/run.sh"))
+			},
+		},
+		{
+			name:          "failure - target not a directory",
+			args:          []string{"run.sh", "src/link", "copy"},
+			expectedError: "cp: target 'copy' is not a directory\n",
+		},
+		{
+			name:          "failure - missing destination",
+			args:          []string{"run.sh"},
+			expectedError: "cp: missing dest\nusage: cp [-rRnv] <source> ... <dest>\n",
+		},
+		{
+			name:           "failure - interrupted",
+			args:           []string{"-r", "src", "copy"},
+			cancelled:      true,
+			expectedStatus: 130,
+			check: func(t *testing.T, dir string) {
+				assert.NoDirExists(t, filepath.Join(dir, "copy"))
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			setupTree(dir)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelled {
+				cancel()
+			}
+
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewCpCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			if tc.expectedStatus == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, &shell.ExitStatus{Code: tc.expectedStatus}, err)
+			}
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			if tc.check != nil {
+				tc.check(t, dir)
+			}
+		})
+	}
+}
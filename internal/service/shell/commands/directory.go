@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+)
+
+// errNotDirectory reports a cd target that exists but is not a directory
+var errNotDirectory = errors.New("not a directory")
+
+// homeDir returns $HOME, falling back to the home directory of the current user
+func homeDir() (string, error) {
+	if home := os.Getenv("HOME"); home != "" {
+		return home, nil
+	}
+	return os.UserHomeDir()
+}
+
+// expandTilde expands a leading ~ or ~user to a home directory
+func expandTilde(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+
+	name, rest, _ := strings.Cut(path[1:], "/")
+	var home string
+	if name == "" {
+		h, err := homeDir()
+		if err != nil {
+			return "", err
+		}
+		home = h
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", fmt.Errorf("no such user: %s", name)
+		}
+		home = u.HomeDir
+	}
+
+	return filepath.Join(home, rest), nil
+}
+
+// resolvePath makes path absolute relative to workingDir. The result is
+// logical: ".." removes the previous component even when it is a symlink.
+func resolvePath(workingDir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(workingDir, path)
+}
+
+// isDirectory reports whether path is an existing directory
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// findDirectory resolves a cd operand to a directory. Relative operands that
+// do not start with . or .. are looked up in CDPATH first; found reports
+// whether a non-empty CDPATH entry was used, in which case cd prints the new
+// directory. With physical set symlinks are resolved.
+func findDirectory(workingDir, operand string, physical bool) (dir string, found bool, err error) {
+	operand, err = expandTilde(operand)
+	if err != nil {
+		return "", false, err
+	}
+
+	dir = resolvePath(workingDir, operand)
+	if !filepath.IsAbs(operand) && !isDotRelative(operand) {
+		for _, entry := range filepath.SplitList(os.Getenv("CDPATH")) {
+			candidate := resolvePath(workingDir, filepath.Join(entry, operand))
+			if isDirectory(candidate) {
+				dir, found = candidate, entry != "" && entry != "."
+				break
+			}
+		}
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", false, err
+	}
+	if !info.IsDir() {
+		return "", false, errNotDirectory
+	}
+
+	if physical {
+		dir, err = filepath.EvalSymlinks(dir)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	return dir, found, nil
+}
+
+// isDotRelative reports whether path starts with a . or .. component
+func isDotRelative(path string) bool {
+	return path == "." || path == ".." || strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../")
+}
+
+// changeWorkingDir makes dir the working directory of the session, keeping
+// the previous one for cd -, exporting PWD and OLDPWD to commands, and
+// recording the visit for z
+func changeWorkingDir(sessionRepo shell.SessionRepository, frecencySVC *frecency.Service, session shell.Session, dir string, errorOutputWriter io.Writer) error {
+	session.OldWorkingDir = session.WorkingDir
+	session.WorkingDir = dir
+
+	if err := sessionRepo.SetSession(session); err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error updating session: %v\n", err)
+		return err
+	}
+
+	os.Setenv("OLDPWD", session.OldWorkingDir)
+	os.Setenv("PWD", session.WorkingDir)
+	recordVisit(frecencySVC, session)
+
+	return nil
+}
+
+// recordVisit records the working directory of session for z. Scores are a
+// convenience, so failures are not reported.
+func recordVisit(frecencySVC *frecency.Service, session shell.Session) {
+	_ = frecencySVC.Visit(sessionUserID(session), session.WorkingDir)
+}
+
+// sessionUserID returns the ID of the session user, nil for the guest
+func sessionUserID(session shell.Session) *int64 {
+	if session.User != nil {
+		return &session.User.ID
+	}
+	return nil
+}
+
+// dirStack returns the working directory followed by the directory stack
+func dirStack(session shell.Session) []string {
+	return append([]string{session.WorkingDir}, session.DirStack...)
+}
+
+// parseStackIndex parses a +N or -N directory stack argument. +N counts from
+// the top of the stack, the working directory being +0, and -N from the bottom.
+func parseStackIndex(arg string, size int) (int, bool, error) {
+	if len(arg) < 2 || (arg[0] != '+' && arg[0] != '-') {
+		return 0, false, nil
+	}
+
+	n, err := strconv.Atoi(arg[1:])
+	if err != nil || n < 0 {
+		return 0, false, nil
+	}
+	if n >= size {
+		return 0, true, fmt.Errorf("%s: directory stack index out of range", arg)
+	}
+
+	if arg[0] == '-' {
+		n = size - 1 - n
+	}
+	return n, true, nil
+}
+
+// abbreviateHome replaces the home directory prefix of dir with ~
+func abbreviateHome(dir string) string {
+	home, err := homeDir()
+	if err != nil || home == "" || home == "/" {
+		return dir
+	}
+
+	if dir == home {
+		return "~"
+	}
+	if rest, ok := strings.CutPrefix(dir, home+string(filepath.Separator)); ok {
+		return "~" + string(filepath.Separator) + rest
+	}
+	return dir
+}
+
+// printDirStack prints the directory stack on one line like dirs
+func printDirStack(session shell.Session, outputWriter io.Writer) error {
+	stack := dirStack(session)
+	for i, dir := range stack {
+		stack[i] = abbreviateHome(dir)
+	}
+
+	_, err := fmt.Fprintln(outputWriter, strings.Join(stack, " "))
+	return err
+}
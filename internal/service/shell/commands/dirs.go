@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// DirsCommand implements the dirs command
+type DirsCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewDirsCommand creates a new dirs command
+func NewDirsCommand(sessionRepo shell.SessionRepository) *DirsCommand {
+	return &DirsCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *DirsCommand) Name() string {
+	return "dirs"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *DirsCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *DirsCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'c', Description: "Clear the directory stack."},
+			{Short: 'l', Description: "Show full paths instead of abbreviating the home directory."},
+			{Short: 'p', Description: "Print one entry per line."},
+			{Short: 'v', Description: "Print one entry per line, preceded by its position."},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *DirsCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	long, perLine, verbose := parsed.Bool("l"), parsed.Bool("p"), parsed.Bool("v")
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	if parsed.Bool("c") {
+		session.DirStack = nil
+		if err := c.sessionRepo.SetSession(session); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error updating session: %v\n", err)
+			return err
+		}
+		return nil
+	}
+
+	if !long && !perLine && !verbose {
+		return printDirStack(session, outputWriter)
+	}
+
+	for i, dir := range dirStack(session) {
+		if !long {
+			dir = abbreviateHome(dir)
+		}
+		if verbose {
+			_, err = fmt.Fprintf(outputWriter, "%2d  %s\n", i, dir)
+		} else {
+			_, err = fmt.Fprintln(outputWriter, dir)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Help returns the help text
+func (c *DirsCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of dirs
+func (c *DirsCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "dirs",
+		Summary:     "Show the directory stack.",
+		Description: "Prints the directory stack kept by pushd and popd, the working directory first. Entries are numbered from 0 at the top; the home directory is shown as ~.",
+		Examples: []shell.HelpExample{
+			{Command: "dirs -v", Description: "Show the numbered stack, to use with pushd +N or popd +N."},
+		},
+		SeeAlso: []string{"pushd", "popd", "cd"},
+	}
+}
@@ -0,0 +1,83 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDirsCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Setenv("HOME", "/home/alice")
+	session := shell.Session{WorkingDir: "/home/alice/src", DirStack: []string{"/tmp", "/home/alice"}}
+
+	cases := []struct {
+		name           string
+		args           []string
+		expectClear    bool
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - one line with home abbreviated",
+			expectedOutput: "~/src /tmp ~\n",
+		},
+		{
+			name:           "success - long names",
+			args:           []string{"-l"},
+			expectedOutput: "/home/alice/src\n/tmp\n/home/alice\n",
+		},
+		{
+			name:           "success - one entry per line",
+			args:           []string{"-p"},
+			expectedOutput: "~/src\n/tmp\n~\n",
+		},
+		{
+			name:           "success - numbered entries",
+			args:           []string{"-v"},
+			expectedOutput: " 0  ~/src\n 1  /tmp\n 2  ~\n",
+		},
+		{
+			name:        "success - clear the stack",
+			args:        []string{"-c"},
+			expectClear: true,
+		},
+		{
+			name:          "failure - unknown flag",
+			args:          []string{"-x"},
+			expectedError: "dirs: invalid option -- 'x'\nusage: dirs [-clpv]\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.SessionRepositoryMock)
+			if tc.expectedError == "" {
+				mockRepo.On("GetSession").Return(session, nil).Once()
+			}
+			if tc.expectClear {
+				mockRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return len(s.DirStack) == 0 && s.WorkingDir == session.WorkingDir
+				})).Return(nil).Once()
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewDirsCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
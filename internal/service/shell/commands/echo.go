@@ -7,6 +7,8 @@ import (
 	"io"
 	"os"
 	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
 )
 
 // EchoCommand implements the echo command
@@ -50,72 +52,54 @@ func (e *EchoCommand) Execute(ctx context.Context, args []string, inputReader io
 			_, err = fmt.Fprintf(errorOutputWriter, "error reading input: %v\n", err)
 			return err
 		}
-	} else {
-		// Process arguments as before
-		for _, arg := range args {
-			if strings.HasPrefix(arg, "'") && strings.HasSuffix(arg, "'") {
-				_, err := output.WriteString(strings.Trim(arg, "'"))
-				if err != nil {
-					return err
-				}
-
-			} else {
-				var expandedArg strings.Builder
-				var inEnvVar bool
-				var envVarName strings.Builder
-
-				for _, char := range arg {
-					if char == '$' && !inEnvVar {
-						inEnvVar = true
-						envVarName.Reset()
-					} else if inEnvVar && (('a' <= char && char <= 'z') || ('A' <= char && char <= 'Z') || ('0' <= char && char <= '9') || char == '_') {
-						_, err := envVarName.WriteRune(char)
-						if err != nil {
-							return err
-						}
-
-					} else if inEnvVar {
-						envVar := os.Getenv(envVarName.String())
-						_, err := expandedArg.WriteString(envVar)
-						if err != nil {
-							return err
-						}
-
-						_, err = expandedArg.WriteRune(char)
-						if err != nil {
-							return err
-						}
-
-						inEnvVar = false
-						envVarName.Reset()
-					} else {
-						_, err := expandedArg.WriteRune(char)
-						if err != nil {
-							return err
-						}
-					}
-				}
-				if inEnvVar {
-					_, err := expandedArg.WriteString(os.Getenv(envVarName.String()))
-					if err != nil {
-						return err
-					}
-				}
-
-				_, err := output.WriteString(expandedArg.String())
-				if err != nil {
-					return err
-				}
+
+		_, err := fmt.Fprintln(outputWriter, strings.TrimSpace(output.String()))
+		if err != nil {
+			_, err := fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+			return err
+		}
+
+		return nil
+	}
+
+	// leading -n, -e and -E options, which may be combined as in -ne
+	escapes, newline := false, true
+	for len(args) > 0 && isEchoOption(args[0]) {
+		for _, flag := range args[0][1:] {
+			switch flag {
+			case 'n':
+				newline = false
+			case 'e':
+				escapes = true
+			case 'E':
+				escapes = false
 			}
+		}
+		args = args[1:]
+	}
 
-			_, err := output.WriteString(" ")
-			if err != nil {
-				return err
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		part := expandArgument(arg)
+
+		// \c ends the output, including the newline
+		if escapes {
+			var stop bool
+			part, stop = expandEscapes(part, false)
+			if stop {
+				parts, newline = append(parts, part), false
+				break
 			}
 		}
+		parts = append(parts, part)
+	}
+
+	output.WriteString(strings.Join(parts, " "))
+	if newline {
+		output.WriteString("\n")
 	}
 
-	_, err := fmt.Fprintln(outputWriter, strings.TrimSpace(output.String()))
+	_, err := io.WriteString(outputWriter, output.String())
 	if err != nil {
 		_, err := fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
 		return err
@@ -124,7 +108,66 @@ func (e *EchoCommand) Execute(ctx context.Context, args []string, inputReader io
 	return nil
 }
 
+// isEchoOption reports whether arg is made only of echo options
+func isEchoOption(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-' && strings.Trim(arg[1:], "neE") == ""
+}
+
+// expandArgument strips the quotes of a single-quoted argument and expands
+// environment variables in the others
+func expandArgument(arg string) string {
+	if len(arg) >= 2 && strings.HasPrefix(arg, "'") && strings.HasSuffix(arg, "'") {
+		return arg[1 : len(arg)-1]
+	}
+
+	var expanded strings.Builder
+	var envVarName strings.Builder
+	inEnvVar := false
+
+	for _, char := range arg {
+		switch {
+		case char == '$' && !inEnvVar:
+			inEnvVar = true
+			envVarName.Reset()
+		case inEnvVar && (('a' <= char && char <= 'z') || ('A' <= char && char <= 'Z') || ('0' <= char && char <= '9') || char == '_'):
+			envVarName.WriteRune(char)
+		case inEnvVar:
+			expanded.WriteString(os.Getenv(envVarName.String()))
+			expanded.WriteRune(char)
+			inEnvVar = false
+		default:
+			expanded.WriteRune(char)
+		}
+	}
+	if inEnvVar {
+		expanded.WriteString(os.Getenv(envVarName.String()))
+	}
+
+	return expanded.String()
+}
+
 // Help returns the help text
 func (e *EchoCommand) Help() string {
-	return "echo [args...] - Prints the provided arguments to the output, supports environment variables and multiple expressions"
+	return shell.HelpLine(e.Doc())
+}
+
+// Doc returns the documentation of echo
+func (e *EchoCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "echo",
+		Summary:     "Print the arguments.",
+		Synopsis:    []string{"echo [-neE] [args ...]"},
+		Description: "Prints the arguments separated by single spaces and followed by a newline. Environment variables such as $HOME are expanded, except in arguments quoted with single quotes. Without arguments echo copies its input.",
+		Flags: []shell.HelpFlag{
+			{Flag: "-n", Description: "Do not print the trailing newline."},
+			{Flag: "-e", Description: "Interpret backslash escapes: \\a \\b \\e \\f \\n \\r \\t \\v \\\\, \\xHH, \\uHHHH, \\0NNN in octal, and \\c which stops all further output."},
+			{Flag: "-E", Description: "Do not interpret backslash escapes (default)."},
+		},
+		Examples: []shell.HelpExample{
+			{Command: "echo Hello $USER", Description: "Greet the current user."},
+			{Command: "echo -e \"name:\\tvalue\"", Description: "Print a tab separated line."},
+			{Command: "echo -n prompt", Description: "Print without a newline."},
+		},
+		SeeAlso: []string{"printf"},
+	}
 }
@@ -51,9 +51,39 @@ func TestEchoCommand_Execute(t *testing.T) {
 		{
 			name:           "ignores undefined environment variables",
 			args:           []string{"Hello", "$UNDEFINED_VAR"},
-			expectedOutput: "Hello\n",
+			expectedOutput: "Hello \n",
 			expectedError:  "",
 		},
+		{
+			name:           "keeps the spacing of arguments",
+			args:           []string{"  a", "b  "},
+			expectedOutput: "  a b  \n",
+		},
+		{
+			name:           "omits the newline with -n",
+			args:           []string{"-n", "Hello"},
+			expectedOutput: "Hello",
+		},
+		{
+			name:           "interprets escapes with -e",
+			args:           []string{"-e", `a\tb\n\x41\0101\\`},
+			expectedOutput: "a\tb\nAA\\\n",
+		},
+		{
+			name:           "stops output at \\c",
+			args:           []string{"-ne", `one\ctwo`, "three"},
+			expectedOutput: "one",
+		},
+		{
+			name:           "leaves escapes with -E",
+			args:           []string{"-e", "-E", `a\tb`},
+			expectedOutput: "a\\tb\n",
+		},
+		{
+			name:           "prints arguments that are not options",
+			args:           []string{"-x", "-n"},
+			expectedOutput: "-x -n\n",
+		},
 		{
 			name:           "returns empty string when no arguments",
 			args:           []string{},
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// expandEscapes replaces backslash escapes like echo -e and printf do: \a \b
+// \e \f \n \r \t \v \\, \xHH, \uHHHH, \UHHHHHHHH and octal values, written
+// \0NNN for echo and %b or \NNN in printf formats. It reports whether \c was
+// found, in which case the text stops there and no further output is
+// produced.
+func expandEscapes(s string, printfFormat bool) (string, bool) {
+	if !strings.Contains(s, "\\") {
+		return s, false
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch c := s[i]; c {
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'e', 'E':
+			b.WriteByte(0x1b)
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case '\\':
+			b.WriteByte('\\')
+		case '"':
+			if !printfFormat {
+				b.WriteByte('\\')
+			}
+			b.WriteByte('"')
+		case 'c':
+			return b.String(), true
+		case 'x':
+			value, n := parseDigits(s[i+1:], 16, 2)
+			if n == 0 {
+				b.WriteString("\\x")
+				continue
+			}
+			b.WriteByte(byte(value))
+			i += n
+		case 'u', 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+			value, n := parseDigits(s[i+1:], 16, size)
+			if n == 0 || !utf8.ValidRune(rune(value)) {
+				b.WriteByte('\\')
+				b.WriteByte(c)
+				continue
+			}
+			b.WriteRune(rune(value))
+			i += n
+		default:
+			// octal: \0NNN for echo and %b, \NNN in printf formats
+			if c == '0' && !printfFormat {
+				value, n := parseDigits(s[i+1:], 8, 3)
+				b.WriteByte(byte(value))
+				i += n
+				continue
+			}
+			if printfFormat && c >= '0' && c <= '7' {
+				value, n := parseDigits(s[i:], 8, 3)
+				b.WriteByte(byte(value))
+				i += n - 1
+				continue
+			}
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), false
+}
+
+// parseDigits parses up to max leading digits of s in base and returns the
+// value and the number of digits used
+func parseDigits(s string, base, max int) (int64, int) {
+	n := 0
+	for n < len(s) && n < max && isDigitIn(s[n], base) {
+		n++
+	}
+	if n == 0 {
+		return 0, 0
+	}
+
+	value, _ := strconv.ParseInt(s[:n], base, 64)
+	return value, n
+}
+
+func isDigitIn(c byte, base int) bool {
+	switch base {
+	case 8:
+		return c >= '0' && c <= '7'
+	default:
+		return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+	}
+}
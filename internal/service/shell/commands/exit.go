@@ -4,25 +4,18 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
-	"strconv"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // ExitCommand implements the exit command
 type ExitCommand struct {
-	onExit   func(int) // Callback for exit
-	exitFunc func(int) // Allows overriding os.Exit
 }
 
-// New creates a new exit command
-func NewExitCommand(
-	onExit func(int),
-	exitFunc func(int),
-) *ExitCommand {
-	return &ExitCommand{
-		onExit:   onExit,
-		exitFunc: exitFunc,
-	}
+// NewExitCommand creates a new exit command
+func NewExitCommand() *ExitCommand {
+	return &ExitCommand{}
 }
 
 // Name returns the command name
@@ -35,39 +28,50 @@ func (c *ExitCommand) MaxArguments() int {
 	return 1
 }
 
-// Execute runs the command
-func (c *ExitCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
-	exitCode := 0 // Default exit code
-
-	if len(args) > 0 {
-		code, err := strconv.Atoi(args[0])
-		if err != nil {
-			_, err = fmt.Fprintf(errorOutputWriter, "Invalid exit code: %s\n", args[0])
-			return err
-		}
-		exitCode = code
+// Arguments declares the arguments of the command
+func (c *ExitCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Positionals: []argparse.Positional{
+			{Name: "code", Type: argparse.Int, Optional: true, Description: "The exit status, 0 when not given."},
+		},
 	}
+}
 
-	if c.onExit != nil {
-		c.onExit(exitCode)
+// Execute runs the command
+func (c *ExitCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
 	}
+	exitCode := parsed.Int("code")
 
-	_, err := fmt.Fprintf(outputWriter, "exit status %d\n", exitCode)
+	_, err = fmt.Fprintf(outputWriter, "exit status %d\n", exitCode)
 	if err != nil {
 		_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
 		return err
 	}
 
-	if c.exitFunc != nil {
-		c.exitFunc(exitCode)
-	} else {
-		os.Exit(exitCode) // Default
-	}
-
-	return nil
+	// the shell exits after recording the command
+	return &shell.ExitShell{Code: exitCode}
 }
 
 // Help returns the help text
 func (c *ExitCommand) Help() string {
-	return "exit [code] - Exit the shell with optional exit code"
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of exit
+func (c *ExitCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "exit",
+		Summary:     "Exit the shell.",
+		Description: "Waits for pending history work, then exits the shell with code, 0 by default.",
+		Examples: []shell.HelpExample{
+			{Command: "exit 2", Description: "Exit with status 2."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Default when no code is given; otherwise the shell exits with the given code."},
+			{Code: 2, Description: "The code is not a number; the shell does not exit."},
+		},
+	}
 }
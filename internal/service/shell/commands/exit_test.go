@@ -5,6 +5,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,24 +20,23 @@ func TestExitCommand_Execute(t *testing.T) {
 	}{
 		{"Default exit code", []string{}, 0, "exit status 0\n", ""},
 		{"Valid exit code", []string{"5"}, 5, "exit status 5\n", ""},
-		{"Invalid exit code", []string{"abc"}, 0, "", "Invalid exit code: abc\n"},
+		{"Invalid exit code", []string{"abc"}, 0, "", "exit: invalid code: abc\nusage: exit [code]\n"},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			var capturedExitCode int
-			exitCmd := commands.NewExitCommand(nil, func(code int) {
-				capturedExitCode = code // Capture the exit code
-			})
+			exitCmd := commands.NewExitCommand()
 
 			var outputBuffer bytes.Buffer
 			var errorBuffer bytes.Buffer
 
 			err := exitCmd.Execute(context.Background(), tc.args, nil, &outputBuffer, &errorBuffer)
 
-			assert.NoError(t, err)
-
-			assert.Equal(t, tc.expectedExit, capturedExitCode)
+			if tc.expectedError == "" {
+				assert.Equal(t, &shell.ExitShell{Code: tc.expectedExit}, err)
+			} else {
+				assert.NoError(t, err)
+			}
 			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
 			assert.Equal(t, tc.expectedError, errorBuffer.String())
 		})
@@ -0,0 +1,223 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The filesystem builtins mkdir, rm, cp, mv, touch and ln resolve their
+// operands against the working directory of the session, stop between files
+// and while copying when the command is interrupted, and report each failed
+// file on the error output before going on with the next operand.
+
+// displayPath returns path as shown in messages: relative to workingDir when
+// it is inside it, like the operands typed, otherwise absolute
+func displayPath(workingDir, path string) string {
+	if rel, err := filepath.Rel(workingDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// writeFileError reports a failed operation on operand as
+// "cmd: action 'file': reason". The file is the one the error is about,
+// which is inside operand when a tree is copied or removed.
+func writeFileError(w io.Writer, cmd, action, workingDir, operand string, err error) error {
+	file := operand
+	var pathErr *fs.PathError
+	var linkErr *os.LinkError
+	switch {
+	case errors.As(err, &pathErr):
+		file, err = displayPath(workingDir, pathErr.Path), pathErr.Err
+	case errors.As(err, &linkErr):
+		file, err = displayPath(workingDir, linkErr.New), linkErr.Err
+	}
+
+	_, err = fmt.Fprintf(w, "%s: %s '%s': %v\n", cmd, action, file, err)
+	return err
+}
+
+// interruptedStatus is the exit status of a filesystem builtin stopped by
+// Ctrl-C before it was done
+const interruptedStatus = 130 // 128 + SIGINT
+
+// isCancelled reports whether err comes from the interrupted command
+func isCancelled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// destinationPaths returns where each source goes when copied or moved to
+// dest: into dest when it is a directory, which it must be for several
+// sources, otherwise dest itself
+func destinationPaths(workingDir string, sources []string, dest string) ([]string, error) {
+	destPath := resolvePath(workingDir, dest)
+	if !isDirectory(destPath) {
+		if len(sources) > 1 {
+			return nil, fmt.Errorf("target '%s' is not a directory", dest)
+		}
+		return []string{destPath}, nil
+	}
+
+	paths := make([]string, len(sources))
+	for i, source := range sources {
+		paths[i] = filepath.Join(destPath, filepath.Base(resolvePath(workingDir, source)))
+	}
+	return paths, nil
+}
+
+// isInside reports whether path is dir or inside it
+func isInside(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// copyTree copies src, described by info, to dst. Directories are copied
+// with their contents and symlinks as symlinks; modes and times are kept.
+// An existing directory at dst is merged into, and existing files are left
+// alone with noClobber. report is called for every entry copied.
+func copyTree(ctx context.Context, src, dst string, info fs.FileInfo, noClobber bool, report func(src, dst string) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, err := os.Lstat(dst); err == nil && noClobber && !info.IsDir() {
+		return nil
+	}
+
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		// like the other files, an existing one is replaced
+		if err := os.Remove(dst); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if err := os.Symlink(target, dst); err != nil {
+			return err
+		}
+
+	case info.IsDir():
+		if err := copyDir(ctx, src, dst, info, noClobber, report); err != nil {
+			return err
+		}
+
+	default:
+		if err := copyFile(ctx, src, dst, info); err != nil {
+			return err
+		}
+	}
+
+	return report(src, dst)
+}
+
+// copyDir copies the contents of the directory src to dst, then gives dst
+// the mode and times of src
+func copyDir(ctx context.Context, src, dst string, info fs.FileInfo, noClobber bool, report func(src, dst string) error) error {
+	// the directory stays writable until its contents are copied
+	if err := os.Mkdir(dst, info.Mode().Perm()|0700); err != nil && !(errors.Is(err, fs.ErrExist) && isDirectory(dst)) {
+		return err
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		entryInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if err := copyTree(ctx, filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name()), entryInfo, noClobber, report); err != nil {
+			return err
+		}
+	}
+
+	return preserveAttributes(dst, info)
+}
+
+// copyFile copies the regular file src to dst, streaming its contents. A
+// partly written file is removed when the copy fails.
+func copyFile(ctx context.Context, src, dst string, info fs.FileInfo) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	if _, err := io.Copy(out, &contextReader{ctx: ctx, r: in}); err != nil {
+		return err
+	}
+	return preserveAttributes(dst, info)
+}
+
+// preserveAttributes gives path the permissions and times of info. The mode
+// is set explicitly since the umask applies when files are created.
+func preserveAttributes(path string, info fs.FileInfo) error {
+	if err := os.Chmod(path, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(path, accessTime(info), info.ModTime())
+}
+
+// removeTree removes path and, when it is a directory, its contents first.
+// report is called for every entry removed.
+func removeTree(ctx context.Context, path string, report func(path string, dir bool) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := removeTree(ctx, filepath.Join(path, entry.Name()), report); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return report(path, info.IsDir())
+}
+
+// contextReader stops reading once ctx is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,18 @@
+//go:build linux
+
+package commands
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// accessTime returns the time the file was last read
+func accessTime(info fs.FileInfo) time.Time {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime()
+	}
+	return time.Unix(st.Atim.Sec, st.Atim.Nsec)
+}
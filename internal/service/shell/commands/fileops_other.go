@@ -0,0 +1,14 @@
+//go:build !linux
+
+package commands
+
+import (
+	"io/fs"
+	"time"
+)
+
+// accessTime returns the time the file was last read. Only the
+// modification time is portable, so it is used instead.
+func accessTime(info fs.FileInfo) time.Time {
+	return info.ModTime()
+}
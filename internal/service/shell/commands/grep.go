@@ -0,0 +1,290 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// Exit statuses of grep
+const (
+	grepNoMatch = 1
+	grepError   = 2
+)
+
+// GrepCommand implements the grep command
+type GrepCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewGrepCommand creates a new grep command
+func NewGrepCommand(sessionRepo shell.SessionRepository) *GrepCommand {
+	return &GrepCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *GrepCommand) Name() string {
+	return "grep"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *GrepCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *GrepCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'i', Long: "ignore-case", Description: "Ignore case when matching."},
+			{Short: 'v', Long: "invert-match", Description: "Select the lines that do not match."},
+			{Short: 'n', Long: "line-number", Description: "Print the line number before each line."},
+			{Short: 'r', Long: "recursive", Description: "Search the files in directories and their subdirectories, the working directory when no file is given."},
+			{Short: 'c', Long: "count", Description: "Print the number of selected lines of each file instead of the lines."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "pattern", Description: "A regular expression in Go syntax, matched anywhere in a line."},
+			{Name: "file", Optional: true, Variadic: true},
+		},
+	}
+}
+
+// grepSearch holds what one grep looks for and how it prints it
+type grepSearch struct {
+	pattern  *regexp.Regexp
+	invert   bool
+	numbers  bool
+	count    bool
+	matched  bool // a line was selected in any file
+	failed   bool // an error was reported
+	errorOut io.Writer
+}
+
+// Execute runs the command
+func (c *GrepCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	expr := parsed.String("pattern")
+	if parsed.Bool("i") {
+		expr = "(?i)" + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		reason := err.Error()
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			reason = syntaxErr.Code.String()
+		}
+		if _, err := fmt.Fprintf(errorOutputWriter, "grep: invalid pattern '%s': %s\n", parsed.String("pattern"), reason); err != nil {
+			return err
+		}
+		return &shell.ExitStatus{Code: grepError}
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	search := &grepSearch{
+		pattern:  pattern,
+		invert:   parsed.Bool("v"),
+		numbers:  parsed.Bool("n"),
+		count:    parsed.Bool("c"),
+		errorOut: errorOutputWriter,
+	}
+
+	operands := parsed.Strings("file")
+	recursive := parsed.Bool("r")
+	if len(operands) == 0 && recursive {
+		operands = []string{""}
+	}
+	operands = operandsOrInput(operands)
+
+	out := bufio.NewWriter(outputWriter)
+	for _, operand := range operands {
+		path := resolvePath(session.WorkingDir, operand)
+		named := len(operands) > 1
+
+		var err error
+		if recursive && operand != stdinOperand && isDirectory(path) {
+			err = search.walk(ctx, session.WorkingDir, operand, out)
+		} else {
+			err = search.file(ctx, session.WorkingDir, operand, named, inputReader, out)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := out.Flush(); err != nil {
+		return search.fail("", err)
+	}
+	switch {
+	case ctx.Err() != nil:
+		return nil
+	case search.failed:
+		return &shell.ExitStatus{Code: grepError}
+	case !search.matched:
+		return &shell.ExitStatus{Code: grepNoMatch}
+	}
+	return nil
+}
+
+// walk searches the files of the directory operand and its subdirectories.
+// Symlinks inside it are not followed. The files are named relative to the
+// working directory when no directory was given.
+func (s *grepSearch) walk(ctx context.Context, workingDir, operand string, out *bufio.Writer) error {
+	root := resolvePath(workingDir, operand)
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return filepath.SkipAll
+		}
+
+		name := path
+		if rel, relErr := filepath.Rel(root, path); relErr == nil {
+			name = rel
+			if operand != "" {
+				name = filepath.Join(operand, rel)
+				if strings.HasPrefix(operand, "./") || operand == "." {
+					// keep the ./ typed, which Join drops
+					name = "./" + name
+				}
+			}
+		}
+
+		if err != nil {
+			return s.fail(name, &inputError{err: err})
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		return s.file(ctx, workingDir, name, true, nil, out)
+	})
+}
+
+// file searches one file, or the input for -, printing the lines selected
+// prefixed with the file name when named is set. Errors reading it are
+// reported; nil is returned unless the output failed.
+func (s *grepSearch) file(ctx context.Context, workingDir, operand string, named bool, inputReader io.Reader, out *bufio.Writer) error {
+	r, f, err := openOperand(ctx, workingDir, operand, inputReader)
+	if err != nil {
+		return s.fail(operand, err)
+	}
+	if f != nil {
+		defer f.Close()
+	}
+
+	name := operand
+	if operand == stdinOperand {
+		name = "(standard input)"
+	}
+	prefix := ""
+	if named {
+		prefix = name + ":"
+	}
+
+	br := bufio.NewReader(r)
+	var selected int64
+	for number := int64(1); ; number++ {
+		line, readErr := br.ReadBytes('\n')
+		if len(line) > 0 {
+			text := bytes.TrimSuffix(line, []byte("\n"))
+			if s.pattern.Match(text) != s.invert {
+				selected++
+				s.matched = true
+
+				switch {
+				case s.count:
+				case bytes.IndexByte(text, 0) >= 0:
+					// binary files are not printed
+					fmt.Fprintf(out, "Binary file %s matches\n", name)
+					if err := out.Flush(); err != nil {
+						return s.fail(operand, err)
+					}
+					return nil
+				default:
+					out.WriteString(prefix)
+					if s.numbers {
+						fmt.Fprintf(out, "%d:", number)
+					}
+					writeLine(out, line)
+				}
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			out.Flush()
+			return s.fail(operand, readErr)
+		}
+		if err := flushIfIdle(out, br); err != nil {
+			return s.fail(operand, err)
+		}
+	}
+
+	if s.count {
+		fmt.Fprintf(out, "%s%d\n", prefix, selected)
+	}
+	return nil
+}
+
+// fail reports err, returned while searching operand, and returns the error
+// that ends grep: nil for input errors, after which the search goes on, and
+// for an interrupted search
+func (s *grepSearch) fail(operand string, err error) error {
+	done, err := reportInputError(s.errorOut, "grep", operand, err)
+	if !done {
+		s.failed = true
+		return nil
+	}
+	return err
+}
+
+// Help returns the help text
+func (c *GrepCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of grep
+func (c *GrepCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "grep",
+		Summary: "Print lines matching a pattern.",
+		Description: "Prints the lines of each file relative to the working directory, or of the input when there is no file or it is -, that match pattern. " +
+			"The pattern is a Go regular expression (RE2 syntax, as in https://golang.org/s/re2syntax); use ^ and $ to match a whole line. " +
+			"With several files, or with -r, each line is prefixed with its file name." +
+			"\n\n" +
+			"Files are read a line at a time. A file containing NUL bytes is reported as a binary file instead of printing its lines. Ctrl-C stops the search.",
+		Examples: []shell.HelpExample{
+			{Command: "grep -n TODO main.go", Description: "Print the lines containing TODO with their numbers."},
+			{Command: "grep -ri \"func (new|open)\" internal", Description: "Search a directory tree, ignoring case."},
+			{Command: "grep -vc \"^#\" config.ini", Description: "Count the lines that are not comments."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "A line was selected."},
+			{Code: grepNoMatch, Description: "No line was selected."},
+			{Code: grepError, Description: "An error was reported."},
+		},
+		SeeAlso: []string{"cat", "wc"},
+	}
+}
@@ -0,0 +1,119 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrepCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("TODO: write tests\ndone\ntodo later\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "src/sub"), 0755)
+	os.WriteFile(filepath.Join(dir, "src/a.go"), []byte("package a\n// TODO x\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "src/sub/b.go"), []byte("package b\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "src/data.bin"), []byte("TODO\x00\x01\n"), 0644)
+
+	cases := []struct {
+		name           string
+		args           []string
+		input          string
+		dir            string
+		expectedOutput string
+		expectedError  string
+		expectedStatus int
+	}{
+		{
+			name:           "success - match in the input",
+			args:           []string{"b"},
+			input:          "abc\nxyz\nb",
+			expectedOutput: "abc\nb\n",
+		},
+		{
+			name:           "success - regular expression in a file",
+			args:           []string{"^[a-z]+$", "notes.txt"},
+			expectedOutput: "done\n",
+		},
+		{
+			name:           "success - ignore case with line numbers",
+			args:           []string{"-in", "todo", "notes.txt"},
+			expectedOutput: "1:TODO: write tests\n3:todo later\n",
+		},
+		{
+			name:           "success - invert and count",
+			args:           []string{"-vc", "TODO", "notes.txt"},
+			expectedOutput: "2\n",
+		},
+		{
+			name:           "success - several files are named",
+			args:           []string{"package", "src/a.go", "src/sub/b.go"},
+			expectedOutput: "src/a.go:package a\nsrc/sub/b.go:package b\n",
+		},
+		{
+			name:           "success - recursive",
+			args:           []string{"-r", "TODO", "src"},
+			expectedOutput: "src/a.go:// TODO x\nBinary file src/data.bin matches\n",
+		},
+		{
+			name:           "success - recursive in the working directory",
+			args:           []string{"-rc", "package"},
+			dir:            "src",
+			expectedOutput: "a.go:1\ndata.bin:0\nsub/b.go:1\n",
+		},
+		{
+			name:           "failure - no match",
+			args:           []string{"missing", "notes.txt"},
+			expectedStatus: 1,
+		},
+		{
+			name:           "failure - missing file",
+			args:           []string{"done", "missing.txt", "notes.txt"},
+			expectedOutput: "notes.txt:done\n",
+			expectedError:  "grep: missing.txt: no such file or directory\n",
+			expectedStatus: 2,
+		},
+		{
+			name:           "failure - directory without recursion",
+			args:           []string{"x", "src"},
+			expectedError:  "grep: src: is a directory\n",
+			expectedStatus: 2,
+		},
+		{
+			name:           "failure - invalid pattern",
+			args:           []string{"(", "notes.txt"},
+			expectedError:  "grep: invalid pattern '(': missing closing )\n",
+			expectedStatus: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: filepath.Join(dir, tc.dir)}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewGrepCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.input), &outputBuffer, &errorBuffer)
+
+			if tc.expectedStatus == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, &shell.ExitStatus{Code: tc.expectedStatus}, err)
+			}
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// HashCommand implements the hash command
+type HashCommand struct {
+	cmdRepo     shell.CommandRepository
+	sessionRepo shell.SessionRepository
+	path        string
+}
+
+// NewHashCommand creates a new hash command for the executables in path
+func NewHashCommand(cmdRepo shell.CommandRepository, sessionRepo shell.SessionRepository, path string) *HashCommand {
+	return &HashCommand{
+		cmdRepo:     cmdRepo,
+		sessionRepo: sessionRepo,
+		path:        path,
+	}
+}
+
+// Name returns the command name
+func (c *HashCommand) Name() string {
+	return "hash"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *HashCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *HashCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'r', Description: "Forget every remembered executable."},
+			{Short: 'd', Description: "Forget the executables of the given names."},
+			{Short: 'p', Type: argparse.String, Value: "path", Description: "Remember path as the executable of the given names."},
+			{Short: 'l', Description: "List the table as hash commands that recreate it."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "name", Optional: true, Variadic: true},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *HashCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	names := parsed.Strings("name")
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+	if session.Hash == nil {
+		session.Hash = shell.NewCommandHash()
+		if err := c.sessionRepo.SetSession(session); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error updating session: %v\n", err)
+			return err
+		}
+	}
+	hash, path := session.Hash, shell.SearchPath(c.path)
+
+	if parsed.Bool("r") {
+		hash.Reset()
+	}
+
+	switch {
+	case parsed.Has("p"):
+		if len(names) == 0 {
+			_, err = fmt.Fprintln(errorOutputWriter, "hash: -p: missing name")
+			return err
+		}
+		for _, name := range names {
+			hash.Set(name, parsed.String("p"), path)
+		}
+		return nil
+	case parsed.Bool("d"):
+		for _, name := range names {
+			if !hash.Delete(name) {
+				if _, err := fmt.Fprintf(errorOutputWriter, "hash: %s: not found\n", name); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case len(names) > 0:
+		for _, name := range names {
+			if err := c.add(hash, name, path, errorOutputWriter); err != nil {
+				return err
+			}
+		}
+		return nil
+	case parsed.Bool("r"):
+		return nil
+	}
+
+	return c.list(hash.Entries(path), parsed.Bool("l"), outputWriter, errorOutputWriter)
+}
+
+// add remembers the executable of name. Builtins and paths such as
+// ./build.sh are not searched in PATH, so they are not remembered.
+func (c *HashCommand) add(hash *shell.CommandHash, name, path string, errorOutputWriter io.Writer) error {
+	if strings.ContainsRune(name, '/') {
+		return nil
+	}
+	if _, err := c.cmdRepo.Get(name); err == nil {
+		return nil
+	}
+
+	if err := hash.Add(name, path); err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "hash: %s: not found\n", name)
+		return err
+	}
+	return nil
+}
+
+// list prints the remembered executables with their hit counts, or as hash
+// commands with reusable
+func (c *HashCommand) list(entries []shell.HashEntry, reusable bool, outputWriter, errorOutputWriter io.Writer) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(outputWriter, "hash: hash table empty")
+		return err
+	}
+
+	var b strings.Builder
+	if !reusable {
+		b.WriteString("hits\tcommand\n")
+	}
+	for _, entry := range entries {
+		if reusable {
+			fmt.Fprintf(&b, "builtin hash -p %s %s\n", entry.Path, entry.Name)
+		} else {
+			fmt.Fprintf(&b, "%4d\t%s\n", entry.Hits, entry.Path)
+		}
+	}
+
+	if _, err := io.WriteString(outputWriter, b.String()); err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// Help returns the help text
+func (c *HashCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of hash
+func (c *HashCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "hash",
+		Summary: "Remember or show where commands are found.",
+		Description: "The shell remembers where it found each executable it ran, so PATH is not searched again the next time. Without arguments hash lists the remembered executables and how many times each was used; with names it looks them up and remembers them without running them. " +
+			"The table belongs to the session and is emptied when PATH changes. A remembered executable that is removed is searched again, but one that is added earlier in PATH is only found after hash -r.",
+		Examples: []shell.HelpExample{
+			{Command: "hash -r", Description: "Forget everything, after installing a program that should take precedence."},
+			{Command: "hash -p /opt/go/bin/go go", Description: "Run /opt/go/bin/go for go, whatever PATH says."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Success."},
+			{Code: 1, Description: "A name was not found."},
+		},
+		SeeAlso: []string{"type", "which", "command"},
+	}
+}
@@ -0,0 +1,110 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "tool")
+	err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0755)
+	assert.NoError(t, err)
+	t.Setenv("PATH", dir)
+
+	session := shell.Session{WorkingDir: dir, Hash: shell.NewCommandHash()}
+	sessionRepo := new(repository.SessionRepositoryMock)
+	sessionRepo.On("GetSession").Return(session, nil)
+
+	cmdRepo := new(repository.CommandRepositoryMock)
+	cmdRepo.On("Get", "echo").Return(&MockCommand{}, nil)
+	cmdRepo.On("Get", "tool").Return(&MockCommand{}, errors.New("not found"))
+	cmdRepo.On("Get", "nonexistent").Return(&MockCommand{}, errors.New("not found"))
+
+	// the steps share the hash table of the session
+	steps := []struct {
+		name           string
+		args           []string
+		run            int // times tool is run before the step
+		path           string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - empty table",
+			expectedOutput: "hash: hash table empty\n",
+		},
+		{
+			name: "success - remember without running",
+			args: []string{"tool", "echo", "./tool"},
+		},
+		{
+			name:           "success - list hits",
+			run:            2,
+			expectedOutput: "hits\tcommand\n   2\t" + tool + "\n",
+		},
+		{
+			name: "success - set a path",
+			args: []string{"-p", "/bin/sh", "sh"},
+		},
+		{
+			name:           "success - reusable listing",
+			args:           []string{"-l"},
+			expectedOutput: "builtin hash -p /bin/sh sh\nbuiltin hash -p " + tool + " tool\n",
+		},
+		{
+			name:          "failure - forget unknown name",
+			args:          []string{"-d", "sh", "ls"},
+			expectedError: "hash: ls: not found\n",
+		},
+		{
+			name:          "failure - not found",
+			args:          []string{"nonexistent"},
+			expectedError: "hash: nonexistent: not found\n",
+		},
+		{
+			name:           "success - forget everything",
+			args:           []string{"-r"},
+			expectedOutput: "",
+		},
+		{
+			name:           "success - emptied when PATH changes",
+			run:            1,
+			path:           dir + string(os.PathListSeparator) + "/nonexistent",
+			expectedOutput: "hash: hash table empty\n",
+		},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			for i := 0; i < step.run; i++ {
+				_, err := session.Hash.Find("tool", dir)
+				assert.NoError(t, err)
+			}
+			if step.path != "" {
+				t.Setenv("PATH", step.path)
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewHashCommand(cmdRepo, sessionRepo, dir)
+			err := cmd.Execute(ctx, step.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, step.expectedOutput, outputBuffer.String())
+			assert.Equal(t, step.expectedError, errorBuffer.String())
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// defaultHeadLines is the number of lines printed by head and tail
+const defaultHeadLines = 10
+
+// HeadCommand implements the head command
+type HeadCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewHeadCommand creates a new head command
+func NewHeadCommand(sessionRepo shell.SessionRepository) *HeadCommand {
+	return &HeadCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *HeadCommand) Name() string {
+	return "head"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *HeadCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *HeadCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'n', Long: "lines", Type: argparse.Int, Value: "count", Description: "Print the first count lines instead of 10."},
+			{Short: 'c', Long: "bytes", Type: argparse.Int, Value: "count", Description: "Print the first count bytes instead of lines."},
+			{Short: 'q', Long: "quiet", Description: "Never print headers with the file names."},
+			{Short: 'v', Long: "verbose", Description: "Always print headers with the file names."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Optional: true, Variadic: true},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *HeadCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	count, countBytes := defaultHeadLines, parsed.Has("c")
+	switch {
+	case countBytes:
+		count = parsed.Int("c")
+	case parsed.Has("n"):
+		count = parsed.Int("n")
+	}
+	if count < 0 {
+		_, err = fmt.Fprintf(errorOutputWriter, "head: invalid number: %d\n", count)
+		return err
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	operands := operandsOrInput(parsed.Operands)
+	headers := &fileHeaders{show: (len(operands) > 1 || parsed.Bool("v")) && !parsed.Bool("q")}
+
+	for _, operand := range operands {
+		err := c.headFile(ctx, session.WorkingDir, operand, count, countBytes, headers, inputReader, outputWriter)
+		if err == nil {
+			continue
+		}
+		if done, err := reportInputError(errorOutputWriter, "head", operand, err); done {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// headFile prints the first count lines or bytes of one operand, after its
+// header
+func (c *HeadCommand) headFile(ctx context.Context, workingDir, operand string, count int, countBytes bool, headers *fileHeaders, inputReader io.Reader, outputWriter io.Writer) error {
+	r, f, err := openOperand(ctx, workingDir, operand, inputReader)
+	if err != nil {
+		return err
+	}
+	if f != nil {
+		defer f.Close()
+	}
+
+	if err := headers.write(outputWriter, operand); err != nil {
+		return err
+	}
+
+	if countBytes {
+		_, err := io.CopyN(outputWriter, r, int64(count))
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	return copyLines(outputWriter, bufio.NewReader(r), count)
+}
+
+// copyLines copies the next count lines of r to w. Lines are copied in
+// pieces, so long ones use little memory.
+func copyLines(w io.Writer, r *bufio.Reader, count int) error {
+	bw := bufio.NewWriter(w)
+	for count > 0 {
+		chunk, err := r.ReadSlice('\n')
+		bw.Write(chunk)
+
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case err == io.EOF:
+			return bw.Flush()
+		case err != nil:
+			// what was read is printed before the error is reported
+			bw.Flush()
+			return err
+		}
+
+		count--
+		if err := flushIfIdle(bw, r); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Help returns the help text
+func (c *HeadCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of head
+func (c *HeadCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "head",
+		Summary:     "Print the first lines of files.",
+		Description: "Prints the first 10 lines, or count lines or bytes, of each file relative to the working directory, or of the input when there is no file or it is -. With several files each starts with a ==> file <== header.",
+		Examples: []shell.HelpExample{
+			{Command: "head -n 3 notes.txt", Description: "Print the first three lines."},
+			{Command: "head -c 512 image.png > header.bin", Description: "Save the first 512 bytes of a file."},
+		},
+		SeeAlso: []string{"tail", "cat"},
+	}
+}
@@ -0,0 +1,113 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// numberedLines returns the lines "1" to "n"
+func numberedLines(n int) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "%d\n", i)
+	}
+	return sb.String()
+}
+
+func TestHeadCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "twenty.txt"), []byte(numberedLines(20)), 0644)
+	os.WriteFile(filepath.Join(dir, "short.txt"), []byte("a\nb"), 0644)
+	longLine := strings.Repeat("x", 10000)
+	os.WriteFile(filepath.Join(dir, "long.txt"), []byte(longLine+"\nsecond\n"), 0644)
+
+	cases := []struct {
+		name           string
+		args           []string
+		input          string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - first ten lines of the input",
+			input:          numberedLines(12),
+			expectedOutput: numberedLines(10),
+		},
+		{
+			name:           "success - line count",
+			args:           []string{"-n", "3", "twenty.txt"},
+			expectedOutput: "1\n2\n3\n",
+		},
+		{
+			name:           "success - byte count",
+			args:           []string{"-c", "5", "twenty.txt"},
+			expectedOutput: "1\n2\n3",
+		},
+		{
+			name:           "success - last line without newline",
+			args:           []string{"short.txt"},
+			expectedOutput: "a\nb",
+		},
+		{
+			name:           "success - long line",
+			args:           []string{"-n", "1", "long.txt"},
+			expectedOutput: longLine + "\n",
+		},
+		{
+			name:           "success - headers with several files",
+			args:           []string{"-n", "1", "twenty.txt", "-", "short.txt"},
+			input:          "in\n",
+			expectedOutput: "==> twenty.txt <==\n1\n\n==> standard input <==\nin\n\n==> short.txt <==\na\n",
+		},
+		{
+			name:           "success - quiet",
+			args:           []string{"-q", "-n", "1", "twenty.txt", "short.txt"},
+			expectedOutput: "1\na\n",
+		},
+		{
+			name:           "failure - missing file",
+			args:           []string{"-n", "1", "missing.txt", "short.txt"},
+			expectedOutput: "==> short.txt <==\na\n",
+			expectedError:  "head: missing.txt: no such file or directory\n",
+		},
+		{
+			name:          "failure - directory",
+			args:          []string{"."},
+			expectedError: "head: .: is a directory\n",
+		},
+		{
+			name:          "failure - negative count",
+			args:          []string{"-n", "-1", "twenty.txt"},
+			expectedError: "head: invalid number: -1\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewHeadCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.input), &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+		})
+	}
+}
@@ -4,18 +4,24 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sort"
 	"text/tabwriter"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
+const helpUsage = "usage: help [command ...] | help -k keyword | help --markdown [command ...] | help --man command | help --man --dir <dir>"
+
 type HelpCommand struct {
-	cmdRepo shell.CommandRepository
+	cmdRepo     shell.CommandRepository
+	sessionRepo shell.SessionRepository
 }
 
-func NewHelpCommand(cmdRepo shell.CommandRepository) *HelpCommand {
-	return &HelpCommand{cmdRepo: cmdRepo}
+func NewHelpCommand(cmdRepo shell.CommandRepository, sessionRepo shell.SessionRepository) *HelpCommand {
+	return &HelpCommand{cmdRepo: cmdRepo, sessionRepo: sessionRepo}
 }
 
 func (h *HelpCommand) Name() string {
@@ -23,10 +29,31 @@ func (h *HelpCommand) Name() string {
 }
 
 func (h *HelpCommand) MaxArguments() int {
-	return 0
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (h *HelpCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'k', Type: argparse.String, Value: "keyword", Description: "List the builtins whose name, summary, description or options contain keyword, ignoring case."},
+			{Long: "markdown", Description: "Write the documentation of the named builtins, or of all builtins, as one Markdown document."},
+			{Long: "man", Description: "Write the man page of a builtin."},
+			{Long: "dir", Type: argparse.String, Value: "dir", Description: "With --man, write a command.1 page for every builtin into dir, creating it when needed."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "command", Optional: true, Variadic: true},
+		},
+	}
 }
 
 func (h *HelpCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, h.Name(), h.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	names := parsed.Strings("command")
+
 	commands, err := h.cmdRepo.List()
 	if err != nil {
 		_, err = fmt.Fprintf(errorOutputWriter, "error listing commands: %v\n", err)
@@ -37,18 +64,83 @@ func (h *HelpCommand) Execute(ctx context.Context, args []string, inputReader io
 		return commands[i].Name() < commands[j].Name()
 	})
 
+	// -k, --markdown and --man are exclusive, --dir goes with --man
+	modes := 0
+	for _, name := range []string{"k", "markdown", "man"} {
+		if parsed.Has(name) {
+			modes++
+		}
+	}
+
+	switch {
+	case modes > 1 || (parsed.Has("dir") && !parsed.Bool("man")):
+		_, err := fmt.Fprintln(errorOutputWriter, helpUsage)
+		return err
+
+	case parsed.Has("k"):
+		if len(names) > 0 {
+			_, err := fmt.Fprintln(errorOutputWriter, helpUsage)
+			return err
+		}
+		return h.searchCommands(commands, parsed.String("k"), outputWriter, errorOutputWriter)
+
+	case parsed.Bool("markdown"):
+		docs, ok, err := h.selectDocs(commands, names, errorOutputWriter)
+		if !ok {
+			return err
+		}
+		return h.writeOutput(shell.WriteMarkdown(outputWriter, docs), errorOutputWriter)
+
+	case parsed.Bool("man"):
+		if parsed.Has("dir") && len(names) == 0 {
+			return h.writeManPages(commands, parsed.String("dir"), errorOutputWriter)
+		}
+		if parsed.Has("dir") || len(names) != 1 {
+			_, err := fmt.Fprintln(errorOutputWriter, helpUsage)
+			return err
+		}
+		docs, ok, err := h.selectDocs(commands, names, errorOutputWriter)
+		if !ok {
+			return err
+		}
+		return h.writeOutput(shell.WriteMan(outputWriter, docs[0]), errorOutputWriter)
+
+	case len(names) == 0:
+		return h.listCommands(commands, outputWriter, errorOutputWriter)
+	}
+
+	docs, ok, err := h.selectDocs(commands, names, errorOutputWriter)
+	if !ok {
+		return err
+	}
+	for i, doc := range docs {
+		if i > 0 {
+			if _, err := fmt.Fprintln(outputWriter); err != nil {
+				return err
+			}
+		}
+		if err := shell.WriteHelp(outputWriter, doc); err != nil {
+			return h.writeOutput(err, errorOutputWriter)
+		}
+	}
+
+	return nil
+}
+
+// listCommands prints every command with its summary
+func (h *HelpCommand) listCommands(commands []shell.Command, outputWriter, errorOutputWriter io.Writer) error {
 	w := tabwriter.NewWriter(outputWriter, 0, 0, 3, ' ', 0)
 	fmt.Fprintln(w, "Command\tDescription")
 	fmt.Fprintln(w, "---\t---")
 
 	for _, cmd := range commands {
-		_, err = fmt.Fprintf(w, "%s\t%s\n", cmd.Name(), cmd.Help())
+		_, err := fmt.Fprintf(w, "%s\t%s\n", cmd.Name(), shell.CommandDoc(cmd).Summary)
 		if err != nil {
 			return err
 		}
 	}
 
-	err = w.Flush()
+	err := w.Flush()
 	if err != nil {
 		_, err := fmt.Fprintf(errorOutputWriter, "error flushing tab writer: %v\n", err)
 		return err
@@ -57,6 +149,127 @@ func (h *HelpCommand) Execute(ctx context.Context, args []string, inputReader io
 	return nil
 }
 
+// searchCommands prints the commands whose documentation mentions keyword,
+// like apropos
+func (h *HelpCommand) searchCommands(commands []shell.Command, keyword string, outputWriter, errorOutputWriter io.Writer) error {
+	found := false
+	for _, cmd := range commands {
+		doc := shell.CommandDoc(cmd)
+		if !doc.MatchesKeyword(keyword) {
+			continue
+		}
+
+		found = true
+		if _, err := fmt.Fprintf(outputWriter, "%s - %s\n", doc.Name, doc.Summary); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		_, err := fmt.Fprintf(errorOutputWriter, "help: nothing appropriate for '%s'\n", keyword)
+		return err
+	}
+
+	return nil
+}
+
+// selectDocs returns the documentation of the named commands, or of all
+// commands when no names are given. ok is false when a name is unknown; the
+// error is then reported already.
+func (h *HelpCommand) selectDocs(commands []shell.Command, names []string, errorOutputWriter io.Writer) ([]shell.HelpDoc, bool, error) {
+	if len(names) == 0 {
+		docs := make([]shell.HelpDoc, len(commands))
+		for i, cmd := range commands {
+			docs[i] = shell.CommandDoc(cmd)
+		}
+		return docs, true, nil
+	}
+
+	byName := make(map[string]shell.Command, len(commands))
+	for _, cmd := range commands {
+		byName[cmd.Name()] = cmd
+	}
+
+	docs := make([]shell.HelpDoc, 0, len(names))
+	for _, name := range names {
+		cmd, ok := byName[name]
+		if !ok {
+			_, err := fmt.Fprintf(errorOutputWriter, "help: no help topics match '%s'\n", name)
+			return nil, false, err
+		}
+		docs = append(docs, shell.CommandDoc(cmd))
+	}
+
+	return docs, true, nil
+}
+
+// writeManPages writes a <command>.1 man page for every command into dir,
+// relative to the working directory
+func (h *HelpCommand) writeManPages(commands []shell.Command, dir string, errorOutputWriter io.Writer) error {
+	session, err := h.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	dir = resolvePath(session.WorkingDir, dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "help: %v\n", err)
+		return err
+	}
+
+	for _, cmd := range commands {
+		f, err := os.Create(filepath.Join(dir, cmd.Name()+".1"))
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "help: %v\n", err)
+			return err
+		}
+
+		err = shell.WriteMan(f, shell.CommandDoc(cmd))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "help: %v\n", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeOutput reports an error writing the output
+func (h *HelpCommand) writeOutput(err error, errorOutputWriter io.Writer) error {
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+	}
+	return err
+}
+
 func (h *HelpCommand) Help() string {
-	return "help - Displays available commands and their usage in a formatted table."
+	return shell.HelpLine(h.Doc())
+}
+
+// Doc returns the documentation of help
+func (h *HelpCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "help",
+		Summary: "Displays available commands, or the documentation of builtins.",
+		Synopsis: []string{
+			"help [command ...]",
+			"help -k keyword",
+			"help --markdown [command ...]",
+			"help --man command",
+			"help --man --dir dir",
+		},
+		Description: "Without arguments help lists every builtin with a one line summary. " +
+			"With command names it shows their synopsis, options, examples and exit status, the same as command --help.\n\n" +
+			"The documentation can also be rendered to Markdown, or to man(1) pages in roff.",
+		Examples: []shell.HelpExample{
+			{Command: "help cd", Description: "Show the documentation of cd."},
+			{Command: "help -k directory", Description: "Find the builtins about directories."},
+			{Command: "help --markdown > BUILTINS.md", Description: "Render all builtin documentation to Markdown."},
+			{Command: "help --man --dir man/man1", Description: "Write a man page for every builtin."},
+		},
+	}
 }
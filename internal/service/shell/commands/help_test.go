@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
@@ -17,6 +19,7 @@ func TestHelpCommand_Execute(t *testing.T) {
 
 	cases := []struct {
 		name           string
+		args           []string
 		setupRepo      func(repo *repository.CommandRepositoryMock)
 		expectedOutput string
 		expectedError  string
@@ -59,6 +62,92 @@ func TestHelpCommand_Execute(t *testing.T) {
 			expectedOutput: "Command   Description\n---       ---\n",
 			expectedError:  "",
 		},
+		{
+			name: "success - summary from the help line",
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("List").Return([]shell.Command{
+					&MockCommand{NameVal: "cmd1", HelpVal: "cmd1 [-x] - Does things"},
+				}, nil).Once()
+			},
+			expectedOutput: "Command   Description\n---       ---\ncmd1      Does things\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - help of a command",
+			args: []string{"cmd1"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("List").Return([]shell.Command{
+					&MockCommand{NameVal: "cmd1", HelpVal: "cmd1 [-x] - Does things"},
+				}, nil).Once()
+			},
+			expectedOutput: "NAME\n    cmd1 - Does things\n\nSYNOPSIS\n    cmd1 [-x]\n\nEXIT STATUS\n    0   Success.\n    1   An error was reported.\n",
+			expectedError:  "",
+		},
+		{
+			name: "failure - unknown topic",
+			args: []string{"cmd1", "nope"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("List").Return([]shell.Command{
+					&MockCommand{NameVal: "cmd1", HelpVal: "Help for cmd1"},
+				}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "help: no help topics match 'nope'\n",
+		},
+		{
+			name: "success - keyword search",
+			args: []string{"-k", "DIRECTORY"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("List").Return([]shell.Command{
+					commands.NewPWDCommand(nil),
+					&MockCommand{NameVal: "cmd1", HelpVal: "Help for cmd1"},
+				}, nil).Once()
+			},
+			expectedOutput: "pwd - Print the working directory.\n",
+			expectedError:  "",
+		},
+		{
+			name: "failure - keyword not found",
+			args: []string{"-k", "nothing"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("List").Return([]shell.Command{
+					&MockCommand{NameVal: "cmd1", HelpVal: "Help for cmd1"},
+				}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "help: nothing appropriate for 'nothing'\n",
+		},
+		{
+			name: "success - markdown",
+			args: []string{"--markdown", "cmd1"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("List").Return([]shell.Command{
+					&MockCommand{NameVal: "cmd1", HelpVal: "cmd1 <file> - Uses *stars*"},
+				}, nil).Once()
+			},
+			expectedOutput: "# Builtin commands\n\n## cmd1\n\nUses \\*stars\\*\n\n```\ncmd1 <file>\n```\n\n### Exit status\n\n| Code | Meaning |\n| --- | --- |\n| 0 | Success. |\n| 1 | An error was reported. |\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - man page",
+			args: []string{"--man", "cmd1"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("List").Return([]shell.Command{
+					&MockCommand{NameVal: "cmd1", HelpVal: "cmd1 [-x] - Does things"},
+				}, nil).Once()
+			},
+			expectedOutput: ".TH CMD1 1 \"\" \"goshell\" \"goshell builtins\"\n.SH NAME\ncmd1 \\- Does things\n.SH SYNOPSIS\n.nf\ncmd1 [\\-x]\n.fi\n.SH EXIT STATUS\n.TP\n.B 0\nSuccess.\n.TP\n.B 1\nAn error was reported.\n",
+			expectedError:  "",
+		},
+		{
+			name: "failure - man without a command",
+			args: []string{"--man"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("List").Return([]shell.Command{}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "usage: help [command ...] | help -k keyword | help --markdown [command ...] | help --man command | help --man --dir <dir>\n",
+		},
 	}
 
 	for _, tc := range cases {
@@ -69,8 +158,8 @@ func TestHelpCommand_Execute(t *testing.T) {
 			var outputBuffer bytes.Buffer
 			var errorBuffer bytes.Buffer
 
-			cmd := commands.NewHelpCommand(mockRepo)
-			err := cmd.Execute(ctx, []string{}, nil, &outputBuffer, &errorBuffer)
+			cmd := commands.NewHelpCommand(mockRepo, new(repository.SessionRepositoryMock))
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
 
 			assert.NoError(t, err)
 
@@ -80,3 +169,33 @@ func TestHelpCommand_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestHelpCommand_ManDir(t *testing.T) {
+	dir := t.TempDir()
+
+	mockRepo := new(repository.CommandRepositoryMock)
+	mockRepo.On("List").Return([]shell.Command{
+		&MockCommand{NameVal: "cmd1", HelpVal: "cmd1 - Does things"},
+		&MockCommand{NameVal: "cmd2", HelpVal: "cmd2 - Does more things"},
+	}, nil).Once()
+	mockSessionRepo := new(repository.SessionRepositoryMock)
+	mockSessionRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+
+	var outputBuffer bytes.Buffer
+	var errorBuffer bytes.Buffer
+
+	cmd := commands.NewHelpCommand(mockRepo, mockSessionRepo)
+	err := cmd.Execute(context.Background(), []string{"--man", "--dir", "man1"}, nil, &outputBuffer, &errorBuffer)
+
+	assert.NoError(t, err)
+	assert.Empty(t, outputBuffer.String())
+	assert.Empty(t, errorBuffer.String())
+
+	for _, name := range []string{"cmd1", "cmd2"} {
+		page, err := os.ReadFile(filepath.Join(dir, "man1", name+".1"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(page), ".SH NAME\n"+name+" \\- ")
+	}
+	mockRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
@@ -4,27 +4,38 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"strconv"
+	"os"
+	"path/filepath"
 	"text/tabwriter"
+	"time"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/history"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/user"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // HistoryCommand implements the history command
 type HistoryCommand struct {
 	historySVC  *history.Service
 	sessionRepo shell.SessionRepository
+	userSVC     *user.Service
+	admins      []string
 }
 
-// New creates a new history command
+// New creates a new history command. admins lists the usernames allowed to
+// search the history of other users.
 func NewHistoryCommand(
 	historySVC *history.Service,
 	sessionRepo shell.SessionRepository,
+	userSVC *user.Service,
+	admins []string,
 ) *HistoryCommand {
 	return &HistoryCommand{
 		historySVC:  historySVC,
 		sessionRepo: sessionRepo,
+		userSVC:     userSVC,
+		admins:      admins,
 	}
 }
 
@@ -35,11 +46,58 @@ func (c *HistoryCommand) Name() string {
 
 // MaxArguments returns the maximum number of arguments allowed for the Command.
 func (c *HistoryCommand) MaxArguments() int {
-	return 2
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *HistoryCommand) Arguments() argparse.Spec {
+	limit := argparse.Flag{Short: 'n', Long: "limit", Type: argparse.Int, Value: "limit", Description: "Show at most limit entries."}
+
+	return argparse.Spec{
+		Flags: []argparse.Flag{limit},
+		Subcommands: []argparse.Subcommand{
+			{
+				Name:        "list",
+				Description: "List the numbered history, oldest first.",
+				Spec:        argparse.Spec{Flags: []argparse.Flag{limit}},
+			},
+			{
+				Name:        "search",
+				Description: "List the entries matching the regular expression pattern, newest first.",
+				Spec:        historySearchSpec,
+			},
+			{
+				Name:        "import",
+				Description: "Add the entries of a bash, zsh or fish history file, skipping duplicates.",
+				Spec: argparse.Spec{
+					Flags: []argparse.Flag{
+						{Short: 'f', Long: "format", Type: argparse.String, Value: "format", Choices: []string{history.FormatBash, history.FormatZsh, history.FormatFish}, Description: "The format of the file, bash by default."},
+					},
+					Positionals: []argparse.Positional{{Name: "file"}},
+				},
+			},
+			{
+				Name:        "export",
+				Description: "Write the whole history as JSON or as a bash or zsh history file.",
+				Spec: argparse.Spec{
+					Flags: []argparse.Flag{
+						{Short: 'f', Long: "format", Type: argparse.String, Value: "format", Choices: []string{history.FormatJSON, history.FormatBash, history.FormatZsh}, Description: "The output format, json by default."},
+					},
+				},
+			},
+			{Name: "prune", Description: "Apply the retention policy now."},
+			{Name: "clean", Description: "Delete the whole history."},
+		},
+	}
 }
 
 // Execute runs the command
 func (c *HistoryCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
 	session, err := c.sessionRepo.GetSession()
 	if err != nil {
 		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
@@ -51,36 +109,47 @@ func (c *HistoryCommand) Execute(ctx context.Context, args []string, inputReader
 		userID = &session.User.ID
 	}
 
-	// Parse flags
-	if len(args) > 0 {
-		switch args[0] {
-		case "clean":
-			err := c.historySVC.ClearCommandHistory(userID)
-			if err != nil {
-				_, err = fmt.Fprintf(errorOutputWriter, "error cleaning history: %v\n", err)
-				return err
-			}
+	switch parsed.Subcommand {
+	case "clean":
+		err := c.historySVC.ClearCommandHistory(userID)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error cleaning history: %v\n", err)
+			return err
+		}
+
+		_, err = fmt.Fprintln(outputWriter, "History cleaned.")
+		return err
 
-			_, err = fmt.Fprintln(outputWriter, "History cleaned.")
+	case "list":
+		limit := parsed.Int("limit")
+		if limit < 0 {
+			_, err = fmt.Fprintf(errorOutputWriter, "%s: invalid limit: %d\n", parsed.Command, limit)
 			return err
+		}
 
-		case "-n", "--limit":
-			if len(args) < 2 {
-				_, err = fmt.Fprintf(errorOutputWriter, "usage: history -n <limit>\n")
-				return err
-			}
+		return c.listHistory(userID, limit, outputWriter, errorOutputWriter)
 
-			limit, err := strconv.Atoi(args[1])
-			if err != nil {
-				_, err = fmt.Fprintf(errorOutputWriter, "invalid limit: %s\n", args[1])
-				return err
-			}
+	case "search":
+		return c.searchHistory(session, userID, parsed, outputWriter, errorOutputWriter)
 
-			return c.showHistory(userID, limit, outputWriter, errorOutputWriter)
+	case "import":
+		return c.importHistory(session, userID, parsed, outputWriter, errorOutputWriter)
+
+	case "export":
+		return c.exportHistory(userID, parsed, outputWriter, errorOutputWriter)
+
+	case "prune":
+		deleted, err := c.historySVC.PruneCommandHistory(userID)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error pruning history: %v\n", err)
+			return err
 		}
+
+		_, err = fmt.Fprintf(outputWriter, "Pruned %d entries.\n", deleted)
+		return err
 	}
 
-	return c.showHistory(userID, 0, outputWriter, errorOutputWriter)
+	return c.showHistory(userID, parsed.Int("limit"), outputWriter, errorOutputWriter)
 }
 
 // showHistory returns command history as a string
@@ -114,7 +183,104 @@ func (c *HistoryCommand) showHistory(userID *int64, limit int, outputWriter, err
 	return nil
 }
 
+// listHistory prints the numbered history oldest first. A positive limit shows only the newest entries.
+func (c *HistoryCommand) listHistory(userID *int64, limit int, outputWriter, errorOutputWriter io.Writer) error {
+	entries, err := c.historySVC.GetCommandHistory(userID)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error retrieving history: %v\n", err)
+		return err
+	}
+
+	first := 0
+	if limit > 0 && limit < len(entries) {
+		first = len(entries) - limit
+	}
+
+	for i := first; i < len(entries); i++ {
+		startedAt := entries[i].StartedAt
+		if startedAt.IsZero() {
+			startedAt = entries[i].CreatedAt
+		}
+
+		_, err = fmt.Fprintf(outputWriter, "%5d  %s  %s\n", i+1, startedAt.Format(time.DateTime), entries[i].Command)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importHistory imports a bash, zsh or fish history file
+func (c *HistoryCommand) importHistory(session shell.Session, userID *int64, parsed *argparse.Args, outputWriter, errorOutputWriter io.Writer) error {
+	format := history.FormatBash
+	if parsed.Has("format") {
+		format = parsed.String("format")
+	}
+
+	path := parsed.String("file")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(session.WorkingDir, path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error importing history: %v\n", err)
+		return err
+	}
+	defer file.Close()
+
+	entries, err := history.ParseHistory(file, format)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error importing history: %v\n", err)
+		return err
+	}
+
+	imported, skipped, err := c.historySVC.ImportCommandHistory(userID, entries)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error importing history: %v\n", err)
+		return err
+	}
+
+	_, err = fmt.Fprintf(outputWriter, "Imported %d commands, skipped %d.\n", imported, skipped)
+	return err
+}
+
+// exportHistory writes the history to the output as json, bash or zsh
+func (c *HistoryCommand) exportHistory(userID *int64, parsed *argparse.Args, outputWriter, errorOutputWriter io.Writer) error {
+	format := history.FormatJSON
+	if parsed.Has("format") {
+		format = parsed.String("format")
+	}
+
+	if err := c.historySVC.ExportCommandHistory(userID, outputWriter, format); err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error exporting history: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
 // Help returns the help text
 func (c *HistoryCommand) Help() string {
-	return "history [clear|-n <limit>] - Show command history, clear history, or limit results"
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of history
+func (c *HistoryCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "history",
+		Summary: "Show, search, import, export and prune the command history.",
+		Description: "Without a subcommand history shows how often each command was run. History is kept per user in the database, and in memory for the guest." +
+			"\n\n" +
+			"Previous commands can be recalled with !!, !n, !-n, !prefix, !$ (last argument), !* (all arguments) and ^old^new, which are expanded before the line runs." +
+			"\n\n" +
+			"The pattern of history search is a Go regular expression for the guest. For logged in users it is matched by PostgreSQL, whose regular expressions lack some features such as named groups; a pattern it rejects is reported as invalid.",
+		Examples: []shell.HelpExample{
+			{Command: "history search \"git push\" --status failed --since 7d", Description: "Find the pushes that failed during the last week."},
+			{Command: "history import --format zsh .zsh_history", Description: "Import the zsh history file in the current directory."},
+			{Command: "history export --format json > history.json", Description: "Save the history as JSON."},
+		},
+		SeeAlso: []string{"z"},
+	}
 }
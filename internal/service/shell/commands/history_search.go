@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/history"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// searchPageSize is the number of entries fetched from the repository at a time
+const searchPageSize = 100
+
+// historySearch holds the parsed arguments of history search
+type historySearch struct {
+	filter   history.SearchFilter
+	username string
+	format   string
+	limit    int
+}
+
+// historySearchSpec declares the arguments of history search
+var historySearchSpec = argparse.Spec{
+	Flags: []argparse.Flag{
+		{Long: "since", Type: argparse.String, Value: "time", Description: "Only entries started at or after time, which is absolute (2006-01-02, 2006-01-02 15:04:05, RFC 3339) or relative such as 90m or 7d."},
+		{Long: "until", Type: argparse.String, Value: "time", Description: "Only entries started before time."},
+		{Long: "cwd", Type: argparse.String, Value: "dir", Description: "Only entries run in dir."},
+		{Long: "status", Type: argparse.String, Value: "status", Choices: []string{history.StatusFailed, history.StatusSucceeded}, Description: "Only failed or successful entries."},
+		{Long: "user", Type: argparse.String, Value: "username", Description: "Search the history of another user; admins only."},
+		{Long: "format", Type: argparse.String, Value: "format", Choices: []string{"table", "json", "csv"}, Description: "The output format, table by default."},
+		{Long: "limit", Type: argparse.Int, Value: "limit", Description: "Show at most limit entries."},
+		{Long: "offset", Type: argparse.Int, Value: "offset", Description: "Skip the offset newest matching entries."},
+	},
+	Positionals: []argparse.Positional{{Name: "pattern"}},
+}
+
+// parseHistorySearch reads the parsed arguments of "history search".
+// Relative directories are resolved against workingDir and relative times
+// against now.
+func parseHistorySearch(parsed *argparse.Args, workingDir string, now time.Time) (historySearch, error) {
+	search := historySearch{
+		filter: history.SearchFilter{
+			Pattern: parsed.String("pattern"),
+			Status:  parsed.String("status"),
+			Offset:  parsed.Int("offset"),
+		},
+		username: parsed.String("user"),
+		format:   "table",
+		limit:    parsed.Int("limit"),
+	}
+
+	var err error
+	if parsed.Has("since") {
+		if search.filter.Since, err = parseHistoryTime(parsed.String("since"), now); err != nil {
+			return search, err
+		}
+	}
+	if parsed.Has("until") {
+		if search.filter.Until, err = parseHistoryTime(parsed.String("until"), now); err != nil {
+			return search, err
+		}
+	}
+	if parsed.Has("cwd") {
+		search.filter.WorkingDir = filepath.Clean(resolvePath(workingDir, parsed.String("cwd")))
+	}
+	if parsed.Has("format") {
+		search.format = parsed.String("format")
+	}
+	if search.limit < 0 {
+		return search, fmt.Errorf("invalid limit: %d", search.limit)
+	}
+	if search.filter.Offset < 0 {
+		return search, fmt.Errorf("invalid offset: %d", search.filter.Offset)
+	}
+
+	return search, nil
+}
+
+// parseHistoryTime parses an absolute time (RFC 3339, "2006-01-02 15:04:05"
+// or "2006-01-02" in local time) or a duration before now such as "90m", "24h"
+// or "7d".
+func parseHistoryTime(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{time.DateTime, time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time: %s", value)
+}
+
+// searchHistory runs history search for the session user, or for another
+// user when an admin passes --user
+func (c *HistoryCommand) searchHistory(session shell.Session, userID *int64, parsed *argparse.Args, outputWriter, errorOutputWriter io.Writer) error {
+	search, err := parseHistorySearch(parsed, session.WorkingDir, time.Now())
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "%s: %v\n", parsed.Command, err)
+		return err
+	}
+
+	if search.username != "" {
+		if session.User == nil || !slices.Contains(c.admins, session.User.Username) {
+			_, err = fmt.Fprintln(errorOutputWriter, "history search: --user requires admin privileges")
+			return err
+		}
+
+		u, err := c.userSVC.FindUser(search.username)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error finding user: %v\n", err)
+			return err
+		}
+		userID = &u.ID
+	}
+
+	out := newSearchOutput(search.format, outputWriter)
+	if err := out.begin(); err != nil {
+		return err
+	}
+
+	// fetch one page at a time so large histories are never loaded at once
+	written := 0
+	for {
+		search.filter.Limit = searchPageSize
+		if search.limit > 0 {
+			search.filter.Limit = min(searchPageSize, search.limit-written)
+		}
+
+		entries, err := c.historySVC.SearchCommandHistory(userID, search.filter)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error searching history: %v\n", err)
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := out.write(entry); err != nil {
+				return err
+			}
+		}
+
+		written += len(entries)
+		search.filter.Offset += len(entries)
+		if len(entries) < search.filter.Limit || (search.limit > 0 && written >= search.limit) {
+			break
+		}
+	}
+
+	return out.end()
+}
+
+// searchOutput writes history search results in one of the output formats
+type searchOutput struct {
+	format string
+	w      io.Writer
+	table  *tabwriter.Writer
+	csv    *csv.Writer
+	count  int
+}
+
+func newSearchOutput(format string, w io.Writer) *searchOutput {
+	return &searchOutput{format: format, w: w}
+}
+
+// csvHeader lists the columns of the csv format
+var csvHeader = []string{"started_at", "finished_at", "duration_ms", "exit_code", "working_dir", "hostname", "session_id", "command"}
+
+func (o *searchOutput) begin() error {
+	switch o.format {
+	case "json":
+		_, err := fmt.Fprint(o.w, "[")
+		return err
+	case "csv":
+		o.csv = csv.NewWriter(o.w)
+		return o.csv.Write(csvHeader)
+	default:
+		o.table = tabwriter.NewWriter(o.w, 0, 0, 3, ' ', 0)
+		_, err := fmt.Fprintln(o.table, "Started\tStatus\tDuration\tDirectory\tCommand")
+		return err
+	}
+}
+
+func (o *searchOutput) write(entry history.CommandHistory) error {
+	startedAt := entry.StartedAt
+	if startedAt.IsZero() {
+		startedAt = entry.CreatedAt
+	}
+
+	switch o.format {
+	case "json":
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		sep := ","
+		if o.count == 0 {
+			sep = ""
+		}
+		o.count++
+		_, err = fmt.Fprintf(o.w, "%s\n  %s", sep, data)
+		return err
+	case "csv":
+		return o.csv.Write([]string{
+			startedAt.Format(time.RFC3339),
+			entry.FinishedAt.Format(time.RFC3339),
+			strconv.FormatInt(entry.Duration.Milliseconds(), 10),
+			strconv.Itoa(entry.ExitCode),
+			entry.WorkingDir,
+			entry.Hostname,
+			entry.SessionID,
+			entry.Command,
+		})
+	default:
+		_, err := fmt.Fprintf(o.table, "%s\t%d\t%s\t%s\t%s\n",
+			startedAt.Format(time.DateTime), entry.ExitCode, entry.Duration.Round(time.Millisecond), entry.WorkingDir, entry.Command)
+		return err
+	}
+}
+
+func (o *searchOutput) end() error {
+	switch o.format {
+	case "json":
+		end := "]\n"
+		if o.count > 0 {
+			end = "\n]\n"
+		}
+		_, err := fmt.Fprint(o.w, end)
+		return err
+	case "csv":
+		o.csv.Flush()
+		return o.csv.Error()
+	default:
+		return o.table.Flush()
+	}
+}
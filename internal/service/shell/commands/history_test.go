@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"text/tabwriter"
+	"time"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/history"
 	historyRepository "github.com/Ali-Farhadnia/goshell/internal/service/history/repository"
@@ -13,7 +17,9 @@ import (
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
 	shellRepository "github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
 	"github.com/Ali-Farhadnia/goshell/internal/service/user"
+	userRepository "github.com/Ali-Farhadnia/goshell/internal/service/user/repository"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHistoryCommand_Execute(t *testing.T) {
@@ -69,24 +75,20 @@ func TestHistoryCommand_Execute(t *testing.T) {
 			expectedError:  "",
 		},
 		{
-			name: "failure - invalid limit",
-			args: []string{"-n", "abc"},
-			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
-				repo.On("GetSession").Return(shell.Session{User: &user.User{ID: 123}}, nil).Once()
-			},
+			name:           "failure - invalid limit",
+			args:           []string{"-n", "abc"},
+			setupSession:   func(repo *shellRepository.SessionRepositoryMock) {},
 			setupHistory:   func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {},
 			expectedOutput: "",
-			expectedError:  "invalid limit: abc\n",
+			expectedError:  "history: invalid limit: abc\nusage: history [-n <limit>]\n       history list [-n <limit>]\n       history search [--since <time>] [--until <time>] [--cwd <dir>] [--status failed|success] [--user <username>] [--format table|json|csv] [--limit <limit>] [--offset <offset>] <pattern>\n       history import [-f bash|zsh|fish] <file>\n       history export [-f json|bash|zsh]\n       history prune\n       history clean\n",
 		},
 		{
-			name: "failure - missing limit",
-			args: []string{"-n"},
-			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
-				repo.On("GetSession").Return(shell.Session{User: &user.User{ID: 123}}, nil).Once()
-			},
+			name:           "failure - missing limit",
+			args:           []string{"-n"},
+			setupSession:   func(repo *shellRepository.SessionRepositoryMock) {},
 			setupHistory:   func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {},
 			expectedOutput: "",
-			expectedError:  "usage: history -n <limit>\n",
+			expectedError:  "history: option requires an argument -- 'n'\nusage: history [-n <limit>]\n       history list [-n <limit>]\n       history search [--since <time>] [--until <time>] [--cwd <dir>] [--status failed|success] [--user <username>] [--format table|json|csv] [--limit <limit>] [--offset <offset>] <pattern>\n       history import [-f bash|zsh|fish] <file>\n       history export [-f json|bash|zsh]\n       history prune\n       history clean\n",
 		},
 		{
 			name: "failure - session error",
@@ -134,6 +136,77 @@ func TestHistoryCommand_Execute(t *testing.T) {
 			expectedOutput: "Command   Count\n---       ---\nls        5\ncd        3\n",
 			expectedError:  "",
 		},
+		{
+			name: "success - numbered listing oldest first",
+			args: []string{"list"},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{User: &user.User{ID: 123}}, nil).Once()
+			},
+			setupHistory: func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {
+				repo.On("GetUserHistory", int64(123), 0).Return([]history.CommandHistory{
+					{Command: "git status", StartedAt: time.Date(2024, 5, 1, 10, 2, 0, 0, time.UTC)},
+					{Command: "ls -la", StartedAt: time.Date(2024, 5, 1, 10, 1, 0, 0, time.UTC)},
+					{Command: "cd /tmp", CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+				}, nil).Once()
+			},
+			expectedOutput: "    1  2024-05-01 10:00:00  cd /tmp\n    2  2024-05-01 10:01:00  ls -la\n    3  2024-05-01 10:02:00  git status\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - numbered listing with limit keeps numbers",
+			args: []string{"list", "-n", "1"},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+			},
+			setupHistory: func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {
+				guestRepo.On("GetUserHistory", guestID, 0).Return([]history.CommandHistory{
+					{Command: "ls -la", StartedAt: time.Date(2024, 5, 1, 10, 1, 0, 0, time.UTC)},
+					{Command: "cd /tmp", StartedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+				}, nil).Once()
+			},
+			expectedOutput: "    2  2024-05-01 10:01:00  ls -la\n",
+			expectedError:  "",
+		},
+		{
+			name:           "failure - invalid list arguments",
+			args:           []string{"list", "-x"},
+			setupSession:   func(repo *shellRepository.SessionRepositoryMock) {},
+			setupHistory:   func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {},
+			expectedOutput: "",
+			expectedError:  "history list: invalid option -- 'x'\nusage: history list [-n <limit>]\n",
+		},
+		{
+			name:           "failure - unknown subcommand",
+			args:           []string{"lsit"},
+			setupSession:   func(repo *shellRepository.SessionRepositoryMock) {},
+			setupHistory:   func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {},
+			expectedOutput: "",
+			expectedError:  "history: invalid command 'lsit'\nusage: history [-n <limit>]\n       history list [-n <limit>]\n       history search [--since <time>] [--until <time>] [--cwd <dir>] [--status failed|success] [--user <username>] [--format table|json|csv] [--limit <limit>] [--offset <offset>] <pattern>\n       history import [-f bash|zsh|fish] <file>\n       history export [-f json|bash|zsh]\n       history prune\n       history clean\n",
+		},
+		{
+			name: "success - prune history",
+			args: []string{"prune"},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{User: &user.User{ID: 123}}, nil).Once()
+			},
+			setupHistory: func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {
+				repo.On("Prune", int64(123), history.RetentionPolicy{}).Return(int64(4), nil).Once()
+			},
+			expectedOutput: "Pruned 4 entries.\n",
+			expectedError:  "",
+		},
+		{
+			name: "failure - prune history error",
+			args: []string{"prune"},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+			},
+			setupHistory: func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {
+				guestRepo.On("Prune", guestID, history.RetentionPolicy{}).Return(int64(0), errors.New("prune error")).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "error pruning history: prune error\n",
+		},
 		{
 			name: "success - clean guest history",
 			args: []string{"clean"},
@@ -161,7 +234,7 @@ func TestHistoryCommand_Execute(t *testing.T) {
 			var errorBuffer bytes.Buffer
 
 			historySvc := history.New(mockHistoryRepo, mockGuestHistoryRepo, guestID)
-			cmd := commands.NewHistoryCommand(historySvc, mockSessionRepo)
+			cmd := commands.NewHistoryCommand(historySvc, mockSessionRepo, nil, nil)
 			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
 
 			assert.NoError(t, err)
@@ -180,3 +253,241 @@ func TestHistoryCommand_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestHistoryCommand_Search(t *testing.T) {
+	ctx := context.Background()
+	guestID := int64(999)
+	startedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	entries := []history.CommandHistory{
+		{Command: "git push", WorkingDir: "/src", ExitCode: 1, StartedAt: startedAt, FinishedAt: startedAt.Add(1500 * time.Millisecond), Duration: 1500 * time.Millisecond},
+	}
+	admin := shell.Session{User: &user.User{ID: 1, Username: "root"}, WorkingDir: "/src"}
+	regular := shell.Session{User: &user.User{ID: 123, Username: "alice"}, WorkingDir: "/src"}
+
+	cases := []struct {
+		name           string
+		args           []string
+		session        shell.Session
+		setupHistory   func(repo, guestRepo *historyRepository.HistoryRepositoryMock)
+		setupUser      func(repo *userRepository.UserRepositoryMock)
+		invalidArgs    bool // rejected before the session is read
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:    "table with filters",
+			args:    []string{"search", "^git", "--cwd", ".", "--status=failed", "--since", "2024-05-01"},
+			session: regular,
+			setupHistory: func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {
+				repo.On("Search", history.SearchFilter{
+					UserID:     123,
+					Pattern:    "^git",
+					Since:      time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local),
+					WorkingDir: "/src",
+					Status:     history.StatusFailed,
+					Limit:      100,
+				}).Return(entries, nil).Once()
+			},
+			expectedOutput: "Started               Status   Duration   Directory   Command\n" +
+				"2024-05-01 10:00:00   1        1.5s       /src        git push\n",
+		},
+		{
+			name:    "json output",
+			args:    []string{"search", "push", "--format", "json"},
+			session: shell.Session{},
+			setupHistory: func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {
+				guestRepo.On("Search", history.SearchFilter{UserID: guestID, Pattern: "push", Limit: 100}).Return(entries, nil).Once()
+			},
+			expectedOutput: "[\n  " + `{"id":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","command":"git push","user_id":0,"working_dir":"/src","exit_code":1,"started_at":"2024-05-01T10:00:00Z","finished_at":"2024-05-01T10:00:01.5Z","duration":1500000000,"hostname":"","session_id":"","pipeline":null}` + "\n]\n",
+		},
+		{
+			name:    "csv output",
+			args:    []string{"search", "push", "--format", "csv"},
+			session: regular,
+			setupHistory: func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {
+				repo.On("Search", history.SearchFilter{UserID: 123, Pattern: "push", Limit: 100}).Return(entries, nil).Once()
+			},
+			expectedOutput: "started_at,finished_at,duration_ms,exit_code,working_dir,hostname,session_id,command\n" +
+				"2024-05-01T10:00:00Z,2024-05-01T10:00:01Z,1500,1,/src,,,git push\n",
+		},
+		{
+			name:    "pages until the limit",
+			args:    []string{"search", ".", "--limit", "150", "--format", "json"},
+			session: regular,
+			setupHistory: func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {
+				repo.On("Search", history.SearchFilter{UserID: 123, Pattern: ".", Limit: 100}).Return(make([]history.CommandHistory, 100), nil).Once()
+				repo.On("Search", history.SearchFilter{UserID: 123, Pattern: ".", Offset: 100, Limit: 50}).Return([]history.CommandHistory{}, nil).Once()
+			},
+			expectedOutput: "[" + strings.Repeat(",\n  "+`{"id":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","command":"","user_id":0,"working_dir":"","exit_code":0,"started_at":"0001-01-01T00:00:00Z","finished_at":"0001-01-01T00:00:00Z","duration":0,"hostname":"","session_id":"","pipeline":null}`, 100)[1:] + "\n]\n",
+		},
+		{
+			name:    "admin searches another user",
+			args:    []string{"search", "ls", "--user", "alice", "--format", "csv"},
+			session: admin,
+			setupUser: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("FindUserByUsername", "alice").Return(user.User{ID: 123, Username: "alice"}, nil).Once()
+			},
+			setupHistory: func(repo, guestRepo *historyRepository.HistoryRepositoryMock) {
+				repo.On("Search", history.SearchFilter{UserID: 123, Pattern: "ls", Limit: 100}).Return([]history.CommandHistory{}, nil).Once()
+			},
+			expectedOutput: "started_at,finished_at,duration_ms,exit_code,working_dir,hostname,session_id,command\n",
+		},
+		{
+			name:          "non admin cannot search another user",
+			args:          []string{"search", "ls", "--user", "root"},
+			session:       regular,
+			expectedError: "history search: --user requires admin privileges\n",
+		},
+		{
+			name:          "missing pattern",
+			args:          []string{"search", "--status", "failed"},
+			session:       regular,
+			invalidArgs:   true,
+			expectedError: "history search: missing pattern\nusage: history search [--since <time>] [--until <time>] [--cwd <dir>] [--status failed|success] [--user <username>] [--format table|json|csv] [--limit <limit>] [--offset <offset>] <pattern>\n",
+		},
+		{
+			name:          "invalid status",
+			args:          []string{"search", "git", "--status", "broken"},
+			session:       regular,
+			invalidArgs:   true,
+			expectedError: "history search: invalid status: broken\nusage: history search [--since <time>] [--until <time>] [--cwd <dir>] [--status failed|success] [--user <username>] [--format table|json|csv] [--limit <limit>] [--offset <offset>] <pattern>\n",
+		},
+		{
+			name:          "invalid time",
+			args:          []string{"search", "git", "--until", "yesterday"},
+			session:       regular,
+			expectedError: "history search: invalid time: yesterday\n",
+		},
+		{
+			name:          "invalid pattern",
+			args:          []string{"search", "git("},
+			session:       regular,
+			expectedError: "error searching history: invalid pattern: error parsing regexp: missing closing ): `git(`\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSessionRepo := new(shellRepository.SessionRepositoryMock)
+			mockHistoryRepo := new(historyRepository.HistoryRepositoryMock)
+			mockGuestHistoryRepo := new(historyRepository.HistoryRepositoryMock)
+			mockUserRepo := new(userRepository.UserRepositoryMock)
+
+			if !tc.invalidArgs {
+				mockSessionRepo.On("GetSession").Return(tc.session, nil).Once()
+			}
+			if tc.setupHistory != nil {
+				tc.setupHistory(mockHistoryRepo, mockGuestHistoryRepo)
+			}
+			if tc.setupUser != nil {
+				tc.setupUser(mockUserRepo)
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			historySvc := history.New(mockHistoryRepo, mockGuestHistoryRepo, guestID)
+			cmd := commands.NewHistoryCommand(historySvc, mockSessionRepo, user.New(mockUserRepo), []string{"root"})
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockSessionRepo.AssertExpectations(t)
+			mockHistoryRepo.AssertExpectations(t)
+			mockGuestHistoryRepo.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHistoryCommand_ImportExport(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".zsh_history"), []byte(": 1700000000:2;make test\n"), 0o644))
+
+	startedAt := time.Unix(1700000000, 0)
+	session := shell.Session{User: &user.User{ID: 123}, WorkingDir: dir}
+
+	cases := []struct {
+		name           string
+		args           []string
+		setupHistory   func(repo *historyRepository.HistoryRepositoryMock)
+		invalidArgs    bool // rejected before the session is read
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "import zsh history relative to the working directory",
+			args: []string{"import", "--format", "zsh", ".zsh_history"},
+			setupHistory: func(repo *historyRepository.HistoryRepositoryMock) {
+				repo.On("GetUserHistory", int64(123), 0).Return([]history.CommandHistory{}, nil).Once()
+				repo.On("SaveCommands", mock.MatchedBy(func(entries []history.CommandHistory) bool {
+					return len(entries) == 1 && entries[0].Command == "make test" && entries[0].StartedAt.Equal(startedAt)
+				})).Return(nil).Once()
+			},
+			expectedOutput: "Imported 1 commands, skipped 0.\n",
+		},
+		{
+			name:          "import missing file",
+			args:          []string{"import", "missing"},
+			expectedError: "error importing history: open " + filepath.Join(dir, "missing") + ": no such file or directory\n",
+		},
+		{
+			name:          "import unsupported format",
+			args:          []string{"import", "--format=csh", ".zsh_history"},
+			invalidArgs:   true,
+			expectedError: "history import: invalid format: csh\nusage: history import [-f bash|zsh|fish] <file>\n",
+		},
+		{
+			name:          "import without file",
+			args:          []string{"import", "--format", "zsh"},
+			invalidArgs:   true,
+			expectedError: "history import: missing file\nusage: history import [-f bash|zsh|fish] <file>\n",
+		},
+		{
+			name: "export bash",
+			args: []string{"export", "--format", "bash"},
+			setupHistory: func(repo *historyRepository.HistoryRepositoryMock) {
+				repo.On("GetUserHistory", int64(123), 0).Return([]history.CommandHistory{
+					{Command: "ls", StartedAt: startedAt.Add(time.Minute)},
+					{Command: "make test", StartedAt: startedAt},
+				}, nil).Once()
+			},
+			expectedOutput: "#1700000000\nmake test\n#1700000060\nls\n",
+		},
+		{
+			name:          "export unsupported format",
+			args:          []string{"export", "--format", "csv"},
+			invalidArgs:   true,
+			expectedError: "history export: invalid format: csv\nusage: history export [-f json|bash|zsh]\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSessionRepo := new(shellRepository.SessionRepositoryMock)
+			mockHistoryRepo := new(historyRepository.HistoryRepositoryMock)
+
+			if !tc.invalidArgs {
+				mockSessionRepo.On("GetSession").Return(session, nil).Once()
+			}
+			if tc.setupHistory != nil {
+				tc.setupHistory(mockHistoryRepo)
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			historySvc := history.New(mockHistoryRepo, new(historyRepository.HistoryRepositoryMock), -1)
+			cmd := commands.NewHistoryCommand(historySvc, mockSessionRepo, nil, nil)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockSessionRepo.AssertExpectations(t)
+			mockHistoryRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// LnCommand implements the ln command
+type LnCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewLnCommand creates a new ln command
+func NewLnCommand(sessionRepo shell.SessionRepository) *LnCommand {
+	return &LnCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *LnCommand) Name() string {
+	return "ln"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *LnCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *LnCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 's', Description: "Make symbolic links instead of hard links."},
+			{Short: 'f', Description: "Replace existing files."},
+			{Short: 'n', Description: "Replace a link name that is a symlink to a directory instead of making the link in the directory; use with -f."},
+			{Short: 'v', Description: "Print each link made."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "target", Variadic: true, Description: "What the link points to, followed by the link name or the directory to make the links in. Without one the link is made in the working directory."},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *LnCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	symbolic := parsed.Bool("s")
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	targets := parsed.Strings("target")
+	var links []string
+	switch {
+	case len(targets) == 1:
+		links = []string{filepath.Join(session.WorkingDir, filepath.Base(targets[0]))}
+	case len(targets) == 2 && parsed.Bool("n") && isSymlink(resolvePath(session.WorkingDir, targets[1])):
+		links = []string{resolvePath(session.WorkingDir, targets[1])}
+		targets = targets[:1]
+	default:
+		dest := targets[len(targets)-1]
+		targets = targets[:len(targets)-1]
+		links, err = destinationPaths(session.WorkingDir, targets, dest)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "ln: %v\n", err)
+			return err
+		}
+	}
+
+	kind := "hard link"
+	if symbolic {
+		kind = "symbolic link"
+	}
+
+	for i, target := range targets {
+		if ctx.Err() != nil {
+			return &shell.ExitStatus{Code: interruptedStatus}
+		}
+
+		if err := makeLink(session.WorkingDir, target, links[i], symbolic, parsed.Bool("f")); err != nil {
+			if err := writeFileError(errorOutputWriter, "ln", "failed to create "+kind, session.WorkingDir, links[i], err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if parsed.Bool("v") {
+			if _, err := fmt.Fprintf(outputWriter, "'%s' -> '%s'\n", displayPath(session.WorkingDir, links[i]), target); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isSymlink reports whether path is a symlink
+func isSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&fs.ModeSymlink != 0
+}
+
+// makeLink links link to target. A symlink holds target as given, so a
+// relative one is relative to the directory of the link; a hard link is
+// made to target relative to the working directory. With force an existing
+// file at link, but not a directory, is replaced.
+func makeLink(workingDir, target, link string, symbolic, force bool) error {
+	if force {
+		if info, err := os.Lstat(link); err == nil && !info.IsDir() {
+			if err := os.Remove(link); err != nil {
+				return err
+			}
+		}
+	}
+
+	if symbolic {
+		return os.Symlink(target, link)
+	}
+
+	err := os.Link(resolvePath(workingDir, target), link)
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) && errors.Is(linkErr.Err, fs.ErrNotExist) {
+		// tell a missing target from a missing link directory
+		if _, statErr := os.Lstat(resolvePath(workingDir, target)); statErr != nil {
+			return statErr
+		}
+	}
+	return err
+}
+
+// Help returns the help text
+func (c *LnCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of ln
+func (c *LnCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "ln",
+		Summary: "Make links to files.",
+		Description: "Makes a link to target called link, or a link to each target in a directory, relative to the working directory. Without a link name the link is made in the working directory with the name of the target." +
+			"\n\n" +
+			"Hard links are made unless -s is given. A symbolic link stores the target as typed, so a relative target is relative to the directory of the link, not the working directory.",
+		Examples: []shell.HelpExample{
+			{Command: "ln -s ../shared/config.yaml config.yaml", Description: "Make a symlink to a file one directory up."},
+			{Command: "ln -sfn v2 current", Description: "Point the current symlink at the directory v2 instead of v1."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Every link was made."},
+			{Code: 1, Description: "A link could not be made."},
+			{Code: 130, Description: "Ctrl-C stopped ln before every link was made."},
+		},
+		SeeAlso: []string{"cp", "ls"},
+	}
+}
@@ -0,0 +1,128 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLnCommand_Execute(t *testing.T) {
+
+	readlink := func(t *testing.T, path string) string {
+		target, err := os.Readlink(path)
+		assert.NoError(t, err)
+		return target
+	}
+
+	cases := []struct {
+		name           string
+		args           []string
+		cancelled      bool
+		check          func(t *testing.T, dir string)
+		expectedOutput string
+		expectedError  string
+		expectedStatus int
+	}{
+		{
+			name:           "success - symlink verbosely",
+			args:           []string{"-sv", "v1", "latest"},
+			expectedOutput: "'latest' -> 'v1'\n",
+			check: func(t *testing.T, dir string) {
+				assert.Equal(t, "v1", readlink(t, filepath.Join(dir, "latest")))
+			},
+		},
+		{
+			name: "success - hard link",
+			args: []string{"v1/a.txt", "b.txt"},
+			check: func(t *testing.T, dir string) {
+				a, _ := os.Stat(filepath.Join(dir, "v1/a.txt"))
+				b, err := os.Stat(filepath.Join(dir, "b.txt"))
+				assert.NoError(t, err)
+				assert.True(t, os.SameFile(a, b))
+			},
+		},
+		{
+			name: "success - link in the working directory",
+			args: []string{"-s", "v1/a.txt"},
+			check: func(t *testing.T, dir string) {
+				assert.Equal(t, "v1/a.txt", readlink(t, filepath.Join(dir, "a.txt")))
+			},
+		},
+		{
+			name: "success - replace a symlink to a directory",
+			args: []string{"-sfn", "v2", "current"},
+			check: func(t *testing.T, dir string) {
+				assert.Equal(t, "v2", readlink(t, filepath.Join(dir, "current")))
+			},
+		},
+		{
+			name: "success - link into the directory a symlink points to",
+			args: []string{"-s", "../v2", "current"},
+			check: func(t *testing.T, dir string) {
+				assert.Equal(t, "../v2", readlink(t, filepath.Join(dir, "v1/v2")))
+			},
+		},
+		{
+			name:          "failure - existing file",
+			args:          []string{"-s", "v2", "v1/a.txt"},
+			expectedError: "ln: failed to create symbolic link 'v1/a.txt': file exists\n",
+		},
+		{
+			name:          "failure - missing target of a hard link",
+			args:          []string{"missing", "b.txt"},
+			expectedError: "ln: failed to create hard link 'missing': no such file or directory\n",
+		},
+		{
+			name:           "failure - interrupted",
+			args:           []string{"-s", "v1", "latest"},
+			cancelled:      true,
+			expectedStatus: 130,
+			check: func(t *testing.T, dir string) {
+				assert.NoFileExists(t, filepath.Join(dir, "latest"))
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.Mkdir(filepath.Join(dir, "v1"), 0755)
+			os.Mkdir(filepath.Join(dir, "v2"), 0755)
+			os.WriteFile(filepath.Join(dir, "v1/a.txt"), []byte("a"), 0644)
+			os.Symlink("v1", filepath.Join(dir, "current"))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelled {
+				cancel()
+			}
+
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewLnCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			if tc.expectedStatus == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, &shell.ExitStatus{Code: tc.expectedStatus}, err)
+			}
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			if tc.check != nil {
+				tc.check(t, dir)
+			}
+		})
+	}
+}
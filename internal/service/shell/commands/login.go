@@ -2,24 +2,42 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/Ali-Farhadnia/goshell/internal/service/history"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
 	"github.com/Ali-Farhadnia/goshell/internal/service/user"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // LoginCommand implements the login command
 type LoginCommand struct {
-	userSVC     *user.Service
-	sessionRepo shell.SessionRepository
+	userSVC        *user.Service
+	historySVC     *history.Service
+	sessionRepo    shell.SessionRepository
+	passwordReader shell.PasswordReader
+	mergeGuest     string
 }
 
-// NewLoginCommand creates a new login command
-func NewLoginCommand(userSVC *user.Service, sessionRepo shell.SessionRepository) *LoginCommand {
+// NewLoginCommand creates a new login command. mergeGuest is one of the
+// history.MergeGuest modes; asking needs a passwordReader that is also a
+// shell.LineReader.
+func NewLoginCommand(
+	userSVC *user.Service,
+	historySVC *history.Service,
+	sessionRepo shell.SessionRepository,
+	passwordReader shell.PasswordReader,
+	mergeGuest string,
+) *LoginCommand {
 	return &LoginCommand{
-		userSVC:     userSVC,
-		sessionRepo: sessionRepo,
+		userSVC:        userSVC,
+		historySVC:     historySVC,
+		sessionRepo:    sessionRepo,
+		passwordReader: passwordReader,
+		mergeGuest:     mergeGuest,
 	}
 }
 
@@ -30,23 +48,42 @@ func (c *LoginCommand) Name() string {
 
 // MaxArguments returns the maximum number of arguments allowed for the Command.
 func (c *LoginCommand) MaxArguments() int {
-	return 2
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *LoginCommand) Arguments() argparse.Spec {
+	return passwordArguments()
+}
+
+// SecretArguments returns the indices of a password given as an argument
+func (c *LoginCommand) SecretArguments(args []string) []int {
+	return passwordArgumentIndices(args)
 }
 
 // Execute runs the command
 func (c *LoginCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
-	if len(args) < 1 {
-		_, err := fmt.Fprintf(errorOutputWriter, "usage: login <username> [password]\n")
-		return err
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
 	}
 
-	username := args[0]
-	password := ""
-	if len(args) > 1 {
-		password = args[1]
+	credentials, err := readPasswordArgs(parsed, inputReader, c.passwordReader)
+	if errors.Is(err, errUsage) {
+		_, err = fmt.Fprintf(errorOutputWriter, "usage: login <username> [--password-stdin | --password-fd <fd>]\n")
+		return err
+	}
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "login failed: %v\n", err)
+		return err
 	}
 
-	user, err := c.userSVC.LoginUser(username, password)
+	user, err := c.userSVC.LoginUserFunc(credentials.username, func() (string, error) {
+		if credentials.hasPassword {
+			return credentials.password, nil
+		}
+		return promptPassword(c.passwordReader, "Password: ")
+	})
 	if err != nil {
 		_, err = fmt.Fprintf(errorOutputWriter, "login failed: %v\n", err)
 		return err
@@ -60,6 +97,13 @@ func (c *LoginCommand) Execute(ctx context.Context, args []string, inputReader i
 
 	session.User = &user
 
+	// saved key bindings are optional, a failure to load them should not block the login
+	keyBindings, err := c.userSVC.KeyBindings(user.ID)
+	if err != nil {
+		fmt.Fprintf(errorOutputWriter, "key bindings error: %v\n", err)
+	}
+	session.KeyBindings = keyBindings
+
 	err = c.sessionRepo.SetSession(session)
 	if err != nil {
 		_, err = fmt.Fprintf(errorOutputWriter, "session save error: %v\n", err)
@@ -72,10 +116,81 @@ func (c *LoginCommand) Execute(ctx context.Context, args []string, inputReader i
 		return err
 	}
 
-	return nil
+	// the guest history is optional as well, the user stays logged in
+	return c.mergeGuestHistory(user, outputWriter, errorOutputWriter)
+}
+
+// mergeGuestHistory moves the commands typed as guest into the history of the
+// user who logged in, asking first when configured to. The guest history is
+// cleared either way so the next guest does not see them.
+func (c *LoginCommand) mergeGuestHistory(u user.User, outputWriter, errorOutputWriter io.Writer) error {
+	count, err := c.historySVC.GuestHistoryCount()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "guest history error: %v\n", err)
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	merge := c.mergeGuest == history.MergeGuestAlways
+	if c.mergeGuest == history.MergeGuestAsk {
+		merge = c.confirm(fmt.Sprintf("Add %d commands typed as guest to the history of %s? [y/N] ", count, u.Username))
+	}
+
+	if !merge {
+		if err := c.historySVC.ClearCommandHistory(nil); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "guest history error: %v\n", err)
+			return err
+		}
+		return nil
+	}
+
+	merged, err := c.historySVC.MergeGuestHistory(u.ID)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "guest history error: %v\n", err)
+		return err
+	}
+
+	_, err = fmt.Fprintf(outputWriter, "Added %d commands from the guest session to your history.\n", merged)
+	return err
+}
+
+// confirm asks a yes/no question. Without a way to ask the answer is no.
+func (c *LoginCommand) confirm(prompt string) bool {
+	reader, ok := c.passwordReader.(shell.LineReader)
+	if !ok {
+		return false
+	}
+
+	answer, err := reader.ReadLine(prompt)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
 }
 
 // Help returns the help text
 func (c *LoginCommand) Help() string {
-	return "login <username> [password] - Login as specified user. Password is optional."
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of login
+func (c *LoginCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "login",
+		Summary:     "Log in as a user.",
+		Synopsis:    []string{"login <username> [--password-stdin | --password-fd <fd>]"},
+		Description: "Switches the session to username. The password is prompted for without echo when the account has one. Key bindings saved for the user are loaded, and commands typed as guest can be added to the user's history, depending on history.mergeGuest.",
+		Examples: []shell.HelpExample{
+			{Command: "login alice", Description: "Log in as alice."},
+		},
+		SeeAlso: []string{"logout", "adduser", "users"},
+	}
 }
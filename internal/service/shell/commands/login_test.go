@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Ali-Farhadnia/goshell/internal/service/history"
+	historyRepository "github.com/Ali-Farhadnia/goshell/internal/service/history/repository"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
 	shellRepository "github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
@@ -25,6 +29,8 @@ func TestLoginCommand_Execute(t *testing.T) {
 	cases := []struct {
 		name           string
 		args           []string
+		stdin          string
+		passwords      []string
 		setupUserRepo  func(repo *userRepository.UserRepositoryMock)
 		setupSession   func(repo *shellRepository.SessionRepositoryMock)
 		expectedOutput string
@@ -36,6 +42,7 @@ func TestLoginCommand_Execute(t *testing.T) {
 			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
 				repo.On("FindUserByUsername", "testuser").Return(user.User{Username: "testuser", ID: 1}, nil).Once()
 				repo.On("UpdateLastLogin", int64(1)).Return(nil).Once()
+				repo.On("ListKeyBindings", int64(1)).Return([]user.KeyBinding{}, nil).Once()
 			},
 			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
 				repo.On("GetSession").Return(shell.Session{}, nil).Once()
@@ -56,6 +63,7 @@ func TestLoginCommand_Execute(t *testing.T) {
 					PasswordHash: &hashedPasswordStr,
 				}, nil).Once()
 				repo.On("UpdateLastLogin", int64(1)).Return(nil).Once()
+				repo.On("ListKeyBindings", int64(1)).Return([]user.KeyBinding{}, nil).Once()
 			},
 			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
 				repo.On("GetSession").Return(shell.Session{}, nil).Once()
@@ -78,13 +86,110 @@ func TestLoginCommand_Execute(t *testing.T) {
 			expectedOutput: "",
 			expectedError:  "login failed: invalid password\n",
 		},
+		{
+			name: "success - saved key bindings are loaded into the session",
+			args: []string{"testuser"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("FindUserByUsername", "testuser").Return(user.User{Username: "testuser", ID: 1}, nil).Once()
+				repo.On("UpdateLastLogin", int64(1)).Return(nil).Once()
+				repo.On("ListKeyBindings", int64(1)).Return([]user.KeyBinding{
+					{UserID: 1, Keymap: "emacs", Key: "ctrl-o", Action: "accept-line"},
+				}, nil).Once()
+			},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+				repo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.KeyBindings["emacs"]["ctrl-o"] == "accept-line"
+				})).Return(nil).Once()
+			},
+			expectedOutput: "Logged in as: testuser\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - key bindings error does not block login",
+			args: []string{"testuser"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("FindUserByUsername", "testuser").Return(user.User{Username: "testuser", ID: 1}, nil).Once()
+				repo.On("UpdateLastLogin", int64(1)).Return(nil).Once()
+				repo.On("ListKeyBindings", int64(1)).Return(nil, errors.New("db error")).Once()
+			},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+				repo.On("SetSession", mock.Anything).Return(nil).Once()
+			},
+			expectedOutput: "Logged in as: testuser\n",
+			expectedError:  "key bindings error: db error\n",
+		},
 		{
 			name:           "failure - missing username",
 			args:           []string{},
 			setupUserRepo:  func(repo *userRepository.UserRepositoryMock) {},
 			setupSession:   func(repo *shellRepository.SessionRepositoryMock) {},
 			expectedOutput: "",
-			expectedError:  "usage: login <username> [password]\n",
+			expectedError:  "login: missing username\nusage: login [--password-stdin] [--password-fd <fd>] <username> [password]\n",
+		},
+		{
+			name:      "success - password prompted when the account has one",
+			args:      []string{"testuser"},
+			passwords: []string{"correctpassword"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("FindUserByUsername", "testuser").Return(user.User{
+					Username:     "testuser",
+					ID:           1,
+					PasswordHash: &hashedPasswordStr,
+				}, nil).Once()
+				repo.On("UpdateLastLogin", int64(1)).Return(nil).Once()
+				repo.On("ListKeyBindings", int64(1)).Return([]user.KeyBinding{}, nil).Once()
+			},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+				repo.On("SetSession", mock.Anything).Return(nil).Once()
+			},
+			expectedOutput: "Logged in as: testuser\n",
+			expectedError:  "",
+		},
+		{
+			name:  "success - password from stdin",
+			args:  []string{"testuser", "--password-stdin"},
+			stdin: "correctpassword\n",
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("FindUserByUsername", "testuser").Return(user.User{
+					Username:     "testuser",
+					ID:           1,
+					PasswordHash: &hashedPasswordStr,
+				}, nil).Once()
+				repo.On("UpdateLastLogin", int64(1)).Return(nil).Once()
+				repo.On("ListKeyBindings", int64(1)).Return([]user.KeyBinding{}, nil).Once()
+			},
+			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+				repo.On("SetSession", mock.Anything).Return(nil).Once()
+			},
+			expectedOutput: "Logged in as: testuser\n",
+			expectedError:  "",
+		},
+		{
+			name: "failure - no terminal to prompt for the password",
+			args: []string{"testuser"},
+			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
+				repo.On("FindUserByUsername", "testuser").Return(user.User{
+					Username:     "testuser",
+					ID:           1,
+					PasswordHash: &hashedPasswordStr,
+				}, nil).Once()
+			},
+			setupSession:   func(repo *shellRepository.SessionRepositoryMock) {},
+			expectedOutput: "",
+			expectedError:  "login failed: invalid password\n",
+		},
+		{
+			name:           "failure - password given twice",
+			args:           []string{"testuser", "correctpassword", "--password-stdin"},
+			stdin:          "correctpassword\n",
+			setupUserRepo:  func(repo *userRepository.UserRepositoryMock) {},
+			setupSession:   func(repo *shellRepository.SessionRepositoryMock) {},
+			expectedOutput: "",
+			expectedError:  "usage: login <username> [--password-stdin | --password-fd <fd>]\n",
 		},
 		{
 			name: "failure - user not found",
@@ -115,6 +220,7 @@ func TestLoginCommand_Execute(t *testing.T) {
 			setupUserRepo: func(repo *userRepository.UserRepositoryMock) {
 				repo.On("FindUserByUsername", "testuser").Return(user.User{Username: "testuser", ID: 1}, nil).Once()
 				repo.On("UpdateLastLogin", int64(1)).Return(nil).Once()
+				repo.On("ListKeyBindings", int64(1)).Return([]user.KeyBinding{}, nil).Once()
 			},
 			setupSession: func(repo *shellRepository.SessionRepositoryMock) {
 				repo.On("GetSession").Return(shell.Session{}, nil).Once()
@@ -147,8 +253,9 @@ func TestLoginCommand_Execute(t *testing.T) {
 			var errorBuffer bytes.Buffer
 
 			userSvc := user.New(mockUserRepo)
-			cmd := commands.NewLoginCommand(userSvc, mockSessionRepo)
-			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+			historySvc := history.New(new(historyRepository.HistoryRepositoryMock), historyRepository.NewInMemory(), -1)
+			cmd := commands.NewLoginCommand(userSvc, historySvc, mockSessionRepo, &PasswordReaderStub{Passwords: tc.passwords}, history.MergeGuestAsk)
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.stdin), &outputBuffer, &errorBuffer)
 
 			assert.NoError(t, err)
 
@@ -160,3 +267,99 @@ func TestLoginCommand_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginCommand_MergeGuestHistory(t *testing.T) {
+	ctx := context.Background()
+	guestID := int64(-1)
+	startedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name           string
+		mode           string
+		lines          []string
+		merged         bool
+		expectedOutput string
+		expectedPrompt []string
+	}{
+		{
+			name:           "always merges",
+			mode:           history.MergeGuestAlways,
+			merged:         true,
+			expectedOutput: "Logged in as: testuser\nAdded 2 commands from the guest session to your history.\n",
+		},
+		{
+			name:           "never merges",
+			mode:           history.MergeGuestNever,
+			expectedOutput: "Logged in as: testuser\n",
+		},
+		{
+			name:           "ask and accept",
+			mode:           history.MergeGuestAsk,
+			lines:          []string{" Yes "},
+			merged:         true,
+			expectedOutput: "Logged in as: testuser\nAdded 2 commands from the guest session to your history.\n",
+			expectedPrompt: []string{"Add 2 commands typed as guest to the history of testuser? [y/N] "},
+		},
+		{
+			name:           "ask and decline",
+			mode:           history.MergeGuestAsk,
+			lines:          []string{""},
+			expectedOutput: "Logged in as: testuser\n",
+			expectedPrompt: []string{"Add 2 commands typed as guest to the history of testuser? [y/N] "},
+		},
+		{
+			name:           "ask without a terminal declines",
+			mode:           history.MergeGuestAsk,
+			expectedOutput: "Logged in as: testuser\n",
+			expectedPrompt: []string{"Add 2 commands typed as guest to the history of testuser? [y/N] "},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUserRepo := new(userRepository.UserRepositoryMock)
+			mockSessionRepo := new(shellRepository.SessionRepositoryMock)
+			mockHistoryRepo := new(historyRepository.HistoryRepositoryMock)
+			guestRepo := historyRepository.NewInMemory()
+
+			mockUserRepo.On("FindUserByUsername", "testuser").Return(user.User{Username: "testuser", ID: 1}, nil).Once()
+			mockUserRepo.On("UpdateLastLogin", int64(1)).Return(nil).Once()
+			mockUserRepo.On("ListKeyBindings", int64(1)).Return([]user.KeyBinding{}, nil).Once()
+			mockSessionRepo.On("GetSession").Return(shell.Session{}, nil).Once()
+			mockSessionRepo.On("SetSession", mock.Anything).Return(nil).Once()
+
+			historySvc := history.New(mockHistoryRepo, guestRepo, guestID)
+			for i, command := range []string{"ls", "cd /tmp"} {
+				createdAt := startedAt.Add(time.Duration(i) * time.Minute)
+				assert.NoError(t, guestRepo.SaveCommand(&history.CommandHistory{UserID: guestID, Command: command, StartedAt: createdAt, CreatedAt: createdAt}))
+			}
+			if tc.merged {
+				mockHistoryRepo.On("SaveCommands", mock.MatchedBy(func(entries []history.CommandHistory) bool {
+					return len(entries) == 2 &&
+						entries[0].UserID == 1 && entries[1].UserID == 1 &&
+						entries[0].ID == 0 &&
+						entries[1].Command == "ls" && entries[1].CreatedAt.Equal(startedAt)
+				})).Return(nil).Once()
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			reader := &PasswordReaderStub{Lines: tc.lines}
+			cmd := commands.NewLoginCommand(user.New(mockUserRepo), historySvc, mockSessionRepo, reader, tc.mode)
+			err := cmd.Execute(ctx, []string{"testuser"}, strings.NewReader(""), &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Empty(t, errorBuffer.String())
+			assert.Equal(t, tc.expectedPrompt, reader.Prompts)
+
+			// the next guest starts with an empty history
+			count, err := historySvc.GuestHistoryCount()
+			assert.NoError(t, err)
+			assert.Zero(t, count)
+
+			mockHistoryRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // LogoutCommand implements the logout command
@@ -30,8 +31,17 @@ func (c *LogoutCommand) MaxArguments() int {
 	return 0
 }
 
+// Arguments declares the arguments of the command
+func (c *LogoutCommand) Arguments() argparse.Spec {
+	return argparse.Spec{}
+}
+
 // Execute runs the command
 func (c *LogoutCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	if _, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args); err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
 	session, err := c.sessionRepo.GetSession()
 	if err != nil {
 		_, err = fmt.Fprintf(errorOutputWriter, "session error: %v\n", err)
@@ -39,6 +49,7 @@ func (c *LogoutCommand) Execute(ctx context.Context, args []string, inputReader
 	}
 
 	session.User = nil
+	session.KeyBindings = nil
 
 	err = c.sessionRepo.SetSession(session)
 	if err != nil {
@@ -57,5 +68,14 @@ func (c *LogoutCommand) Execute(ctx context.Context, args []string, inputReader
 
 // Help returns the help text
 func (c *LogoutCommand) Help() string {
-	return "logout - Logout current user and return to guest"
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of logout
+func (c *LogoutCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "logout",
+		Summary: "Log out and return to the guest session.",
+		SeeAlso: []string{"login"},
+	}
 }
@@ -2,14 +2,17 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // LSCommand implements the ls command
@@ -31,48 +34,367 @@ func (l *LSCommand) Name() string {
 
 // MaxArguments returns the maximum number of arguments allowed for the Command.
 func (c *LSCommand) MaxArguments() int {
-	return 1
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (l *LSCommand) Arguments() argparse.Spec {
+	whens := []string{"always", "auto", "never"}
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: '1', Description: "Print one entry per line."},
+			{Short: 'a', Long: "all", Description: "Include hidden entries, and . and .."},
+			{Short: 'A', Long: "almost-all", Description: "Include hidden entries but not . and .."},
+			{Short: 'C', Description: "Print entries in columns, using $COLUMNS or 80 when the output is not a terminal."},
+			{Short: 'h', Long: "human-readable", Description: "With -l, show sizes as 1.5K, 12M and so on."},
+			{Short: 'l', Description: "Long format: mode, links, owner, group, size, modification time and name; symlinks show their target."},
+			{Short: 'r', Long: "reverse", Description: "Reverse the sort order."},
+			{Short: 'R', Long: "recursive", Description: "List subdirectories recursively."},
+			{Short: 'S', Description: "Sort by size, largest first."},
+			{Short: 't', Description: "Sort by modification time, newest first."},
+			{Long: "color", Type: argparse.String, Implied: "always", Choices: whens, Description: "Colour names always, never, or when the output is a terminal (auto, the default)."},
+			{Long: "colour", Type: argparse.String, Implied: "always", Choices: whens, Description: "Same as --color."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Optional: true, Variadic: true},
+		},
+	}
 }
 
 // Execute runs the command
 func (l *LSCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, l.Name(), l.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
 	session, err := l.sessionRepo.GetSession()
 	if err != nil {
 		_, err := fmt.Fprintf(errorOutputWriter, "session error: %v\n", err)
 		return err
 	}
 
-	dirPath := session.WorkingDir
-	if len(args) > 0 {
-		dirPath = filepath.Join(session.WorkingDir, args[0])
+	opts := lsOptionsFrom(parsed)
+	operands := parsed.Strings("file")
+	if len(operands) == 0 {
+		operands = []string{"."}
 	}
 
-	entries, err := os.ReadDir(dirPath)
-	if err != nil {
-		_, err := fmt.Fprintf(errorOutputWriter, "dir error: %v\n", err)
+	lister := newLSLister(opts, outputWriter, errorOutputWriter)
+	if err := lister.list(session.WorkingDir, operands); err != nil {
+		_, err := fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
 		return err
 	}
 
-	var output []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			output = append(output, entry.Name()+"/")
+	return nil
+}
+
+// Help returns the help text
+func (l *LSCommand) Help() string {
+	return shell.HelpLine(l.Doc())
+}
+
+// Doc returns the documentation of ls
+func (l *LSCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "ls",
+		Summary: "List directory contents.",
+		Description: "Lists the files and the contents of the directories given, or of the working directory. Operands may be glob patterns, which ls expands itself. Files are listed first, then each directory under a header when there are several operands. Directories are marked with a trailing /." +
+			"\n\n" +
+			"On a terminal entries are laid out in columns fitting its width and coloured according to LS_COLORS; otherwise one entry is printed per line.",
+		Examples: []shell.HelpExample{
+			{Command: "ls -lAh", Description: "Long listing with hidden files and readable sizes."},
+			{Command: "ls -tr *.log", Description: "Log files, oldest first."},
+		},
+		SeeAlso: []string{"cd", "cat"},
+	}
+}
+
+// lsOptions holds the flags of ls
+type lsOptions struct {
+	all       bool // -a: also . and .. and hidden entries
+	almostAll bool // -A: hidden entries but not . and ..
+	long      bool
+	human     bool
+	recursive bool
+	reverse   bool
+	sortBy    byte   // 0 for names, 't' for modification time, 'S' for size
+	layout    byte   // 0 to decide from the output, '1' for one per line, 'C' for columns
+	color     string // always, auto or never
+}
+
+// lsOptionsFrom returns the options of ls given by its flags. Of -t and -S,
+// and of -1 and -C, the last one given is used.
+func lsOptionsFrom(parsed *argparse.Args) lsOptions {
+	opts := lsOptions{
+		all:       parsed.Bool("a"),
+		almostAll: parsed.Bool("A"),
+		long:      parsed.Bool("l"),
+		human:     parsed.Bool("h"),
+		recursive: parsed.Bool("R"),
+		reverse:   parsed.Bool("r"),
+		color:     "auto",
+	}
+	if sortBy := parsed.Last("t", "S"); sortBy != "" {
+		opts.sortBy = sortBy[0]
+	}
+	if layout := parsed.Last("1", "C"); layout != "" {
+		opts.layout = layout[0]
+	}
+	if color := parsed.Last("color", "colour"); color != "" {
+		opts.color = parsed.String(color)
+	}
+	return opts
+}
+
+// lsEntry is a file listed by ls
+type lsEntry struct {
+	name string // name as displayed
+	path string // absolute path
+	info fs.FileInfo
+}
+
+// lsLister lists files and directories for one run of ls
+type lsLister struct {
+	opts     lsOptions
+	out      io.Writer
+	errOut   io.Writer
+	colors   *lsColors // nil when output is not coloured
+	width    int
+	columns  bool
+	owners   map[string]string
+	groups   map[string]string
+	operands int
+}
+
+func newLSLister(opts lsOptions, out, errOut io.Writer) *lsLister {
+	isTerminal, width := terminalInfo(out)
+
+	l := &lsLister{
+		opts:    opts,
+		out:     out,
+		errOut:  errOut,
+		width:   width,
+		columns: opts.layout == 'C' || (opts.layout == 0 && isTerminal),
+		owners:  make(map[string]string),
+		groups:  make(map[string]string),
+	}
+	if opts.color == "always" || (opts.color == "auto" && isTerminal) {
+		l.colors = parseLSColors(os.Getenv("LS_COLORS"))
+	}
+
+	return l
+}
+
+// list lists the operands like GNU ls: files first, then the contents of each
+// directory under a header when there is more than one operand
+func (l *lsLister) list(workingDir string, operands []string) error {
+	var files, dirs []lsEntry
+	for _, entry := range l.expandOperands(workingDir, operands) {
+		if entry.info.IsDir() {
+			dirs = append(dirs, entry)
 		} else {
-			output = append(output, entry.Name())
+			files = append(files, entry)
+		}
+	}
+
+	l.sortEntries(files)
+	l.sortEntries(dirs)
+
+	if len(files) > 0 {
+		if err := l.printEntries(files, false); err != nil {
+			return err
+		}
+	}
+
+	headers := l.operands > 1 || l.opts.recursive
+	for i, dir := range dirs {
+		if i > 0 || len(files) > 0 {
+			if _, err := fmt.Fprintln(l.out); err != nil {
+				return err
+			}
+		}
+		if err := l.listDirectory(dir.path, dir.name, headers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expandOperands expands ~ and glob patterns in the operands and looks the
+// files up. Operands that cannot be accessed are reported and skipped.
+func (l *lsLister) expandOperands(workingDir string, operands []string) []lsEntry {
+	var entries []lsEntry
+
+	for _, operand := range operands {
+		expanded, err := expandTilde(operand)
+		if err != nil {
+			l.reportf("cannot access '%s': %v", operand, err)
+			l.operands++
+			continue
+		}
+
+		names := []string{expanded}
+		if strings.ContainsAny(expanded, "*?[") {
+			matches, _ := filepath.Glob(resolvePath(workingDir, expanded))
+			if len(matches) == 0 {
+				l.reportf("cannot access '%s': no such file or directory", operand)
+				l.operands++
+				continue
+			}
+
+			names = names[:0]
+			for _, match := range matches {
+				if !filepath.IsAbs(expanded) {
+					match, _ = filepath.Rel(workingDir, match)
+				}
+				names = append(names, match)
+			}
 		}
+
+		for _, name := range names {
+			l.operands++
+
+			path := resolvePath(workingDir, name)
+			info, err := l.statOperand(path)
+			if err != nil {
+				l.reportf("cannot access '%s': %v", name, pathError(err))
+				continue
+			}
+
+			entries = append(entries, lsEntry{name: name, path: path, info: info})
+		}
+	}
+
+	return entries
+}
+
+// statOperand looks up an operand. Symlinks are followed except in the long
+// format, and broken ones are listed as links.
+func (l *lsLister) statOperand(path string) (fs.FileInfo, error) {
+	if l.opts.long {
+		return os.Lstat(path)
 	}
 
-	sort.Strings(output)
-	_, err = fmt.Fprintf(outputWriter, "%s\n", strings.Join(output, "\n"))
+	info, err := os.Stat(path)
 	if err != nil {
-		_, err := fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+		if linkInfo, linkErr := os.Lstat(path); linkErr == nil {
+			return linkInfo, nil
+		}
+	}
+	return info, err
+}
+
+// listDirectory prints the contents of a directory, then those of its
+// subdirectories with -R
+func (l *lsLister) listDirectory(path, name string, header bool) error {
+	if header {
+		if _, err := fmt.Fprintf(l.out, "%s:\n", name); err != nil {
+			return err
+		}
+	}
+
+	entries, err := l.readDirectory(path)
+	if err != nil {
+		l.reportf("cannot open directory '%s': %v", name, pathError(err))
+		return nil
+	}
+
+	l.sortEntries(entries)
+	if err := l.printEntries(entries, true); err != nil {
 		return err
 	}
 
+	if !l.opts.recursive {
+		return nil
+	}
+
+	for _, entry := range entries {
+		if !entry.info.IsDir() || entry.name == "." || entry.name == ".." {
+			continue
+		}
+		if _, err := fmt.Fprintln(l.out); err != nil {
+			return err
+		}
+		if err := l.listDirectory(entry.path, strings.TrimSuffix(name, "/")+"/"+entry.name, true); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// Help returns the help text
-func (l *LSCommand) Help() string {
-	return "ls [dir] - Lists the contents of the specified directory (or current directory if none specified)"
+// readDirectory returns the entries of a directory that are shown with the
+// current flags
+func (l *lsLister) readDirectory(path string) ([]lsEntry, error) {
+	dirEntries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []lsEntry
+	if l.opts.all {
+		for _, name := range []string{".", ".."} {
+			info, err := os.Lstat(filepath.Join(path, name))
+			if err == nil {
+				entries = append(entries, lsEntry{name: name, path: filepath.Join(path, name), info: info})
+			}
+		}
+	}
+
+	for _, dirEntry := range dirEntries {
+		if strings.HasPrefix(dirEntry.Name(), ".") && !l.opts.all && !l.opts.almostAll {
+			continue
+		}
+
+		// entries removed while listing are skipped
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, lsEntry{name: dirEntry.Name(), path: filepath.Join(path, dirEntry.Name()), info: info})
+	}
+
+	return entries, nil
+}
+
+// sortEntries sorts by name, modification time (newest first) or size
+// (largest first), names breaking ties
+func (l *lsLister) sortEntries(entries []lsEntry) {
+	less := func(a, b lsEntry) bool {
+		switch l.opts.sortBy {
+		case 't':
+			if !a.info.ModTime().Equal(b.info.ModTime()) {
+				return a.info.ModTime().After(b.info.ModTime())
+			}
+		case 'S':
+			if a.info.Size() != b.info.Size() {
+				return a.info.Size() > b.info.Size()
+			}
+		}
+		return a.name < b.name
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if l.opts.reverse {
+			return less(entries[j], entries[i])
+		}
+		return less(entries[i], entries[j])
+	})
+}
+
+// reportf prints an error about one operand; ls carries on with the others
+func (l *lsLister) reportf(format string, args ...any) {
+	fmt.Fprintf(l.errOut, "ls: "+format+"\n", args...)
+}
+
+// pathError strips the operation and path from file system errors, since ls
+// names the file itself
+func pathError(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	return err
 }
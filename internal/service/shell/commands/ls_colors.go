@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// defaultLSColors is used when LS_COLORS is not set. It has the colours of
+// the GNU dircolors defaults for file types.
+const defaultLSColors = "di=01;34:ln=01;36:pi=40;33:so=01;35:do=01;35:bd=40;33;01:cd=40;33;01:or=40;31;01:" +
+	"su=37;41:sg=30;43:tw=30;42:ow=34;42:st=37;44:ex=01;32"
+
+// lsColors holds the colours parsed from LS_COLORS
+type lsColors struct {
+	types      map[string]string // two letter file type keys such as di or ex
+	extensions map[string]string // *.ext patterns, without the *
+}
+
+// parseLSColors parses a colon separated LS_COLORS value such as
+// "di=01;34:*.tar=01;31". Malformed entries are ignored.
+func parseLSColors(spec string) *lsColors {
+	if spec == "" {
+		spec = defaultLSColors
+	}
+
+	colors := &lsColors{
+		types:      make(map[string]string),
+		extensions: make(map[string]string),
+	}
+	for _, item := range strings.Split(spec, ":") {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok || key == "" {
+			continue
+		}
+		if suffix, isPattern := strings.CutPrefix(key, "*"); isPattern {
+			colors.extensions[suffix] = value
+		} else {
+			colors.types[key] = value
+		}
+	}
+
+	return colors
+}
+
+// paint returns the name of entry wrapped in the colour of its type. A nil
+// lsColors leaves names uncoloured.
+func (c *lsColors) paint(entry lsEntry) string {
+	if c == nil {
+		return entry.name
+	}
+
+	code := c.code(entry)
+	if code == "" || code == "0" || code == "00" {
+		return entry.name
+	}
+	return "\x1b[" + code + "m" + entry.name + "\x1b[0m"
+}
+
+// code returns the colour of an entry, checking file types in the order GNU
+// ls does: special types and permissions first, then extensions
+func (c *lsColors) code(entry lsEntry) string {
+	mode := entry.info.Mode()
+
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		target, err := os.Stat(entry.path)
+		if err != nil {
+			return c.first("or", "ln")
+		}
+		if c.types["ln"] == "target" {
+			return c.code(lsEntry{name: entry.name, path: entry.path, info: target})
+		}
+		return c.types["ln"]
+
+	case mode.IsDir():
+		sticky, otherWritable := mode&fs.ModeSticky != 0, mode&0o002 != 0
+		switch {
+		case sticky && otherWritable:
+			return c.first("tw", "di")
+		case otherWritable:
+			return c.first("ow", "di")
+		case sticky:
+			return c.first("st", "di")
+		}
+		return c.types["di"]
+
+	case mode&fs.ModeNamedPipe != 0:
+		return c.types["pi"]
+	case mode&fs.ModeSocket != 0:
+		return c.types["so"]
+	case mode&fs.ModeCharDevice != 0:
+		return c.types["cd"]
+	case mode&fs.ModeDevice != 0:
+		return c.types["bd"]
+	}
+
+	switch {
+	case mode&fs.ModeSetuid != 0 && c.types["su"] != "":
+		return c.types["su"]
+	case mode&fs.ModeSetgid != 0 && c.types["sg"] != "":
+		return c.types["sg"]
+	case mode&0o111 != 0 && c.types["ex"] != "":
+		return c.types["ex"]
+	}
+
+	// the longest matching pattern wins
+	code, matched := c.types["fi"], 0
+	for suffix, value := range c.extensions {
+		if len(suffix) > matched && strings.HasSuffix(entry.name, suffix) {
+			code, matched = value, len(suffix)
+		}
+	}
+	return code
+}
+
+// first returns the colour of the first key that has one
+func (c *lsColors) first(keys ...string) string {
+	for _, key := range keys {
+		if code := c.types[key]; code != "" {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,301 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// defaultTerminalWidth is used when the width of the output is unknown
+const defaultTerminalWidth = 80
+
+// terminalInfo reports whether w is a terminal and how wide it is. Other
+// outputs use $COLUMNS, or 80 columns, when columns are requested.
+func terminalInfo(w io.Writer) (bool, int) {
+	width := defaultTerminalWidth
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		width = columns
+	}
+
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return false, width
+	}
+	if columns, _, err := term.GetSize(int(f.Fd())); err == nil && columns > 0 {
+		width = columns
+	}
+	return true, width
+}
+
+// printEntries prints entries in the long, column or one per line format.
+// Directory listings start with the total size in the long format.
+func (l *lsLister) printEntries(entries []lsEntry, directory bool) error {
+	switch {
+	case l.opts.long:
+		return l.printLong(entries, directory)
+	case l.columns:
+		return l.printColumns(entries)
+	default:
+		for _, entry := range entries {
+			if _, err := fmt.Fprintln(l.out, l.displayName(entry)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// displayName returns the name of an entry, coloured when enabled and with a
+// / after directories
+func (l *lsLister) displayName(entry lsEntry) string {
+	name := l.colors.paint(entry)
+	if entry.info.IsDir() {
+		name += "/"
+	}
+	return name
+}
+
+// printColumns prints entries in columns filled top to bottom, using as few
+// rows as fit in the output width
+func (l *lsLister) printColumns(entries []lsEntry) error {
+	n := len(entries)
+	if n == 0 {
+		return nil
+	}
+
+	names := make([]string, n)
+	lengths := make([]int, n)
+	for i, entry := range entries {
+		names[i] = l.displayName(entry)
+		lengths[i] = utf8.RuneCountInString(entry.name)
+		if entry.info.IsDir() {
+			lengths[i]++
+		}
+	}
+
+	const gap = 2
+	var rows int
+	var widths []int
+	for rows = 1; rows <= n; rows++ {
+		cols := (n + rows - 1) / rows
+		widths = make([]int, cols)
+		total := gap * (cols - 1)
+		for c := range widths {
+			for r := 0; r < rows && c*rows+r < n; r++ {
+				widths[c] = max(widths[c], lengths[c*rows+r])
+			}
+			total += widths[c]
+		}
+		if total <= l.width {
+			break
+		}
+	}
+	rows = min(rows, n)
+
+	for r := 0; r < rows; r++ {
+		var line strings.Builder
+		for c := range widths {
+			i := c*rows + r
+			if i >= n {
+				break
+			}
+			line.WriteString(names[i])
+			if i+rows < n {
+				line.WriteString(strings.Repeat(" ", widths[c]-lengths[i]+gap))
+			}
+		}
+		if _, err := fmt.Fprintln(l.out, line.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printLong prints one entry per line with its mode, link count, owner,
+// group, size and modification time
+func (l *lsLister) printLong(entries []lsEntry, directory bool) error {
+	type row struct {
+		mode, links, owner, group, size, modified, name string
+	}
+
+	now := time.Now()
+	rows := make([]row, len(entries))
+	var blocks int64
+	var linksWidth, ownerWidth, groupWidth, sizeWidth int
+
+	for i, entry := range entries {
+		st := fileStat(entry.info)
+		blocks += st.blocks
+
+		size := strconv.FormatInt(entry.info.Size(), 10)
+		if l.opts.human {
+			size = humanSize(entry.info.Size())
+		}
+
+		name := l.displayName(entry)
+		if entry.info.Mode()&fs.ModeSymlink != 0 {
+			if target, err := os.Readlink(entry.path); err == nil {
+				name += " -> " + target
+			}
+		}
+
+		rows[i] = row{
+			mode:     lsMode(entry.info.Mode()),
+			links:    strconv.FormatUint(st.links, 10),
+			owner:    l.lookupName(l.owners, st.uid, lookupUserName),
+			group:    l.lookupName(l.groups, st.gid, lookupGroupName),
+			size:     size,
+			modified: lsTime(entry.info.ModTime(), now),
+			name:     name,
+		}
+
+		linksWidth = max(linksWidth, len(rows[i].links))
+		ownerWidth = max(ownerWidth, utf8.RuneCountInString(rows[i].owner))
+		groupWidth = max(groupWidth, utf8.RuneCountInString(rows[i].group))
+		sizeWidth = max(sizeWidth, len(rows[i].size))
+	}
+
+	// the total is counted in 1024 byte blocks like GNU ls
+	if directory {
+		total := strconv.FormatInt((blocks+1)/2, 10)
+		if l.opts.human {
+			total = humanSize(blocks * 512)
+		}
+		if _, err := fmt.Fprintf(l.out, "total %s\n", total); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range rows {
+		_, err := fmt.Fprintf(l.out, "%s %*s %-*s %-*s %*s %s %s\n",
+			r.mode, linksWidth, r.links, ownerWidth, r.owner, groupWidth, r.group, sizeWidth, r.size, r.modified, r.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookupName resolves a user or group ID to a name, caching the result.
+// Unknown IDs are shown as numbers.
+func (l *lsLister) lookupName(cache map[string]string, id string, lookup func(string) (string, error)) string {
+	if id == "" {
+		return "-"
+	}
+	if name, ok := cache[id]; ok {
+		return name
+	}
+
+	name, err := lookup(id)
+	if err != nil {
+		name = id
+	}
+	cache[id] = name
+	return name
+}
+
+func lookupUserName(uid string) (string, error) {
+	u, err := user.LookupId(uid)
+	if err != nil {
+		return "", err
+	}
+	return u.Username, nil
+}
+
+func lookupGroupName(gid string) (string, error) {
+	g, err := user.LookupGroupId(gid)
+	if err != nil {
+		return "", err
+	}
+	return g.Name, nil
+}
+
+// lsMode formats a file mode like ls -l, e.g. drwxr-xr-x or -rwsr-xr-x
+func lsMode(mode fs.FileMode) string {
+	b := []byte("----------")
+	switch {
+	case mode.IsDir():
+		b[0] = 'd'
+	case mode&fs.ModeSymlink != 0:
+		b[0] = 'l'
+	case mode&fs.ModeNamedPipe != 0:
+		b[0] = 'p'
+	case mode&fs.ModeSocket != 0:
+		b[0] = 's'
+	case mode&fs.ModeCharDevice != 0:
+		b[0] = 'c'
+	case mode&fs.ModeDevice != 0:
+		b[0] = 'b'
+	}
+
+	const rwx = "rwxrwxrwx"
+	for i := 0; i < 9; i++ {
+		if mode&(1<<uint(8-i)) != 0 {
+			b[i+1] = rwx[i]
+		}
+	}
+
+	special := func(i int, set bool, lower, upper byte) {
+		if !set {
+			return
+		}
+		if b[i] == 'x' {
+			b[i] = lower
+		} else {
+			b[i] = upper
+		}
+	}
+	special(3, mode&fs.ModeSetuid != 0, 's', 'S')
+	special(6, mode&fs.ModeSetgid != 0, 's', 'S')
+	special(9, mode&fs.ModeSticky != 0, 't', 'T')
+
+	return string(b)
+}
+
+// lsTime formats a modification time like ls -l: the time of day for the
+// last six months, the year for older or future times
+func lsTime(t, now time.Time) string {
+	if t.After(now.AddDate(0, -6, 0)) && !t.After(now) {
+		return t.Format("Jan _2 15:04")
+	}
+	return t.Format("Jan _2  2006")
+}
+
+// humanSize formats a size with a binary unit suffix like ls -h, rounding
+// up: 1023, 1.0K, 9.9K, 10K, 1.5M
+func humanSize(n int64) string {
+	if n < 1024 {
+		return strconv.FormatInt(n, 10)
+	}
+
+	const units = "KMGTPE"
+	v := float64(n)
+	unit := -1
+	for v >= 1024 && unit < len(units)-1 {
+		v /= 1024
+		unit++
+	}
+
+	if v < 10 {
+		if rounded := math.Ceil(v*10) / 10; rounded < 10 {
+			return fmt.Sprintf("%.1f%c", rounded, units[unit])
+		}
+	}
+
+	v = math.Ceil(v)
+	if v >= 1024 && unit < len(units)-1 {
+		return fmt.Sprintf("1.0%c", units[unit+1])
+	}
+	return fmt.Sprintf("%.0f%c", v, units[unit])
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package commands
+
+import "io/fs"
+
+// lsStat holds the file details of ls -l that fs.FileInfo does not expose
+type lsStat struct {
+	links    uint64
+	uid, gid string
+	blocks   int64 // 512 byte blocks
+}
+
+// fileStat returns the link count and allocated blocks of a file. Owners are
+// not known on this platform.
+func fileStat(info fs.FileInfo) lsStat {
+	return lsStat{links: 1, blocks: (info.Size() + 511) / 512}
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package commands
+
+import (
+	"io/fs"
+	"strconv"
+	"syscall"
+)
+
+// lsStat holds the file details of ls -l that fs.FileInfo does not expose
+type lsStat struct {
+	links    uint64
+	uid, gid string
+	blocks   int64 // 512 byte blocks
+}
+
+// fileStat returns the link count, owner, group and allocated blocks of a file
+func fileStat(info fs.FileInfo) lsStat {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return lsStat{links: 1, blocks: (info.Size() + 511) / 512}
+	}
+
+	return lsStat{
+		links:  uint64(st.Nlink),
+		uid:    strconv.FormatUint(uint64(st.Uid), 10),
+		gid:    strconv.FormatUint(uint64(st.Gid), 10),
+		blocks: int64(st.Blocks),
+	}
+}
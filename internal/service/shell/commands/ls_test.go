@@ -6,9 +6,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
@@ -31,13 +33,33 @@ func TestLSCommand_Execute(t *testing.T) {
 	os.Mkdir(filepath.Join(tempDir, "dir1"), 0755)
 	os.Mkdir(filepath.Join(tempDir, "dir2"), 0755)
 	os.WriteFile(filepath.Join(tempDir, "file1.txt"), []byte("file1"), 0644)
-	os.WriteFile(filepath.Join(tempDir, "file2.txt"), []byte("file2"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "file2.txt"), []byte("file2 is larger"), 0644)
+	os.WriteFile(filepath.Join(tempDir, ".hidden"), []byte("hidden"), 0644)
+	os.MkdirAll(filepath.Join(tempDir, "dir2", "sub"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "dir2", "sub", "deep.txt"), []byte("deep"), 0644)
+
+	// modification times from oldest to newest
+	base := time.Now().Add(-time.Hour)
+	for i, name := range []string{"dir1", "dir2", "file1.txt", "file2.txt"} {
+		modified := base.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(filepath.Join(tempDir, name), modified, modified)
+	}
+
+	// files for the long format outside the listed directory
+	otherDir := t.TempDir()
+	os.WriteFile(filepath.Join(otherDir, "big.bin"), make([]byte, 1536), 0644)
+	os.Chmod(filepath.Join(otherDir, "big.bin"), 0644)
+	os.Symlink("big.bin", filepath.Join(otherDir, "link"))
+
+	t.Setenv("COLUMNS", "20")
+	t.Setenv("LS_COLORS", "di=01;34:*.txt=00;31")
 
 	cases := []struct {
 		name           string
 		args           []string
 		setupSession   func(repo *repository.SessionRepositoryMock)
 		expectedOutput string
+		outputPattern  string // checked instead of expectedOutput when set
 		expectedError  string
 	}{
 		{
@@ -54,9 +76,87 @@ func TestLSCommand_Execute(t *testing.T) {
 			setupSession: func(repo *repository.SessionRepositoryMock) {
 				repo.On("GetSession").Return(shell.Session{WorkingDir: tempDir}, nil).Once()
 			},
-			expectedOutput: "\n", // dir1 is empty
+			expectedOutput: "", // dir1 is empty
 			expectedError:  "",
 		},
+		{
+			name:           "success - all entries",
+			args:           []string{"-a"},
+			setupSession:   sessionIn(tempDir),
+			expectedOutput: "./\n../\n.hidden\ndir1/\ndir2/\nfile1.txt\nfile2.txt\n",
+		},
+		{
+			name:           "success - almost all entries",
+			args:           []string{"-A"},
+			setupSession:   sessionIn(tempDir),
+			expectedOutput: ".hidden\ndir1/\ndir2/\nfile1.txt\nfile2.txt\n",
+		},
+		{
+			name:           "success - reverse order",
+			args:           []string{"-r"},
+			setupSession:   sessionIn(tempDir),
+			expectedOutput: "file2.txt\nfile1.txt\ndir2/\ndir1/\n",
+		},
+		{
+			name:           "success - sort by modification time",
+			args:           []string{"-t"},
+			setupSession:   sessionIn(tempDir),
+			expectedOutput: "file2.txt\nfile1.txt\ndir2/\ndir1/\n",
+		},
+		{
+			name:           "success - sort by size with a glob operand",
+			args:           []string{"-S", "*.txt"},
+			setupSession:   sessionIn(tempDir),
+			expectedOutput: "file2.txt\nfile1.txt\n",
+		},
+		{
+			name:           "success - files before directories with headers",
+			args:           []string{"dir1", "file1.txt"},
+			setupSession:   sessionIn(tempDir),
+			expectedOutput: "file1.txt\n\ndir1:\n",
+		},
+		{
+			name:           "success - recursive",
+			args:           []string{"-R", "dir2"},
+			setupSession:   sessionIn(tempDir),
+			expectedOutput: "dir2:\nsub/\n\ndir2/sub:\ndeep.txt\n",
+		},
+		{
+			name:           "success - columns fitted to COLUMNS",
+			args:           []string{"-C"},
+			setupSession:   sessionIn(tempDir),
+			expectedOutput: "dir1/  file1.txt\ndir2/  file2.txt\n",
+		},
+		{
+			name:           "success - colours from LS_COLORS",
+			args:           []string{"--color=always", "-1"},
+			setupSession:   sessionIn(tempDir),
+			expectedOutput: "\x1b[01;34mdir1\x1b[0m/\n\x1b[01;34mdir2\x1b[0m/\n\x1b[00;31mfile1.txt\x1b[0m\n\x1b[00;31mfile2.txt\x1b[0m\n",
+		},
+		{
+			name:          "success - long format",
+			args:          []string{"-l", filepath.Join(otherDir, "big.bin")},
+			setupSession:  sessionIn(tempDir),
+			outputPattern: `^-rw-r--r-- 1 \S+ \S+ 1536 \w{3} [ \d]\d (\d\d:\d\d| \d{4}) ` + regexp.QuoteMeta(filepath.Join(otherDir, "big.bin")) + "\n$",
+		},
+		{
+			name:          "success - long format with human-readable sizes and symlinks",
+			args:          []string{"-lh", otherDir},
+			setupSession:  sessionIn(tempDir),
+			outputPattern: `^total \S+\n-rw-r--r-- 1 \S+ \S+ 1.5K .* big.bin\nl[rwx-]{9} 1 \S+ \S+ +\d+ .* link -> big.bin\n$`,
+		},
+		{
+			name:          "failure - invalid option",
+			args:          []string{"-x"},
+			setupSession:  func(repo *repository.SessionRepositoryMock) {},
+			expectedError: "ls: invalid option -- 'x'\nusage: ls [-1aAChlrRSt] [--color[=always|auto|never]] [--colour[=always|auto|never]] [file ...]\n",
+		},
+		{
+			name:          "failure - glob without matches",
+			args:          []string{"*.none"},
+			setupSession:  sessionIn(tempDir),
+			expectedError: "ls: cannot access '*.none': no such file or directory\n",
+		},
 		{
 			name: "failure - session error",
 			setupSession: func(repo *repository.SessionRepositoryMock) {
@@ -72,7 +172,7 @@ func TestLSCommand_Execute(t *testing.T) {
 				repo.On("GetSession").Return(shell.Session{WorkingDir: tempDir}, nil).Once()
 			},
 			expectedOutput: "",
-			expectedError:  "dir error: open " + filepath.Join(tempDir, "nonexistent") + ": no such file or directory\n",
+			expectedError:  "ls: cannot access 'nonexistent': no such file or directory\n",
 		},
 	}
 
@@ -95,9 +195,20 @@ func TestLSCommand_Execute(t *testing.T) {
 				expectedOutput = strings.ReplaceAll(expectedOutput, "/", "\\")
 			}
 
-			assert.Equal(t, expectedOutput, outputBuffer.String())
+			if tc.outputPattern != "" {
+				assert.Regexp(t, tc.outputPattern, outputBuffer.String())
+			} else {
+				assert.Equal(t, expectedOutput, outputBuffer.String())
+			}
 			assert.Equal(t, tc.expectedError, errorBuffer.String())
 			mockSessionRepo.AssertExpectations(t)
 		})
 	}
 }
+
+// sessionIn returns a session setup with the given working directory
+func sessionIn(dir string) func(repo *repository.SessionRepositoryMock) {
+	return func(repo *repository.SessionRepositoryMock) {
+		repo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil).Once()
+	}
+}
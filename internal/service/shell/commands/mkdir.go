@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// MkdirCommand implements the mkdir command
+type MkdirCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewMkdirCommand creates a new mkdir command
+func NewMkdirCommand(sessionRepo shell.SessionRepository) *MkdirCommand {
+	return &MkdirCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *MkdirCommand) Name() string {
+	return "mkdir"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *MkdirCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *MkdirCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'p', Description: "Create missing parent directories, and succeed when the directory exists."},
+			{Short: 'm', Type: argparse.String, Value: "mode", Description: "Give each directory named this octal mode, such as 700, regardless of the umask."},
+			{Short: 'v', Description: "Print each directory created."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "directory", Variadic: true},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *MkdirCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	mode := fs.FileMode(0777)
+	if parsed.Has("m") {
+		m, err := strconv.ParseUint(parsed.String("m"), 8, 32)
+		if err != nil || m > 07777 {
+			_, err = fmt.Fprintf(errorOutputWriter, "mkdir: invalid mode '%s'\n", parsed.String("m"))
+			return err
+		}
+		mode = fs.FileMode(m)
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	for _, operand := range parsed.Strings("directory") {
+		if ctx.Err() != nil {
+			return &shell.ExitStatus{Code: interruptedStatus}
+		}
+
+		// only the directory named gets the mode of -m, not its parents
+		target := resolvePath(session.WorkingDir, operand)
+		report := func(dir string) error {
+			if dir == target && parsed.Has("m") {
+				if err := os.Chmod(dir, mode); err != nil {
+					return err
+				}
+			}
+			if !parsed.Bool("v") {
+				return nil
+			}
+			_, err := fmt.Fprintf(outputWriter, "mkdir: created directory '%s'\n", displayPath(session.WorkingDir, dir))
+			return err
+		}
+
+		if parsed.Bool("p") {
+			err = makeParents(target, report)
+		} else if err = os.Mkdir(target, 0777); err == nil {
+			err = report(target)
+		}
+
+		if err != nil {
+			if err := writeFileError(errorOutputWriter, "mkdir", "cannot create directory", session.WorkingDir, operand, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// makeParents creates dir and its missing parents, calling report for each
+// directory created from the top down. An existing directory is not an error.
+func makeParents(dir string, report func(dir string) error) error {
+	if isDirectory(dir) {
+		return nil
+	}
+
+	if parent := filepath.Dir(dir); parent != dir {
+		if err := makeParents(parent, report); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Mkdir(dir, 0777); err != nil {
+		// created in the meantime
+		if errors.Is(err, fs.ErrExist) && isDirectory(dir) {
+			return nil
+		}
+		return err
+	}
+	return report(dir)
+}
+
+// Help returns the help text
+func (c *MkdirCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of mkdir
+func (c *MkdirCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "mkdir",
+		Summary:     "Create directories.",
+		Description: "Creates each directory, relative to the working directory. Without -p the parent must exist and the directory must not.",
+		Examples: []shell.HelpExample{
+			{Command: "mkdir -p src/cmd/app", Description: "Create a directory and its missing parents."},
+			{Command: "mkdir -m 700 private", Description: "Create a directory only its owner can enter."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Every directory was created."},
+			{Code: 1, Description: "A directory could not be created."},
+			{Code: 130, Description: "Ctrl-C stopped mkdir before every directory was created."},
+		},
+		SeeAlso: []string{"rm", "ls", "cd"},
+	}
+}
@@ -0,0 +1,121 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMkdirCommand_Execute(t *testing.T) {
+	cases := []struct {
+		name           string
+		args           []string
+		cancelled      bool
+		setup          func(dir string)
+		check          func(t *testing.T, dir string)
+		expectedOutput string
+		expectedError  string
+		expectedStatus int
+	}{
+		{
+			name: "success - create directories",
+			args: []string{"a", "b"},
+			check: func(t *testing.T, dir string) {
+				assert.DirExists(t, filepath.Join(dir, "a"))
+				assert.DirExists(t, filepath.Join(dir, "b"))
+			},
+		},
+		{
+			name:           "success - parents verbosely",
+			args:           []string{"-pv", "x/y/z"},
+			expectedOutput: "mkdir: created directory 'x'\nmkdir: created directory 'x/y'\nmkdir: created directory 'x/y/z'\n",
+			check: func(t *testing.T, dir string) {
+				assert.DirExists(t, filepath.Join(dir, "x/y/z"))
+			},
+		},
+		{
+			name:  "success - parents of an existing directory",
+			args:  []string{"-p", "a"},
+			setup: func(dir string) { os.Mkdir(filepath.Join(dir, "a"), 0755) },
+		},
+		{
+			name: "success - mode",
+			args: []string{"-m", "700", "private"},
+			check: func(t *testing.T, dir string) {
+				info, err := os.Stat(filepath.Join(dir, "private"))
+				assert.NoError(t, err)
+				assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+			},
+		},
+		{
+			name:          "failure - existing directory",
+			args:          []string{"a", "b"},
+			setup:         func(dir string) { os.Mkdir(filepath.Join(dir, "a"), 0755) },
+			expectedError: "mkdir: cannot create directory 'a': file exists\n",
+			check: func(t *testing.T, dir string) {
+				assert.DirExists(t, filepath.Join(dir, "b"))
+			},
+		},
+		{
+			name:          "failure - missing parent",
+			args:          []string{"x/y"},
+			expectedError: "mkdir: cannot create directory 'x/y': no such file or directory\n",
+		},
+		{
+			name:          "failure - invalid mode",
+			args:          []string{"-m", "rwx", "a"},
+			expectedError: "mkdir: invalid mode 'rwx'\n",
+		},
+		{
+			name:           "failure - interrupted",
+			args:           []string{"a"},
+			cancelled:      true,
+			expectedStatus: 130,
+			check: func(t *testing.T, dir string) {
+				assert.NoDirExists(t, filepath.Join(dir, "a"))
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tc.setup != nil {
+				tc.setup(dir)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelled {
+				cancel()
+			}
+
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewMkdirCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			if tc.expectedStatus == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, &shell.ExitStatus{Code: tc.expectedStatus}, err)
+			}
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			if tc.check != nil {
+				tc.check(t, dir)
+			}
+		})
+	}
+}
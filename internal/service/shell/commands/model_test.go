@@ -3,6 +3,8 @@ package commands_test
 import (
 	"context"
 	"io"
+
+	"github.com/Ali-Farhadnia/goshell/pkg/lineeditor"
 )
 
 type MockCommand struct {
@@ -25,3 +27,34 @@ func (m *MockCommand) MaxArguments() int {
 func (m *MockCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
 	return nil
 }
+
+// PasswordReaderStub answers password prompts with the queued passwords and
+// line prompts with the queued lines. With nothing queued it behaves like a
+// non-interactive input.
+type PasswordReaderStub struct {
+	Passwords []string
+	Lines     []string
+	Prompts   []string
+}
+
+func (p *PasswordReaderStub) ReadPassword(prompt string) (string, error) {
+	p.Prompts = append(p.Prompts, prompt)
+	if len(p.Passwords) == 0 {
+		return "", lineeditor.ErrNotTerminal
+	}
+
+	password := p.Passwords[0]
+	p.Passwords = p.Passwords[1:]
+	return password, nil
+}
+
+func (p *PasswordReaderStub) ReadLine(prompt string) (string, error) {
+	p.Prompts = append(p.Prompts, prompt)
+	if len(p.Lines) == 0 {
+		return "", lineeditor.ErrNotTerminal
+	}
+
+	line := p.Lines[0]
+	p.Lines = p.Lines[1:]
+	return line, nil
+}
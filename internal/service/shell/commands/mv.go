@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// MvCommand implements the mv command
+type MvCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewMvCommand creates a new mv command
+func NewMvCommand(sessionRepo shell.SessionRepository) *MvCommand {
+	return &MvCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *MvCommand) Name() string {
+	return "mv"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *MvCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *MvCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'n', Description: "Do not overwrite existing files."},
+			{Short: 'v', Description: "Print each file moved."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "source", Variadic: true},
+			{Name: "dest", Description: "The new name, or the directory to move the sources into."},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *MvCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	sources := parsed.Strings("source")
+	targets, err := destinationPaths(session.WorkingDir, sources, parsed.String("dest"))
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "mv: %v\n", err)
+		return err
+	}
+
+	for i, source := range sources {
+		if ctx.Err() != nil {
+			return &shell.ExitStatus{Code: interruptedStatus}
+		}
+
+		src, dst := resolvePath(session.WorkingDir, source), targets[i]
+		info, err := os.Lstat(src)
+		if err != nil {
+			if err := writeFileError(errorOutputWriter, "mv", "cannot stat", session.WorkingDir, source, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := os.Lstat(dst); err == nil && parsed.Bool("n") {
+			continue
+		}
+
+		var problem string
+		switch {
+		case sameFile(info, dst) && src != dst:
+			// renaming a hard link to another one of the same file does nothing
+			problem = fmt.Sprintf("'%s' and '%s' are the same file", source, displayPath(session.WorkingDir, dst))
+		case info.IsDir() && isInside(dst, src):
+			problem = fmt.Sprintf("cannot move '%s' to a subdirectory of itself, '%s'", source, displayPath(session.WorkingDir, dst))
+		}
+		if problem != "" {
+			if _, err := fmt.Fprintf(errorOutputWriter, "mv: %s\n", problem); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = movePath(ctx, src, dst, info)
+		switch {
+		case err == nil:
+			if parsed.Bool("v") {
+				if _, err := fmt.Fprintf(outputWriter, "renamed '%s' -> '%s'\n", source, displayPath(session.WorkingDir, dst)); err != nil {
+					return err
+				}
+			}
+		case isCancelled(err):
+			return &shell.ExitStatus{Code: interruptedStatus}
+		default:
+			if err := writeFileError(errorOutputWriter, "mv", "cannot move", session.WorkingDir, source, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// movePath renames src to dst. Across filesystems, where a rename is not
+// possible, src is copied and then removed; when the copy fails or is
+// interrupted src is kept and a partial copy of a directory removed.
+func movePath(ctx context.Context, src, dst string, info os.FileInfo) error {
+	err := os.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	_, statErr := os.Lstat(dst)
+	existed := statErr == nil
+
+	noReport := func(string, string) error { return nil }
+	if err := copyTree(ctx, src, dst, info, false, noReport); err != nil {
+		// a directory that was there before holds files of its own
+		if info.IsDir() && !existed {
+			os.RemoveAll(dst)
+		}
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// Help returns the help text
+func (c *MvCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of mv
+func (c *MvCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "mv",
+		Summary: "Move or rename files and directories.",
+		Description: "Renames source to dest, or moves each source into the directory dest, relative to the working directory. " +
+			"Moving to another filesystem copies the files, keeping their permissions and times, and then removes the originals." +
+			"\n\n" +
+			"Ctrl-C stops mv between files; a move across filesystems that is interrupted leaves the original in place.",
+		Examples: []shell.HelpExample{
+			{Command: "mv draft.txt final.txt", Description: "Rename a file."},
+			{Command: "mv -v app.log db.log /mnt/archive", Description: "Move files to another disk, printing each one."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Every source was moved."},
+			{Code: 1, Description: "A source could not be moved."},
+			{Code: 130, Description: "Ctrl-C stopped mv before every source was moved."},
+		},
+		SeeAlso: []string{"cp", "rm"},
+	}
+}
@@ -0,0 +1,121 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMvCommand_Execute(t *testing.T) {
+	setupTree := func(dir string) {
+		os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+		os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644)
+		os.MkdirAll(filepath.Join(dir, "src/sub"), 0755)
+		os.Mkdir(filepath.Join(dir, "dest"), 0755)
+	}
+
+	cases := []struct {
+		name           string
+		args           []string
+		cancelled      bool
+		check          func(t *testing.T, dir string)
+		expectedOutput string
+		expectedError  string
+		expectedStatus int
+	}{
+		{
+			name: "success - rename",
+			args: []string{"a.txt", "c.txt"},
+			check: func(t *testing.T, dir string) {
+				assert.NoFileExists(t, filepath.Join(dir, "a.txt"))
+				assert.FileExists(t, filepath.Join(dir, "c.txt"))
+			},
+		},
+		{
+			name:           "success - several sources into a directory verbosely",
+			args:           []string{"-v", "a.txt", "src", "dest"},
+			expectedOutput: "renamed 'a.txt' -> 'dest/a.txt'\nrenamed 'src' -> 'dest/src'\n",
+			check: func(t *testing.T, dir string) {
+				assert.FileExists(t, filepath.Join(dir, "dest/a.txt"))
+				assert.DirExists(t, filepath.Join(dir, "dest/src/sub"))
+			},
+		},
+		{
+			name: "success - no clobber",
+			args: []string{"-n", "a.txt", "b.txt"},
+			check: func(t *testing.T, dir string) {
+				content, err := os.ReadFile(filepath.Join(dir, "b.txt"))
+				assert.NoError(t, err)
+				assert.Equal(t, "b", string(content))
+				assert.FileExists(t, filepath.Join(dir, "a.txt"))
+			},
+		},
+		{
+			name:          "failure - into itself",
+			args:          []string{"src", "src/sub"},
+			expectedError: "mv: cannot move 'src' to a subdirectory of itself, 'src/sub/src'\n",
+		},
+		{
+			name:          "failure - missing source",
+			args:          []string{"missing", "a.txt", "dest"},
+			expectedError: "mv: cannot stat 'missing': no such file or directory\n",
+			check: func(t *testing.T, dir string) {
+				assert.FileExists(t, filepath.Join(dir, "dest/a.txt"))
+			},
+		},
+		{
+			name:          "failure - target not a directory",
+			args:          []string{"a.txt", "b.txt", "c.txt"},
+			expectedError: "mv: target 'c.txt' is not a directory\n",
+		},
+		{
+			name:           "failure - interrupted",
+			args:           []string{"a.txt", "c.txt"},
+			cancelled:      true,
+			expectedStatus: 130,
+			check: func(t *testing.T, dir string) {
+				assert.FileExists(t, filepath.Join(dir, "a.txt"))
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			setupTree(dir)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelled {
+				cancel()
+			}
+
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewMvCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			if tc.expectedStatus == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, &shell.ExitStatus{Code: tc.expectedStatus}, err)
+			}
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			if tc.check != nil {
+				tc.check(t, dir)
+			}
+		})
+	}
+}
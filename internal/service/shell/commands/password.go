@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+	"github.com/Ali-Farhadnia/goshell/pkg/lineeditor"
+)
+
+// errUsage reports that the arguments do not match the command usage
+var errUsage = errors.New("invalid arguments")
+
+// passwordArguments declares "<username> [password | --password-stdin |
+// --password-fd <fd>]", the arguments of a command taking a username and a
+// password
+func passwordArguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Long: "password-stdin", Description: "Read the password from the first line of the input instead of prompting."},
+			{Long: "password-fd", Type: argparse.Int, Value: "fd", Description: "Read the password from the first line of file descriptor fd."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "username"},
+			{Name: "password", Optional: true},
+		},
+	}
+}
+
+// passwordArgs holds the arguments of a command taking a username and a password
+type passwordArgs struct {
+	username    string
+	password    string
+	hasPassword bool // password given as an argument, on stdin or through a file descriptor
+}
+
+// readPasswordArgs returns the arguments parsed with passwordArguments,
+// reading the password from stdin or the file descriptor when asked to. The
+// password can only be given one way. The input of the shell is read through
+// passwordReader when it is a shell.TerminalInput.
+func readPasswordArgs(parsed *argparse.Args, inputReader io.Reader, passwordReader shell.PasswordReader) (passwordArgs, error) {
+	terminal, _ := passwordReader.(shell.TerminalInput)
+
+	args := passwordArgs{username: parsed.String("username")}
+
+	given := 0
+	for _, name := range []string{"password", "password-stdin", "password-fd"} {
+		if parsed.Has(name) {
+			given++
+		}
+	}
+
+	switch {
+	case given > 1:
+		return args, errUsage
+
+	case parsed.Has("password"):
+		args.password, args.hasPassword = parsed.String("password"), true
+
+	case parsed.Bool("password-stdin"):
+		password, err := readPasswordLine(inputReader, terminal)
+		if err != nil {
+			return args, fmt.Errorf("error reading password from stdin: %w", err)
+		}
+		args.password, args.hasPassword = password, true
+
+	case parsed.Has("password-fd"):
+		fd := parsed.String("password-fd")
+		password, err := readPasswordFD(fd, terminal)
+		if err != nil {
+			return args, fmt.Errorf("error reading password from file descriptor %s: %w", fd, err)
+		}
+		args.password, args.hasPassword = password, true
+	}
+
+	return args, nil
+}
+
+// passwordArgumentIndices returns the indices of the arguments that may be a
+// password given as a plain argument: all of them after the username except
+// the flags of passwordArguments, so that mistyped arguments recorded with
+// their usage error do not reveal a password either.
+func passwordArgumentIndices(args []string) []int {
+	var indices []int
+	username := false
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--" || arg == "--password-stdin" || strings.HasPrefix(arg, "--password-fd="):
+		case arg == "--password-fd":
+			i++
+		case !username:
+			username = true
+		default:
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// readPasswordLine reads the first line of r as a password. The input of the
+// shell is read through terminal, which may hold input it read ahead, without
+// echo; other input is read a byte at a time, so that the lines after the
+// password are left for the next reader.
+func readPasswordLine(r io.Reader, terminal shell.TerminalInput) (string, error) {
+	if r == nil {
+		return "", errors.New("no input")
+	}
+
+	var line string
+	var err error
+	if terminal != nil && r == io.Reader(terminal.Input()) {
+		line, err = terminal.ReadChars("", 0, '\n', false)
+	} else {
+		line, _, err = readUntil(r, 0, '\n')
+	}
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readPasswordFD reads a password from an inherited file descriptor. The
+// standard output and error are refused.
+func readPasswordFD(fd string, terminal shell.TerminalInput) (string, error) {
+	n, err := strconv.Atoi(fd)
+	if err != nil || n < 0 || n == 1 || n == 2 {
+		return "", fmt.Errorf("invalid file descriptor: %s", fd)
+	}
+
+	if n == 0 {
+		return readPasswordLine(os.Stdin, terminal)
+	}
+
+	f := os.NewFile(uintptr(n), "password-fd")
+	if f == nil {
+		return "", fmt.Errorf("invalid file descriptor: %s", fd)
+	}
+	defer f.Close()
+
+	return readPasswordLine(f, nil)
+}
+
+// promptPassword asks for a password on the terminal. Without a terminal an
+// empty password is returned so scripts behave as before.
+func promptPassword(passwordReader shell.PasswordReader, prompt string) (string, error) {
+	if passwordReader == nil {
+		return "", nil
+	}
+
+	password, err := passwordReader.ReadPassword(prompt)
+	if errors.Is(err, lineeditor.ErrNotTerminal) {
+		return "", nil
+	}
+	return password, err
+}
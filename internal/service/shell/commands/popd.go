@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// PopdCommand implements the popd command
+type PopdCommand struct {
+	sessionRepo shell.SessionRepository
+	frecencySVC *frecency.Service
+}
+
+// NewPopdCommand creates a new popd command
+func NewPopdCommand(sessionRepo shell.SessionRepository, frecencySVC *frecency.Service) *PopdCommand {
+	return &PopdCommand{
+		sessionRepo: sessionRepo,
+		frecencySVC: frecencySVC,
+	}
+}
+
+// Name returns the command name
+func (c *PopdCommand) Name() string {
+	return "popd"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *PopdCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *PopdCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Positionals: []argparse.Positional{
+			{Name: "entry", Optional: true, Description: "+N removes the Nth entry counting from the top, the working directory being +0, and -N the Nth from the bottom."},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *PopdCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	stack := dirStack(session)
+	if len(stack) < 2 {
+		_, err = fmt.Fprintf(errorOutputWriter, "popd: directory stack empty\n")
+		return err
+	}
+
+	n := 0
+	if parsed.Has("entry") {
+		var isIndex bool
+		n, isIndex, err = parseStackIndex(parsed.String("entry"), len(stack))
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "popd: %v\n", err)
+			return err
+		}
+		if !isIndex {
+			_, err = fmt.Fprintf(errorOutputWriter, "popd: %s: invalid argument\n", parsed.String("entry"))
+			return err
+		}
+	}
+
+	remaining := append(append([]string{}, stack[:n]...), stack[n+1:]...)
+	session.DirStack = remaining[1:]
+
+	// removing the top entry changes to the next one, other entries are just dropped
+	if n == 0 {
+		if !isDirectory(remaining[0]) {
+			_, err = fmt.Fprintf(errorOutputWriter, "popd: %s: no such directory\n", remaining[0])
+			return err
+		}
+		if err := changeWorkingDir(c.sessionRepo, c.frecencySVC, session, remaining[0], errorOutputWriter); err != nil {
+			return err
+		}
+		session.WorkingDir = remaining[0]
+	} else if err := c.sessionRepo.SetSession(session); err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error updating session: %v\n", err)
+		return err
+	}
+
+	return printDirStack(session, outputWriter)
+}
+
+// Help returns the help text
+func (c *PopdCommand) Help() string {
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of popd
+func (c *PopdCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "popd",
+		Summary:     "Remove a directory from the directory stack.",
+		Synopsis:    []string{"popd [+N | -N]"},
+		Description: "Removes the top of the directory stack and changes to the new top, then prints the stack. With +N or -N the entry at that position is removed instead, counting from the top or the bottom from 0, and the working directory stays the same unless it was that entry.",
+		Examples: []shell.HelpExample{
+			{Command: "popd", Description: "Go back to the directory saved by the last pushd."},
+		},
+		SeeAlso: []string{"pushd", "dirs", "cd"},
+	}
+}
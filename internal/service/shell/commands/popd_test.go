@@ -0,0 +1,105 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	frecencyRepository "github.com/Ali-Farhadnia/goshell/internal/service/frecency/repository"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPopdCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	tempDir := t.TempDir()
+	dirA := filepath.Join(tempDir, "a")
+	dirB := filepath.Join(tempDir, "b")
+	assert.NoError(t, os.Mkdir(dirA, 0o755))
+	assert.NoError(t, os.Mkdir(dirB, 0o755))
+
+	t.Setenv("HOME", "/nonexistent-home")
+	t.Setenv("PWD", "")
+	t.Setenv("OLDPWD", "")
+
+	cases := []struct {
+		name           string
+		args           []string
+		session        shell.Session
+		expectedDir    string
+		expectedStack  []string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - pop changes to the next directory",
+			session:        shell.Session{WorkingDir: dirA, DirStack: []string{dirB, tempDir}},
+			expectedDir:    dirB,
+			expectedStack:  []string{tempDir},
+			expectedOutput: dirB + " " + tempDir + "\n",
+		},
+		{
+			name:           "success - +N removes an entry without changing directory",
+			args:           []string{"+1"},
+			session:        shell.Session{WorkingDir: dirA, DirStack: []string{dirB, tempDir}},
+			expectedDir:    dirA,
+			expectedStack:  []string{tempDir},
+			expectedOutput: dirA + " " + tempDir + "\n",
+		},
+		{
+			name:           "success - -N counts from the bottom",
+			args:           []string{"-0"},
+			session:        shell.Session{WorkingDir: dirA, DirStack: []string{dirB, tempDir}},
+			expectedDir:    dirA,
+			expectedStack:  []string{dirB},
+			expectedOutput: dirA + " " + dirB + "\n",
+		},
+		{
+			name:          "failure - empty stack",
+			session:       shell.Session{WorkingDir: dirA},
+			expectedError: "popd: directory stack empty\n",
+		},
+		{
+			name:          "failure - index out of range",
+			args:          []string{"+5"},
+			session:       shell.Session{WorkingDir: dirA, DirStack: []string{dirB}},
+			expectedError: "popd: +5: directory stack index out of range\n",
+		},
+		{
+			name:          "failure - not an index",
+			args:          []string{dirB},
+			session:       shell.Session{WorkingDir: dirA, DirStack: []string{dirB}},
+			expectedError: "popd: " + dirB + ": invalid argument\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(tc.session, nil).Once()
+			if tc.expectedDir != "" {
+				mockRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == tc.expectedDir && assert.ObjectsAreEqual(tc.expectedStack, s.DirStack)
+				})).Return(nil).Once()
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewPopdCommand(mockRepo, frecency.New(nil, frecencyRepository.NewInMemory(), -1))
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
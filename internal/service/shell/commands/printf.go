@@ -0,0 +1,405 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+)
+
+const printfUsage = "usage: printf [-v var] format [arguments]"
+
+// variableName matches valid environment variable names
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// PrintfCommand implements the printf command
+type PrintfCommand struct {
+}
+
+// NewPrintfCommand creates a new printf command
+func NewPrintfCommand() *PrintfCommand {
+	return &PrintfCommand{}
+}
+
+// Name returns the command name
+func (c *PrintfCommand) Name() string {
+	return "printf"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *PrintfCommand) MaxArguments() int {
+	return -1
+}
+
+// Execute runs the command
+func (c *PrintfCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	variable := ""
+	if len(args) > 0 && args[0] == "-v" {
+		if len(args) < 2 {
+			_, err := fmt.Fprintln(errorOutputWriter, printfUsage)
+			return err
+		}
+		variable, args = args[1], args[2:]
+		if !variableName.MatchString(variable) {
+			_, err := fmt.Fprintf(errorOutputWriter, "printf: `%s': not a valid identifier\n", variable)
+			return err
+		}
+	}
+	if len(args) > 0 && args[0] == "--" {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		_, err := fmt.Fprintln(errorOutputWriter, printfUsage)
+		return err
+	}
+
+	operands := make([]string, len(args))
+	for i, arg := range args {
+		operands[i] = expandArgument(arg)
+	}
+
+	p := &printer{args: operands[1:], errOut: errorOutputWriter}
+	output, err := p.print(operands[0])
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "printf: %v\n", err)
+		return err
+	}
+
+	if variable != "" {
+		if err := os.Setenv(variable, output); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "printf: %v\n", err)
+			return err
+		}
+		return nil
+	}
+
+	_, err = io.WriteString(outputWriter, output)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// Help returns the help text
+func (c *PrintfCommand) Help() string {
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of printf
+func (c *PrintfCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:     "printf",
+		Summary:  "Format and print data.",
+		Synopsis: []string{"printf [-v var] format [arguments]"},
+		Description: "Prints the arguments according to format, like the POSIX printf utility, without adding a newline. The format is reused until all arguments are consumed; missing arguments count as empty strings or 0." +
+			"\n\n" +
+			"Conversions: %d %i %o %u %x %X %f %F %e %E %g %G %c %s, %b which expands backslash escapes in its argument, %q which quotes its argument for reuse as shell input, and %%. Flags -+ #0, a width and a precision, either of which may be * to take it from the arguments. Numeric arguments may be decimal, octal (0NNN), hexadecimal (0xHH) or 'c for the code of c. The format supports the escapes of echo -e and \\NNN in octal.",
+		Flags: []shell.HelpFlag{
+			{Flag: "-v var", Description: "Assign the output to the environment variable var instead of printing it."},
+		},
+		Examples: []shell.HelpExample{
+			{Command: "printf \"%-10s %5.1f\\n\" cpu 12.345 mem 3.5", Description: "Print an aligned table, reusing the format for each pair."},
+			{Command: "printf \"%q\\n\" \"my file (1).txt\"", Description: "Quote a file name so it can be pasted into a command."},
+			{Command: "printf -v GREETING \"Hello %s\" world", Description: "Store formatted text in a variable."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Success."},
+			{Code: 1, Description: "An argument was not a valid number, or the format was invalid."},
+		},
+		SeeAlso: []string{"echo"},
+	}
+}
+
+// printer formats arguments for one run of printf
+type printer struct {
+	args   []string
+	next   int
+	errOut io.Writer
+	out    strings.Builder
+}
+
+// print applies format to the arguments, repeating it while arguments are
+// left. Invalid numbers are reported and formatted as 0, like in bash.
+func (p *printer) print(format string) (string, error) {
+	for {
+		start := p.next
+		stop, err := p.printOnce(format)
+		if err != nil {
+			return "", err
+		}
+		if stop || p.next >= len(p.args) || p.next == start {
+			return p.out.String(), nil
+		}
+	}
+}
+
+// printOnce applies format once. It reports whether \c stopped the output.
+func (p *printer) printOnce(format string) (bool, error) {
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+
+		if c == '\\' {
+			end := escapeEnd(format, i)
+			text, stop := expandEscapes(format[i:end], true)
+			p.out.WriteString(text)
+			if stop {
+				return true, nil
+			}
+			i = end - 1
+			continue
+		}
+
+		if c != '%' {
+			p.out.WriteByte(c)
+			continue
+		}
+
+		if i+1 < len(format) && format[i+1] == '%' {
+			p.out.WriteByte('%')
+			i++
+			continue
+		}
+
+		end, stop, err := p.convert(format, i)
+		if err != nil || stop {
+			return stop, err
+		}
+		i = end - 1
+	}
+
+	return false, nil
+}
+
+// escapeEnd returns the end of the backslash escape starting at i
+func escapeEnd(format string, i int) int {
+	if i+1 >= len(format) {
+		return len(format)
+	}
+
+	switch c := format[i+1]; {
+	case c == 'x':
+		return i + 2 + digitsAt(format[i+2:], 16, 2)
+	case c == 'u':
+		return i + 2 + digitsAt(format[i+2:], 16, 4)
+	case c == 'U':
+		return i + 2 + digitsAt(format[i+2:], 16, 8)
+	case c >= '0' && c <= '7':
+		return i + 1 + digitsAt(format[i+1:], 8, 3)
+	default:
+		return i + 2
+	}
+}
+
+// digitsAt counts up to max leading digits of s in base
+func digitsAt(s string, base, max int) int {
+	_, n := parseDigits(s, base, max)
+	return n
+}
+
+// convert formats the conversion specification starting at format[start]
+// and returns where it ends
+func (p *printer) convert(format string, start int) (int, bool, error) {
+	i := start + 1
+
+	flags := ""
+	for i < len(format) && strings.IndexByte("-+ #0", format[i]) >= 0 {
+		flags += string(format[i])
+		i++
+	}
+
+	width, i := p.specNumber(format, i)
+	precision := ""
+	if i < len(format) && format[i] == '.' {
+		// an empty precision means 0 and a negative one from * is ignored
+		precision, i = p.specNumber(format, i+1)
+		switch {
+		case precision == "":
+			precision = ".0"
+		case strings.HasPrefix(precision, "-"):
+			precision = ""
+		default:
+			precision = "." + precision
+		}
+	}
+
+	if i >= len(format) {
+		return i, false, fmt.Errorf("%s: invalid conversion specification", format[start:])
+	}
+	verb := format[i]
+	i++
+	spec := "%" + flags + width + precision
+
+	switch verb {
+	case 'd', 'i':
+		fmt.Fprintf(&p.out, spec+"d", p.intArg())
+	case 'o', 'x', 'X':
+		fmt.Fprintf(&p.out, spec+string(verb), uint64(p.intArg()))
+	case 'u':
+		fmt.Fprintf(&p.out, spec+"d", uint64(p.intArg()))
+	case 'f', 'F', 'e', 'E':
+		fmt.Fprintf(&p.out, spec+string(verb), p.floatArg())
+	case 'g', 'G':
+		// C defaults to 6 significant digits where Go uses as many as needed
+		if precision == "" {
+			spec += ".6"
+		}
+		fmt.Fprintf(&p.out, spec+string(verb), p.floatArg())
+	case 'c':
+		arg := p.stringArg()
+		if arg != "" {
+			arg = arg[:1]
+		}
+		fmt.Fprintf(&p.out, spec+"s", arg)
+	case 's':
+		fmt.Fprintf(&p.out, spec+"s", p.stringArg())
+	case 'b':
+		text, stop := expandEscapes(p.stringArg(), false)
+		fmt.Fprintf(&p.out, spec+"s", text)
+		if stop {
+			return i, true, nil
+		}
+	case 'q':
+		fmt.Fprintf(&p.out, spec+"s", shellQuote(p.stringArg()))
+	default:
+		return i, false, fmt.Errorf("%%%c: invalid directive", verb)
+	}
+
+	return i, false, nil
+}
+
+// specNumber reads a width or precision, which is either digits or * taking
+// the value from the next argument
+func (p *printer) specNumber(format string, i int) (string, int) {
+	if i < len(format) && format[i] == '*' {
+		return strconv.FormatInt(p.intArg(), 10), i + 1
+	}
+
+	start := i
+	for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+		i++
+	}
+	return format[start:i], i
+}
+
+// stringArg returns the next argument, or an empty string when none is left
+func (p *printer) stringArg() string {
+	if p.next >= len(p.args) {
+		return ""
+	}
+	p.next++
+	return p.args[p.next-1]
+}
+
+// intArg returns the next argument as an integer. Numbers may be decimal,
+// octal with a leading 0 or hexadecimal with 0x, and 'c gives the code of c.
+func (p *printer) intArg() int64 {
+	arg := strings.TrimSpace(p.stringArg())
+	if arg == "" {
+		return 0
+	}
+	if code, ok := charCode(arg); ok {
+		return code
+	}
+
+	value, err := strconv.ParseInt(arg, 0, 64)
+	if err != nil {
+		if unsigned, uerr := strconv.ParseUint(arg, 0, 64); uerr == nil {
+			return int64(unsigned)
+		}
+		fmt.Fprintf(p.errOut, "printf: %s: invalid number\n", arg)
+		return 0
+	}
+	return value
+}
+
+// floatArg returns the next argument as a floating point number
+func (p *printer) floatArg() float64 {
+	arg := strings.TrimSpace(p.stringArg())
+	if arg == "" {
+		return 0
+	}
+	if code, ok := charCode(arg); ok {
+		return float64(code)
+	}
+
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		fmt.Fprintf(p.errOut, "printf: %s: invalid number\n", arg)
+		return 0
+	}
+	return value
+}
+
+// charCode returns the character code of a numeric argument written 'c or "c
+func charCode(arg string) (int64, bool) {
+	if len(arg) < 2 || (arg[0] != '\'' && arg[0] != '"') {
+		return 0, false
+	}
+	r := []rune(arg[1:])
+	return int64(r[0]), true
+}
+
+// shellQuote quotes s so that the shell reads it back as one word, using
+// backslashes, or $'...' when s has non-printable characters
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	printable := true
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || !unicode.IsPrint(r) {
+			printable = false
+			break
+		}
+		i += size
+	}
+
+	var b strings.Builder
+	if printable {
+		for _, r := range s {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_./:@%+=,", r) {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	}
+
+	b.WriteString("$'")
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\'' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == 0x1b:
+			b.WriteString(`\E`)
+		case (r == utf8.RuneError && size == 1) || !unicode.IsPrint(r):
+			for _, c := range []byte(s[i : i+size]) {
+				fmt.Fprintf(&b, `\%03o`, c)
+			}
+		default:
+			b.WriteRune(r)
+		}
+		i += size
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
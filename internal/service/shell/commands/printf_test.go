@@ -0,0 +1,118 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintfCommand_Execute(t *testing.T) {
+	t.Setenv("PRINTF_NAME", "GoShell")
+
+	cases := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		expectedError  string
+		expectedVar    string
+	}{
+		{
+			name:           "plain format without newline",
+			args:           []string{"Hello"},
+			expectedOutput: "Hello",
+		},
+		{
+			name:           "escapes in the format",
+			args:           []string{`a\tb\n\101\x42é\\`},
+			expectedOutput: "a\tb\nABé\\",
+		},
+		{
+			name:           "format is reused for remaining arguments",
+			args:           []string{`%s=%d\n`, "a", "1", "b", "2", "c"},
+			expectedOutput: "a=1\nb=2\nc=0\n",
+		},
+		{
+			name:           "width, precision and flags",
+			args:           []string{"[%5s|%-5s|%.2s|%05d|%+d|%x|%#o|%.3f|%e]", "ab", "cd", "xyz", "42", "7", "255", "8", "3.14159", "1500"},
+			expectedOutput: "[   ab|cd   |xy|00042|+7|ff|010|3.142|1.500000e+03]",
+		},
+		{
+			name:           "width and precision from arguments",
+			args:           []string{"[%*.*s]", "6", "3", "abcdef"},
+			expectedOutput: "[   abc]",
+		},
+		{
+			name:           "g uses six significant digits",
+			args:           []string{"%g %g", "1234567", "0.1"},
+			expectedOutput: "1.23457e+06 0.1",
+		},
+		{
+			name:           "character conversions and codes",
+			args:           []string{"%c %d %d", "hello", "'A", "0x10"},
+			expectedOutput: "h 65 16",
+		},
+		{
+			name:           "b expands escapes in the argument and \\c stops output",
+			args:           []string{"%b|%b|%s", `x\ty`, `end\cignored`, "never"},
+			expectedOutput: "x\ty|end",
+		},
+		{
+			name:           "q quotes for the shell",
+			args:           []string{"%q %q %q %q", "a b", "it's", "", "tab\there"},
+			expectedOutput: `a\ b it\'s '' $'tab\there'`,
+		},
+		{
+			name:           "percent sign and environment variables",
+			args:           []string{"100%% %s", "$PRINTF_NAME"},
+			expectedOutput: "100% GoShell",
+		},
+		{
+			name:        "v assigns the output to a variable",
+			args:        []string{"-v", "PRINTF_RESULT", "%s-%s", "a", "b"},
+			expectedVar: "a-b",
+		},
+		{
+			name:           "invalid number is reported and printed as zero",
+			args:           []string{"%d", "abc"},
+			expectedOutput: "0",
+			expectedError:  "printf: abc: invalid number\n",
+		},
+		{
+			name:          "invalid directive",
+			args:          []string{"%z"},
+			expectedError: "printf: %z: invalid directive\n",
+		},
+		{
+			name:          "invalid variable name",
+			args:          []string{"-v", "1x", "%s", "a"},
+			expectedError: "printf: `1x': not a valid identifier\n",
+		},
+		{
+			name:          "missing format",
+			args:          []string{},
+			expectedError: "usage: printf [-v var] format [arguments]\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewPrintfCommand()
+			err := cmd.Execute(context.Background(), tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			if tc.expectedVar != "" {
+				assert.Equal(t, tc.expectedVar, os.Getenv("PRINTF_RESULT"))
+				os.Unsetenv("PRINTF_RESULT")
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// PushdCommand implements the pushd command
+type PushdCommand struct {
+	sessionRepo shell.SessionRepository
+	frecencySVC *frecency.Service
+}
+
+// NewPushdCommand creates a new pushd command
+func NewPushdCommand(sessionRepo shell.SessionRepository, frecencySVC *frecency.Service) *PushdCommand {
+	return &PushdCommand{
+		sessionRepo: sessionRepo,
+		frecencySVC: frecencySVC,
+	}
+}
+
+// Name returns the command name
+func (c *PushdCommand) Name() string {
+	return "pushd"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *PushdCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *PushdCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Positionals: []argparse.Positional{
+			{Name: "dir", Optional: true, Description: "The directory to change to, resolved like cd does, or +N or -N to rotate the stack so the Nth entry from the top or the bottom is on top."},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *PushdCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	operand := parsed.String("dir")
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	stack := dirStack(session)
+
+	// without a directory the top two entries are swapped, +N and -N rotate
+	// the stack so that entry N is on top
+	var rotated []string
+	switch {
+	case !parsed.Has("dir"):
+		if len(stack) < 2 {
+			_, err = fmt.Fprintf(errorOutputWriter, "pushd: no other directory\n")
+			return err
+		}
+		rotated = append([]string{stack[1], stack[0]}, stack[2:]...)
+	default:
+		n, isIndex, err := parseStackIndex(operand, len(stack))
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "pushd: %v\n", err)
+			return err
+		}
+		if isIndex {
+			rotated = append(append([]string{}, stack[n:]...), stack[:n]...)
+			break
+		}
+
+		dir, _, err := findDirectory(session.WorkingDir, operand, false)
+		if errors.Is(err, errNotDirectory) {
+			_, err = fmt.Fprintf(errorOutputWriter, "pushd: %s: not a directory\n", operand)
+			return err
+		}
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "pushd: %v\n", err)
+			return err
+		}
+		rotated = append([]string{dir}, stack...)
+	}
+
+	session.DirStack = rotated[1:]
+	if err := changeWorkingDir(c.sessionRepo, c.frecencySVC, session, rotated[0], errorOutputWriter); err != nil {
+		return err
+	}
+
+	session.WorkingDir = rotated[0]
+	return printDirStack(session, outputWriter)
+}
+
+// Help returns the help text
+func (c *PushdCommand) Help() string {
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of pushd
+func (c *PushdCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "pushd",
+		Summary:     "Save the working directory and change directory.",
+		Synopsis:    []string{"pushd [dir | +N | -N]"},
+		Description: "Pushes the working directory on the directory stack and changes to dir, then prints the stack. Without dir the top two entries are exchanged. With +N or -N the stack is rotated so that the Nth entry, counting from the top or the bottom from 0, becomes the working directory.",
+		Examples: []shell.HelpExample{
+			{Command: "pushd /etc", Description: "Work in /etc and remember where you came from."},
+			{Command: "pushd", Description: "Switch between the two most recent directories."},
+			{Command: "pushd +2", Description: "Go to the third entry of dirs -v."},
+		},
+		SeeAlso: []string{"popd", "dirs", "cd"},
+	}
+}
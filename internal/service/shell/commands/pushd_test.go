@@ -0,0 +1,114 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	frecencyRepository "github.com/Ali-Farhadnia/goshell/internal/service/frecency/repository"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPushdCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	tempDir := t.TempDir()
+	dirA := filepath.Join(tempDir, "a")
+	dirB := filepath.Join(tempDir, "b")
+	assert.NoError(t, os.Mkdir(dirA, 0o755))
+	assert.NoError(t, os.Mkdir(dirB, 0o755))
+
+	t.Setenv("HOME", "/nonexistent-home")
+	t.Setenv("CDPATH", "")
+	t.Setenv("PWD", "")
+	t.Setenv("OLDPWD", "")
+
+	cases := []struct {
+		name           string
+		args           []string
+		session        shell.Session
+		expectedDir    string
+		expectedStack  []string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - push a directory",
+			args:           []string{dirB},
+			session:        shell.Session{WorkingDir: dirA},
+			expectedDir:    dirB,
+			expectedStack:  []string{dirA},
+			expectedOutput: dirB + " " + dirA + "\n",
+		},
+		{
+			name:           "success - no arguments swaps the top two directories",
+			session:        shell.Session{WorkingDir: dirA, DirStack: []string{dirB}},
+			expectedDir:    dirB,
+			expectedStack:  []string{dirA},
+			expectedOutput: dirB + " " + dirA + "\n",
+		},
+		{
+			name:           "success - +N rotates the stack",
+			args:           []string{"+2"},
+			session:        shell.Session{WorkingDir: dirA, DirStack: []string{tempDir, dirB}},
+			expectedDir:    dirB,
+			expectedStack:  []string{dirA, tempDir},
+			expectedOutput: dirB + " " + dirA + " " + tempDir + "\n",
+		},
+		{
+			name:           "success - -N counts from the bottom",
+			args:           []string{"-0"},
+			session:        shell.Session{WorkingDir: dirA, DirStack: []string{tempDir, dirB}},
+			expectedDir:    dirB,
+			expectedStack:  []string{dirA, tempDir},
+			expectedOutput: dirB + " " + dirA + " " + tempDir + "\n",
+		},
+		{
+			name:          "failure - no other directory",
+			session:       shell.Session{WorkingDir: dirA},
+			expectedError: "pushd: no other directory\n",
+		},
+		{
+			name:          "failure - index out of range",
+			args:          []string{"+3"},
+			session:       shell.Session{WorkingDir: dirA, DirStack: []string{dirB}},
+			expectedError: "pushd: +3: directory stack index out of range\n",
+		},
+		{
+			name:          "failure - directory does not exist",
+			args:          []string{filepath.Join(tempDir, "missing")},
+			session:       shell.Session{WorkingDir: dirA},
+			expectedError: "no such file or directory",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(tc.session, nil).Once()
+			if tc.expectedDir != "" {
+				mockRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == tc.expectedDir && assert.ObjectsAreEqual(tc.expectedStack, s.DirStack)
+				})).Return(nil).Once()
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewPushdCommand(mockRepo, frecency.New(nil, frecencyRepository.NewInMemory(), -1))
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Contains(t, errorBuffer.String(), tc.expectedError)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // PWDCommand implements the pwd command
@@ -30,8 +31,17 @@ func (c *PWDCommand) MaxArguments() int {
 	return 0
 }
 
+// Arguments declares the arguments of the command
+func (c *PWDCommand) Arguments() argparse.Spec {
+	return argparse.Spec{}
+}
+
 // Execute runs the command
 func (c *PWDCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	if _, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args); err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
 	session, err := c.sessionRepo.GetSession()
 	if err != nil {
 		_, err := fmt.Fprintf(errorOutputWriter, "session error: %v\n", err)
@@ -39,10 +49,6 @@ func (c *PWDCommand) Execute(ctx context.Context, args []string, inputReader io.
 	}
 
 	dirPath := session.WorkingDir
-	if len(args) > 0 {
-		_, err := fmt.Fprintf(errorOutputWriter, "pwd: too many arguments\n")
-		return err
-	}
 
 	_, err = fmt.Fprintf(outputWriter, "%s\n", dirPath)
 	if err != nil {
@@ -55,5 +61,14 @@ func (c *PWDCommand) Execute(ctx context.Context, args []string, inputReader io.
 
 // Help returns the help text
 func (c *PWDCommand) Help() string {
-	return "pwd - Prints the current working directory"
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of pwd
+func (c *PWDCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "pwd",
+		Summary: "Print the working directory.",
+		SeeAlso: []string{"cd"},
+	}
 }
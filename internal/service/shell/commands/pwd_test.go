@@ -39,13 +39,11 @@ func TestPWDCommand_Execute(t *testing.T) {
 			expectedError:  "session error: session error\n",
 		},
 		{
-			name: "failure - too many arguments",
-			args: []string{"extra"},
-			setupSession: func(repo *repository.SessionRepositoryMock) {
-				repo.On("GetSession").Return(shell.Session{WorkingDir: "/test/dir"}, nil).Once()
-			},
+			name:           "failure - too many arguments",
+			args:           []string{"extra"},
+			setupSession:   func(repo *repository.SessionRepositoryMock) {},
 			expectedOutput: "",
-			expectedError:  "pwd: too many arguments\n",
+			expectedError:  "pwd: too many arguments\nusage: pwd\n",
 		},
 	}
 
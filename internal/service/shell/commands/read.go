@@ -0,0 +1,444 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+	"github.com/Ali-Farhadnia/goshell/pkg/lineeditor"
+)
+
+// Exit statuses of read other than success
+const (
+	readEOF         = 1
+	readInterrupted = 130 // 128 + SIGINT
+	readTimedOut    = 142 // 128 + SIGALRM, like bash
+)
+
+// defaultIFS separates fields when IFS is not set
+const defaultIFS = " \t\n"
+
+// ReadCommand implements the read command
+type ReadCommand struct {
+	terminal shell.TerminalInput
+}
+
+// NewReadCommand creates a new read command. The terminal is used when the
+// input of the command is the input of the shell.
+func NewReadCommand(terminal shell.TerminalInput) *ReadCommand {
+	return &ReadCommand{
+		terminal: terminal,
+	}
+}
+
+// Name returns the command name
+func (c *ReadCommand) Name() string {
+	return "read"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *ReadCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *ReadCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'r', Description: "Do not treat backslashes as escapes."},
+			{Short: 'p', Type: argparse.String, Value: "prompt", Description: "Print prompt before reading when the input is a terminal."},
+			{Short: 's', Description: "Do not echo the characters typed on a terminal."},
+			{Short: 't', Type: argparse.String, Value: "seconds", Description: "Give up when the input is not complete after seconds, which may be a fraction."},
+			{Short: 'n', Type: argparse.Int, Value: "count", Description: "Return after count characters instead of waiting for the delimiter."},
+			{Short: 'd', Type: argparse.String, Value: "delim", Description: "End the input at the first character of delim instead of a newline, or at a NUL when delim is empty."},
+			{Short: 'a', Type: argparse.String, Value: "array", Description: "Assign the fields to array_0, array_1 and so on instead of to names."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "name", Optional: true, Variadic: true, Description: "The variables the fields are assigned to, the last one taking the rest of the line; REPLY gets the whole line without names."},
+		},
+	}
+}
+
+// readOptions holds how read reads its input
+type readOptions struct {
+	prompt   string
+	count    int // 0 reads up to the delimiter
+	delim    rune
+	raw      bool
+	silent   bool
+	deadline time.Time
+}
+
+// Execute runs the command
+func (c *ReadCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	names := parsed.Strings("name")
+	if parsed.Has("a") {
+		if len(names) > 0 {
+			_, err = fmt.Fprintln(errorOutputWriter, "read: names cannot be given with -a")
+			return err
+		}
+		names = []string{parsed.String("a")}
+	}
+	for _, name := range names {
+		if !variableName.MatchString(name) {
+			_, err = fmt.Fprintf(errorOutputWriter, "read: `%s': not a valid identifier\n", name)
+			return err
+		}
+	}
+
+	opts := readOptions{
+		prompt: parsed.String("p"),
+		count:  parsed.Int("n"),
+		delim:  '\n',
+		raw:    parsed.Bool("r"),
+		silent: parsed.Bool("s"),
+	}
+	if opts.count < 0 {
+		_, err = fmt.Fprintf(errorOutputWriter, "read: invalid count: %d\n", opts.count)
+		return err
+	}
+	if parsed.Has("d") {
+		opts.delim, _ = utf8.DecodeRuneInString(parsed.String("d"))
+		if parsed.String("d") == "" {
+			opts.delim = 0
+		}
+	}
+	if parsed.Has("t") {
+		seconds, err := strconv.ParseFloat(parsed.String("t"), 64)
+		if err != nil || seconds <= 0 || math.IsInf(seconds, 0) {
+			_, err = fmt.Fprintf(errorOutputWriter, "read: invalid timeout: %s\n", parsed.String("t"))
+			return err
+		}
+		opts.deadline = time.Now().Add(time.Duration(seconds * float64(time.Second)))
+	}
+
+	// -n 0 reads nothing and assigns empty values
+	var text string
+	if !parsed.Has("n") || opts.count > 0 {
+		text, err = c.read(ctx, inputReader, opts)
+	}
+
+	var status int
+	switch {
+	case err == nil:
+	case errors.Is(err, lineeditor.ErrInterrupted) || ctx.Err() != nil:
+		return &shell.ExitStatus{Code: readInterrupted}
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		status = readTimedOut
+	case errors.Is(err, io.EOF):
+		status = readEOF
+	default:
+		_, err = fmt.Fprintf(errorOutputWriter, "read: read error: %v\n", err)
+		return err
+	}
+
+	// what was read before the input ended or the time ran out is assigned too
+	if err := assignFields(unescapeInput(text, opts.raw), names, parsed.Has("a")); err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "read: %v\n", err)
+		return err
+	}
+
+	if status != 0 {
+		return &shell.ExitStatus{Code: status}
+	}
+	return nil
+}
+
+// read reads the input up to the delimiter, which is not returned. Without
+// -r a backslash before the delimiter continues the input past it. io.EOF is
+// returned when the input ends before the delimiter.
+func (c *ReadCommand) read(ctx context.Context, inputReader io.Reader, opts readOptions) (string, error) {
+	fromTerminal := c.terminal != nil && inputReader == io.Reader(c.terminal.Input())
+	if fromTerminal {
+		c.terminal.SetDeadline(opts.deadline)
+		defer c.terminal.SetDeadline(time.Time{})
+		// like bash, the prompt is only shown to someone typing
+		if !c.terminal.IsTerminal() {
+			opts.prompt = ""
+		}
+	} else if f, ok := inputReader.(*os.File); ok && !opts.deadline.IsZero() {
+		// files that cannot wait, such as regular files, never block anyway
+		if f.SetReadDeadline(opts.deadline) == nil {
+			defer f.SetReadDeadline(time.Time{})
+		}
+	}
+
+	var text string
+	count := opts.count
+	for {
+		var chunk string
+		var found bool
+		var err error
+		if fromTerminal {
+			chunk, found, err = c.readTerminal(opts, count)
+		} else {
+			chunk, found, err = readUntil(&contextReader{ctx: ctx, r: inputReader}, count, opts.delim)
+		}
+		text += chunk
+		if err != nil {
+			return text, err
+		}
+		if !found {
+			// -n was satisfied
+			return text, nil
+		}
+
+		body := strings.TrimSuffix(text, string(opts.delim))
+		if opts.raw || !endsWithEscape(body) {
+			return body, nil
+		}
+
+		// the delimiter was escaped: keep it and read on without a prompt
+		opts.prompt = ""
+		if opts.count > 0 {
+			count = opts.count - utf8.RuneCountInString(text)
+			if count <= 0 {
+				return text, nil
+			}
+		}
+	}
+}
+
+// readTerminal reads through the line editor. A newline delimited line is
+// read with line editing unless it must not be echoed.
+func (c *ReadCommand) readTerminal(opts readOptions, count int) (string, bool, error) {
+	if count == 0 && opts.delim == '\n' && !opts.silent {
+		line, err := c.terminal.ReadLine(opts.prompt)
+		if err != nil {
+			return line, false, err
+		}
+		return line + "\n", true, nil
+	}
+
+	text, err := c.terminal.ReadChars(opts.prompt, count, opts.delim, !opts.silent)
+	found := err == nil && strings.HasSuffix(text, string(opts.delim))
+	return text, found, err
+}
+
+// readUntil reads characters up to and including delim, or count of them
+// when count is positive. It reads a byte at a time so that nothing after
+// them is taken from r.
+func readUntil(r io.Reader, count int, delim rune) (string, bool, error) {
+	var b strings.Builder
+	for n := 0; count <= 0 || n < count; n++ {
+		ch, err := readChar(r)
+		b.WriteString(ch)
+		if err != nil {
+			return b.String(), false, err
+		}
+		if ch == string(delim) {
+			return b.String(), true, nil
+		}
+	}
+	return b.String(), false, nil
+}
+
+// readChar reads one UTF-8 encoded character, or a byte that does not start one
+func readChar(r io.Reader) (string, error) {
+	var buf [utf8.UTFMax]byte
+	n := 0
+	for n < len(buf) && !utf8.FullRune(buf[:n]) {
+		if _, err := io.ReadFull(r, buf[n:n+1]); err != nil {
+			if n > 0 && errors.Is(err, io.EOF) {
+				break
+			}
+			return string(buf[:n]), err
+		}
+		n++
+	}
+	return string(buf[:n]), nil
+}
+
+// endsWithEscape reports whether s ends with a backslash that is not itself
+// escaped
+func endsWithEscape(s string) bool {
+	backslashes := len(s) - len(strings.TrimRight(s, `\`))
+	return backslashes%2 == 1
+}
+
+// inputChar is a character of the input, escaped when a backslash preceded
+// it so that it does not separate fields
+type inputChar struct {
+	r       rune
+	escaped bool
+}
+
+// unescapeInput removes the backslashes of the input unless raw is set. A
+// backslash escapes the character after it and is dropped with a newline.
+func unescapeInput(text string, raw bool) []inputChar {
+	runes := []rune(text)
+	chars := make([]inputChar, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		if raw || runes[i] != '\\' {
+			chars = append(chars, inputChar{r: runes[i]})
+			continue
+		}
+
+		i++
+		if i < len(runes) && runes[i] != '\n' {
+			chars = append(chars, inputChar{r: runes[i], escaped: true})
+		}
+	}
+	return chars
+}
+
+// assignFields splits the input into fields at the characters of IFS and
+// assigns them to names, or the whole input to REPLY without names. IFS
+// white space around fields is dropped; any other IFS character ends a
+// field, so two of them make an empty one. The last name takes the rest of
+// the input. With array, every field is assigned to array_0, array_1 and so
+// on.
+func assignFields(chars []inputChar, names []string, array bool) error {
+	ifs, ok := os.LookupEnv("IFS")
+	if !ok {
+		ifs = defaultIFS
+	}
+	s := &fieldSplitter{chars: chars, ifs: ifs}
+
+	if len(names) == 0 {
+		return os.Setenv("REPLY", s.text(0, len(chars)))
+	}
+
+	s.skipWhitespace()
+	if array {
+		var fields []string
+		for s.pos < len(s.chars) {
+			fields = append(fields, s.next())
+		}
+		return assignArray(names[0], fields)
+	}
+
+	for i, name := range names {
+		var value string
+		if i < len(names)-1 {
+			value = s.next()
+		} else {
+			value = s.rest()
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignArray sets name_0, name_1 and so on to the fields and unsets the
+// elements left from a longer array, as the shell has no arrays of its own
+func assignArray(name string, fields []string) error {
+	for i, field := range fields {
+		if err := os.Setenv(fmt.Sprintf("%s_%d", name, i), field); err != nil {
+			return err
+		}
+	}
+	for i := len(fields); ; i++ {
+		element := fmt.Sprintf("%s_%d", name, i)
+		if _, ok := os.LookupEnv(element); !ok {
+			return nil
+		}
+		if err := os.Unsetenv(element); err != nil {
+			return err
+		}
+	}
+}
+
+// fieldSplitter splits the input into fields at IFS characters
+type fieldSplitter struct {
+	chars []inputChar
+	ifs   string
+	pos   int
+}
+
+// isSeparator reports whether c separates fields
+func (s *fieldSplitter) isSeparator(c inputChar) bool {
+	return !c.escaped && strings.ContainsRune(s.ifs, c.r)
+}
+
+// isWhitespace reports whether c is IFS white space
+func (s *fieldSplitter) isWhitespace(c inputChar) bool {
+	return s.isSeparator(c) && strings.ContainsRune(defaultIFS, c.r)
+}
+
+func (s *fieldSplitter) skipWhitespace() {
+	for s.pos < len(s.chars) && s.isWhitespace(s.chars[s.pos]) {
+		s.pos++
+	}
+}
+
+// next returns the next field and skips the separator after it: white space
+// around at most one other separator character
+func (s *fieldSplitter) next() string {
+	start := s.pos
+	for s.pos < len(s.chars) && !s.isSeparator(s.chars[s.pos]) {
+		s.pos++
+	}
+	field := s.text(start, s.pos)
+
+	s.skipWhitespace()
+	if s.pos < len(s.chars) && s.isSeparator(s.chars[s.pos]) {
+		s.pos++
+		s.skipWhitespace()
+	}
+	return field
+}
+
+// rest returns the rest of the input without the white space at its end
+func (s *fieldSplitter) rest() string {
+	end := len(s.chars)
+	for end > s.pos && s.isWhitespace(s.chars[end-1]) {
+		end--
+	}
+	return s.text(s.pos, end)
+}
+
+func (s *fieldSplitter) text(start, end int) string {
+	var b strings.Builder
+	for _, c := range s.chars[start:end] {
+		b.WriteRune(c.r)
+	}
+	return b.String()
+}
+
+// Help returns the help text
+func (c *ReadCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of read
+func (c *ReadCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "read",
+		Summary: "Read a line into variables.",
+		Description: "Reads a line of the input and splits it into fields at the characters of IFS, a space, tab or newline when it is not set, assigning each field to the next name and the rest of the line to the last one. " +
+			"Without names the whole line is assigned to REPLY. A backslash escapes the character after it, so that it does not separate fields, and continues the line before a newline, unless -r is given." +
+			"\n\n" +
+			"The shell has no arrays: -a assigns the fields to array_0, array_1 and so on, which echo expands as $array_0. " +
+			"When the input is the terminal, the line can be edited like a command line unless -n, -d or -s is given.",
+		Examples: []shell.HelpExample{
+			{Command: "read -p \"Name: \" first last", Description: "Ask for a name and split it into two variables."},
+			{Command: "read -r line < notes.txt", Description: "Read the first line of a file as it is."},
+			{Command: "read -s -p \"Token: \" TOKEN", Description: "Read a secret without echoing it."},
+			{Command: "read -n 1 -t 5 -p \"Continue? \" answer", Description: "Wait up to five seconds for a single key."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "A line was read."},
+			{Code: readEOF, Description: "The input ended before the delimiter, or an error was reported."},
+			{Code: readInterrupted, Description: "Ctrl-C was pressed."},
+			{Code: readTimedOut, Description: "The timeout of -t passed; what was read is assigned."},
+		},
+		SeeAlso: []string{"echo", "printf"},
+	}
+}
@@ -0,0 +1,181 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCommand_Execute(t *testing.T) {
+	cases := []struct {
+		name           string
+		args           []string
+		input          string
+		env            map[string]string
+		expectedVars   map[string]string
+		expectedUnset  []string
+		expectedRest   string
+		expectedError  string
+		expectedStatus int
+	}{
+		{
+			name:         "fields are assigned to names, the last taking the rest",
+			args:         []string{"READ_A", "READ_B"},
+			input:        "  one   two  three  \nnext\n",
+			expectedVars: map[string]string{"READ_A": "one", "READ_B": "two  three"},
+			expectedRest: "next\n",
+		},
+		{
+			name:         "missing fields are empty",
+			args:         []string{"READ_A", "READ_B"},
+			input:        "one\n",
+			expectedVars: map[string]string{"READ_A": "one", "READ_B": ""},
+		},
+		{
+			name:         "REPLY keeps the whole line",
+			input:        "  keep  this  \n",
+			expectedVars: map[string]string{"REPLY": "  keep  this  "},
+		},
+		{
+			name:         "backslashes escape separators and continue lines",
+			args:         []string{"READ_A", "READ_B"},
+			input:        "a\\ b c\\\nd\n",
+			expectedVars: map[string]string{"READ_A": "a b", "READ_B": "cd"},
+		},
+		{
+			name:         "r keeps backslashes",
+			args:         []string{"-r", "READ_A"},
+			input:        "a\\ b\\\n",
+			expectedVars: map[string]string{"READ_A": "a\\ b\\"},
+		},
+		{
+			name:         "other IFS characters delimit empty fields",
+			args:         []string{"READ_A", "READ_B", "READ_C"},
+			input:        "x : :z\n",
+			env:          map[string]string{"IFS": ": "},
+			expectedVars: map[string]string{"READ_A": "x", "READ_B": "", "READ_C": "z"},
+		},
+		{
+			name:         "empty IFS does not split",
+			args:         []string{"READ_A", "READ_B"},
+			input:        " a b \n",
+			env:          map[string]string{"IFS": ""},
+			expectedVars: map[string]string{"READ_A": " a b ", "READ_B": ""},
+		},
+		{
+			name:         "d ends the input at another character",
+			args:         []string{"-d", ":", "READ_A"},
+			input:        "one\ntwo:three",
+			expectedVars: map[string]string{"READ_A": "one\ntwo"},
+			expectedRest: "three",
+		},
+		{
+			name:         "n reads a number of characters",
+			args:         []string{"-n", "3", "READ_A"},
+			input:        "héllo\n",
+			expectedVars: map[string]string{"READ_A": "hél"},
+			expectedRest: "lo\n",
+		},
+		{
+			name:         "n stops at the delimiter",
+			args:         []string{"-n", "10", "READ_A"},
+			input:        "ab\ncd\n",
+			expectedVars: map[string]string{"READ_A": "ab"},
+			expectedRest: "cd\n",
+		},
+		{
+			name:         "n 0 reads nothing",
+			args:         []string{"-n", "0", "READ_A"},
+			input:        "ab\n",
+			expectedVars: map[string]string{"READ_A": ""},
+			expectedRest: "ab\n",
+		},
+		{
+			name:          "a assigns the fields to elements",
+			args:          []string{"-a", "READ_ARR"},
+			input:         " x  y z\n",
+			env:           map[string]string{"READ_ARR_3": "stale"},
+			expectedVars:  map[string]string{"READ_ARR_0": "x", "READ_ARR_1": "y", "READ_ARR_2": "z"},
+			expectedUnset: []string{"READ_ARR_3"},
+		},
+		{
+			name:           "end of input before the delimiter",
+			args:           []string{"READ_A"},
+			input:          "partial",
+			expectedVars:   map[string]string{"READ_A": "partial"},
+			expectedStatus: 1,
+		},
+		{
+			name:           "empty input",
+			args:           []string{"READ_A"},
+			env:            map[string]string{"READ_A": "old"},
+			expectedVars:   map[string]string{"READ_A": ""},
+			expectedStatus: 1,
+		},
+		{
+			name:          "invalid variable name",
+			args:          []string{"1x"},
+			input:         "a\n",
+			expectedError: "read: `1x': not a valid identifier\n",
+			expectedRest:  "a\n",
+		},
+		{
+			name:          "names with a",
+			args:          []string{"-a", "READ_ARR", "READ_A"},
+			expectedError: "read: names cannot be given with -a\n",
+		},
+		{
+			name:          "invalid timeout",
+			args:          []string{"-t", "soon", "READ_A"},
+			expectedError: "read: invalid timeout: soon\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// IFS and the variables are unset unless the case sets them
+			for _, name := range []string{"IFS", "REPLY", "READ_A", "READ_B", "READ_C", "READ_ARR_0", "READ_ARR_1", "READ_ARR_2", "READ_ARR_3"} {
+				t.Setenv(name, "")
+				os.Unsetenv(name)
+			}
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+			input := strings.NewReader(tc.input)
+
+			cmd := commands.NewReadCommand(nil)
+			err := cmd.Execute(context.Background(), tc.args, input, &outputBuffer, &errorBuffer)
+
+			if tc.expectedStatus == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, &shell.ExitStatus{Code: tc.expectedStatus}, err)
+			}
+			assert.Empty(t, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			for name, value := range tc.expectedVars {
+				actual, ok := os.LookupEnv(name)
+				assert.True(t, ok, name)
+				assert.Equal(t, value, actual, name)
+			}
+			for _, name := range tc.expectedUnset {
+				_, ok := os.LookupEnv(name)
+				assert.False(t, ok, name)
+			}
+
+			// nothing after what was read is consumed
+			rest := make([]byte, input.Len())
+			input.Read(rest)
+			assert.Equal(t, tc.expectedRest, string(rest))
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// errIsDirectory reports a directory given to rm without -r or -d
+var errIsDirectory = errors.New("is a directory")
+
+// RmCommand implements the rm command
+type RmCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewRmCommand creates a new rm command
+func NewRmCommand(sessionRepo shell.SessionRepository) *RmCommand {
+	return &RmCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *RmCommand) Name() string {
+	return "rm"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *RmCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *RmCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'r', Description: "Remove directories and their contents."},
+			{Short: 'R', Description: "Same as -r."},
+			{Short: 'f', Description: "Ignore files that do not exist."},
+			{Short: 'd', Description: "Remove empty directories."},
+			{Short: 'v', Description: "Print each file removed."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Variadic: true},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *RmCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	recursive := parsed.Bool("r") || parsed.Bool("R")
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	report := func(path string, dir bool) error {
+		if !parsed.Bool("v") {
+			return nil
+		}
+		kind := ""
+		if dir {
+			kind = "directory "
+		}
+		_, err := fmt.Fprintf(outputWriter, "removed %s'%s'\n", kind, displayPath(session.WorkingDir, path))
+		return err
+	}
+
+	for _, operand := range parsed.Strings("file") {
+		if ctx.Err() != nil {
+			return &shell.ExitStatus{Code: interruptedStatus}
+		}
+
+		if base := filepath.Base(operand); base == "." || base == ".." {
+			if _, err := fmt.Fprintf(errorOutputWriter, "rm: refusing to remove '.' or '..' directory: skipping '%s'\n", operand); err != nil {
+				return err
+			}
+			continue
+		}
+
+		path := resolvePath(session.WorkingDir, operand)
+		if reason := protectedPath(path); reason != "" {
+			if _, err := fmt.Fprintf(errorOutputWriter, "rm: refusing to remove '%s': %s\n", operand, reason); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := removePath(ctx, path, recursive, parsed.Bool("d"), report)
+		switch {
+		case err == nil:
+		case isCancelled(err):
+			return &shell.ExitStatus{Code: interruptedStatus}
+		case errors.Is(err, fs.ErrNotExist) && parsed.Bool("f"):
+		default:
+			if err := writeFileError(errorOutputWriter, "rm", "cannot remove", session.WorkingDir, operand, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// removePath removes one operand of rm. Directories are only removed with
+// recursive, or with emptyDirs when they are empty.
+func removePath(ctx context.Context, path string, recursive, emptyDirs bool, report func(path string, dir bool) error) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.IsDir() && recursive:
+		return removeTree(ctx, path, report)
+	case info.IsDir() && !emptyDirs:
+		return &fs.PathError{Op: "remove", Path: path, Err: errIsDirectory}
+	}
+
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return report(path, info.IsDir())
+}
+
+// protectedPath returns why path must not be removed: it is the root
+// directory, or the home directory or one of its parents. Symlinks are
+// resolved so that a link to them is removed but not what it points to.
+func protectedPath(path string) string {
+	if parent, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		path = filepath.Join(parent, filepath.Base(path))
+	}
+	if path == string(filepath.Separator) || filepath.Dir(path) == path {
+		return "it is the root directory"
+	}
+
+	home, err := homeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(home); err == nil {
+		home = resolved
+	}
+	if isInside(home, filepath.Clean(path)) {
+		return "it contains the home directory"
+	}
+	return ""
+}
+
+// Help returns the help text
+func (c *RmCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of rm
+func (c *RmCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "rm",
+		Summary: "Remove files and directories.",
+		Description: "Removes each file, relative to the working directory. Directories are only removed with -r, which removes their contents too, or with -d when they are empty. A symlink is removed, not what it points to." +
+			"\n\n" +
+			"As a safety guard rm never removes /, the home directory or a directory containing it, nor . and .., whatever the options. Ctrl-C stops rm between files.",
+		Examples: []shell.HelpExample{
+			{Command: "rm -rf build", Description: "Remove a directory tree, succeeding when it does not exist."},
+			{Command: "rm -v notes.txt draft.txt", Description: "Remove files, printing each name."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Every file was removed, or did not exist with -f."},
+			{Code: 1, Description: "A file could not be removed or was refused."},
+			{Code: 130, Description: "Ctrl-C stopped rm before every file was removed."},
+		},
+		SeeAlso: []string{"mkdir", "mv"},
+	}
+}
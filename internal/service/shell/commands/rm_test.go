@@ -0,0 +1,141 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRmCommand_Execute(t *testing.T) {
+	// a file, and a tree with a file and an empty directory
+	setupTree := func(dir string) {
+		os.WriteFile(filepath.Join(dir, "file.txt"), []byte("x"), 0644)
+		os.MkdirAll(filepath.Join(dir, "tree/empty"), 0755)
+		os.WriteFile(filepath.Join(dir, "tree/a.txt"), []byte("a"), 0644)
+	}
+
+	cases := []struct {
+		name           string
+		args           []string
+		home           string
+		cancelled      bool
+		check          func(t *testing.T, dir string)
+		expectedOutput string
+		expectedError  string
+		expectedStatus int
+	}{
+		{
+			name: "success - remove a file",
+			args: []string{"file.txt"},
+			check: func(t *testing.T, dir string) {
+				assert.NoFileExists(t, filepath.Join(dir, "file.txt"))
+			},
+		},
+		{
+			name:           "success - remove a tree verbosely",
+			args:           []string{"-rv", "tree"},
+			expectedOutput: "removed 'tree/a.txt'\nremoved directory 'tree/empty'\nremoved directory 'tree'\n",
+			check: func(t *testing.T, dir string) {
+				assert.NoDirExists(t, filepath.Join(dir, "tree"))
+			},
+		},
+		{
+			name: "success - remove an empty directory",
+			args: []string{"-d", "tree/empty"},
+			check: func(t *testing.T, dir string) {
+				assert.NoDirExists(t, filepath.Join(dir, "tree/empty"))
+			},
+		},
+		{
+			name: "success - force ignores missing files",
+			args: []string{"-f", "missing", "file.txt"},
+			check: func(t *testing.T, dir string) {
+				assert.NoFileExists(t, filepath.Join(dir, "file.txt"))
+			},
+		},
+		{
+			name:          "failure - missing file",
+			args:          []string{"missing"},
+			expectedError: "rm: cannot remove 'missing': no such file or directory\n",
+		},
+		{
+			name:          "failure - directory without -r",
+			args:          []string{"tree"},
+			expectedError: "rm: cannot remove 'tree': is a directory\n",
+			check: func(t *testing.T, dir string) {
+				assert.DirExists(t, filepath.Join(dir, "tree"))
+			},
+		},
+		{
+			name:          "failure - dot directories",
+			args:          []string{"-rf", ".", "tree/.."},
+			expectedError: "rm: refusing to remove '.' or '..' directory: skipping '.'\nrm: refusing to remove '.' or '..' directory: skipping 'tree/..'\n",
+		},
+		{
+			name:          "failure - root directory",
+			args:          []string{"-rf", "/"},
+			expectedError: "rm: refusing to remove '/': it is the root directory\n",
+		},
+		{
+			name:          "failure - home directory and its parent",
+			args:          []string{"-rf", "tree", "tree/empty"},
+			home:          "tree/empty",
+			expectedError: "rm: refusing to remove 'tree': it contains the home directory\nrm: refusing to remove 'tree/empty': it contains the home directory\n",
+			check: func(t *testing.T, dir string) {
+				assert.DirExists(t, filepath.Join(dir, "tree/empty"))
+			},
+		},
+		{
+			name:           "failure - interrupted",
+			args:           []string{"-r", "tree"},
+			cancelled:      true,
+			expectedStatus: 130,
+			check: func(t *testing.T, dir string) {
+				assert.DirExists(t, filepath.Join(dir, "tree"))
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			setupTree(dir)
+			if tc.home != "" {
+				t.Setenv("HOME", filepath.Join(dir, tc.home))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelled {
+				cancel()
+			}
+
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewRmCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			if tc.expectedStatus == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, &shell.ExitStatus{Code: tc.expectedStatus}, err)
+			}
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			if tc.check != nil {
+				tc.check(t, dir)
+			}
+		})
+	}
+}
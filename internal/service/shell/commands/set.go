@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/lineeditor"
+)
+
+// SetCommand implements the set command
+type SetCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewSetCommand creates a new set command
+func NewSetCommand(sessionRepo shell.SessionRepository) *SetCommand {
+	return &SetCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *SetCommand) Name() string {
+	return "set"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *SetCommand) MaxArguments() int {
+	return 2
+}
+
+// Execute runs the command
+func (c *SetCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "session error: %v\n", err)
+		return err
+	}
+
+	mode := session.EditingMode
+	if mode == "" {
+		mode = lineeditor.ModeEmacs
+	}
+
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-o") {
+		for _, option := range []string{lineeditor.ModeEmacs, lineeditor.ModeVi} {
+			state := "off"
+			if option == mode {
+				state = "on"
+			}
+			_, err = fmt.Fprintf(outputWriter, "%-10s%s\n", option, state)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(args) != 2 || (args[0] != "-o" && args[0] != "+o") {
+		_, err = fmt.Fprintf(errorOutputWriter, "usage: set [-o|+o] [emacs|vi]\n")
+		return err
+	}
+
+	option := args[1]
+	if option != lineeditor.ModeEmacs && option != lineeditor.ModeVi {
+		_, err = fmt.Fprintf(errorOutputWriter, "set: unknown option: %s\n", option)
+		return err
+	}
+
+	// turning one editing mode off switches to the other one
+	if args[0] == "+o" {
+		if option == lineeditor.ModeVi {
+			option = lineeditor.ModeEmacs
+		} else {
+			option = lineeditor.ModeVi
+		}
+	}
+
+	session.EditingMode = option
+
+	err = c.sessionRepo.SetSession(session)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "session save error: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// Help returns the help text
+func (c *SetCommand) Help() string {
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of set
+func (c *SetCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "set",
+		Summary:     "Show or switch the line editing mode.",
+		Synopsis:    []string{"set [-o | +o] [emacs | vi]"},
+		Description: "Without arguments set shows the current editing mode. The mode is kept for the session only; it is not saved for logged in users like key bindings are.",
+		Flags: []shell.HelpFlag{
+			{Flag: "-o emacs", Description: "Use emacs style editing keys (default)."},
+			{Flag: "-o vi", Description: "Use vi insert and command modes."},
+			{Flag: "+o vi", Description: "Turn vi mode off, going back to emacs mode."},
+		},
+		Examples: []shell.HelpExample{
+			{Command: "set -o vi", Description: "Switch to vi editing."},
+		},
+		SeeAlso: []string{"bind"},
+	}
+}
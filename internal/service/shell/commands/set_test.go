@@ -0,0 +1,124 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name           string
+		args           []string
+		setupSession   func(repo *repository.SessionRepositoryMock)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name: "success - list editing modes",
+			args: []string{"-o"},
+			setupSession: func(repo *repository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{EditingMode: "vi"}, nil).Once()
+			},
+			expectedOutput: "emacs     off\nvi        on\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - emacs is the default mode",
+			args: []string{},
+			setupSession: func(repo *repository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+			},
+			expectedOutput: "emacs     on\nvi        off\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - switch to vi mode",
+			args: []string{"-o", "vi"},
+			setupSession: func(repo *repository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+				repo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.EditingMode == "vi"
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name: "success - turning vi off switches to emacs",
+			args: []string{"+o", "vi"},
+			setupSession: func(repo *repository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{EditingMode: "vi"}, nil).Once()
+				repo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.EditingMode == "emacs"
+				})).Return(nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name: "failure - unknown option",
+			args: []string{"-o", "noclobber"},
+			setupSession: func(repo *repository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "set: unknown option: noclobber\n",
+		},
+		{
+			name: "failure - invalid usage",
+			args: []string{"-x", "vi"},
+			setupSession: func(repo *repository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "usage: set [-o|+o] [emacs|vi]\n",
+		},
+		{
+			name: "failure - session error",
+			args: []string{"-o", "vi"},
+			setupSession: func(repo *repository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, errors.New("session get error")).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "session error: session get error\n",
+		},
+		{
+			name: "failure - session save error",
+			args: []string{"-o", "vi"},
+			setupSession: func(repo *repository.SessionRepositoryMock) {
+				repo.On("GetSession").Return(shell.Session{}, nil).Once()
+				repo.On("SetSession", mock.Anything).Return(errors.New("session save error")).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "session save error: session save error\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSessionRepo := new(repository.SessionRepositoryMock)
+			tc.setupSession(mockSessionRepo)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewSetCommand(mockSessionRepo)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockSessionRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// SortCommand implements the sort command
+type SortCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewSortCommand creates a new sort command
+func NewSortCommand(sessionRepo shell.SessionRepository) *SortCommand {
+	return &SortCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *SortCommand) Name() string {
+	return "sort"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *SortCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *SortCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'n', Long: "numeric-sort", Description: "Compare by numeric value, a number with an optional sign and decimals at the start of the key."},
+			{Short: 'r', Long: "reverse", Description: "Reverse the order."},
+			{Short: 'u', Long: "unique", Description: "Print only the first of the lines whose keys compare equal."},
+			{Short: 'k', Long: "key", Type: argparse.String, Value: "keydef", Description: "Sort by the key start[,end], where start and end are a field number with an optional .character and n or r flags; end defaults to the end of the line. Repeat for more keys."},
+			{Short: 't', Long: "field-separator", Type: argparse.String, Value: "sep", Description: "Separate fields with the character sep instead of the blanks before each field."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Optional: true, Variadic: true},
+		},
+	}
+}
+
+// sortKey is a part of a line lines are compared by. Fields and characters
+// are numbered from 1; an end field of 0 is the end of the line and an end
+// character of 0 the end of the field.
+type sortKey struct {
+	startField, startChar int
+	endField, endChar     int
+	numeric, reverse      bool
+}
+
+// sortOrder compares lines by their keys
+type sortOrder struct {
+	keys      []sortKey
+	separator string // empty when fields are separated by blanks
+	reverse   bool
+}
+
+// Execute runs the command
+func (c *SortCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	order := sortOrder{separator: parsed.String("t"), reverse: parsed.Bool("r")}
+	if parsed.Has("t") && utf8.RuneCountInString(order.separator) != 1 {
+		_, err = fmt.Fprintf(errorOutputWriter, "sort: the separator must be a single character: '%s'\n", order.separator)
+		return err
+	}
+
+	for _, value := range parsed.Strings("k") {
+		key, err := parseSortKey(value)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "sort: invalid key '%s'\n", value)
+			return err
+		}
+		// a key without flags of its own uses the global ones
+		if !key.numeric && !key.reverse {
+			key.numeric, key.reverse = parsed.Bool("n"), parsed.Bool("r")
+		}
+		order.keys = append(order.keys, key)
+	}
+	if len(order.keys) == 0 {
+		order.keys = []sortKey{{startField: 1, startChar: 1, numeric: parsed.Bool("n"), reverse: parsed.Bool("r")}}
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	// sorting needs every line, which are read before anything is printed
+	var lines []string
+	for _, operand := range operandsOrInput(parsed.Operands) {
+		fileLines, err := readLines(ctx, session.WorkingDir, operand, inputReader)
+		lines = append(lines, fileLines...)
+		if err != nil {
+			if done, err := reportInputError(errorOutputWriter, "sort", operand, err); done {
+				return err
+			}
+		}
+	}
+
+	slices.SortStableFunc(lines, order.compare)
+	if parsed.Bool("u") {
+		lines = slices.CompactFunc(lines, func(a, b string) bool { return order.compareKeys(a, b) == 0 })
+	}
+
+	out := bufio.NewWriter(outputWriter)
+	for _, line := range lines {
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	if err := out.Flush(); err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// readLines returns the lines of an operand without their newlines
+func readLines(ctx context.Context, workingDir, operand string, inputReader io.Reader) ([]string, error) {
+	r, f, err := openOperand(ctx, workingDir, operand, inputReader)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		defer f.Close()
+	}
+
+	var lines []string
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if len(line) > 0 {
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+		}
+
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+// parseSortKey parses the keydef of -k: F[.C][flags][,F[.C][flags]]
+func parseSortKey(value string) (sortKey, error) {
+	var key sortKey
+	start, end, hasEnd := strings.Cut(value, ",")
+
+	var err error
+	if key.startField, key.startChar, err = parseKeyPosition(start, &key); err != nil || key.startField == 0 {
+		return key, fmt.Errorf("invalid key: %s", value)
+	}
+	if key.startChar == 0 {
+		key.startChar = 1
+	}
+
+	if hasEnd {
+		if key.endField, key.endChar, err = parseKeyPosition(end, &key); err != nil || key.endField == 0 {
+			return key, fmt.Errorf("invalid key: %s", value)
+		}
+	}
+	return key, nil
+}
+
+// parseKeyPosition parses F[.C][flags], setting the flags on key
+func parseKeyPosition(value string, key *sortKey) (field, char int, err error) {
+	position := strings.TrimRight(value, "nr")
+	for _, flag := range value[len(position):] {
+		switch flag {
+		case 'n':
+			key.numeric = true
+		case 'r':
+			key.reverse = true
+		}
+	}
+
+	fieldText, charText, hasChar := strings.Cut(position, ".")
+	if field, err = strconv.Atoi(fieldText); err != nil || field < 0 {
+		return 0, 0, fmt.Errorf("invalid field: %s", value)
+	}
+	if hasChar {
+		if char, err = strconv.Atoi(charText); err != nil || char < 0 {
+			return 0, 0, fmt.Errorf("invalid character: %s", value)
+		}
+	}
+	return field, char, nil
+}
+
+// compare orders lines by their keys and, when those are equal, by the
+// whole line so that the output does not depend on the input order
+func (o sortOrder) compare(a, b string) int {
+	if c := o.compareKeys(a, b); c != 0 {
+		return c
+	}
+	if o.reverse {
+		return strings.Compare(b, a)
+	}
+	return strings.Compare(a, b)
+}
+
+// compareKeys orders lines by their keys only
+func (o sortOrder) compareKeys(a, b string) int {
+	for _, key := range o.keys {
+		keyA, keyB := key.extract(a, o.separator), key.extract(b, o.separator)
+
+		var c int
+		if key.numeric {
+			c = compareNumbers(keyA, keyB)
+		} else {
+			c = strings.Compare(keyA, keyB)
+		}
+		if key.reverse {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// extract returns the key of line
+func (k sortKey) extract(line, separator string) string {
+	fields := fieldBounds(line, separator)
+
+	start := len(line)
+	if k.startField <= len(fields) {
+		field := fields[k.startField-1]
+		start = min(field[0]+k.startChar-1, field[1])
+	}
+
+	end := len(line)
+	if k.endField > 0 && k.endField <= len(fields) {
+		field := fields[k.endField-1]
+		end = field[1]
+		if k.endChar > 0 {
+			end = min(field[0]+k.endChar, field[1])
+		}
+	}
+
+	if end <= start {
+		return ""
+	}
+	return line[start:end]
+}
+
+// fieldBounds returns the start and end of each field of line. Without a
+// separator a field is a run of blanks followed by non-blanks, so the blanks
+// before a field belong to it.
+func fieldBounds(line, separator string) [][2]int {
+	var fields [][2]int
+	if separator != "" {
+		start := 0
+		for {
+			i := strings.Index(line[start:], separator)
+			if i < 0 {
+				return append(fields, [2]int{start, len(line)})
+			}
+			fields = append(fields, [2]int{start, start + i})
+			start += i + len(separator)
+		}
+	}
+
+	for start := 0; start < len(line); {
+		end := start
+		for end < len(line) && isBlank(line[end]) {
+			end++
+		}
+		for end < len(line) && !isBlank(line[end]) {
+			end++
+		}
+		fields = append(fields, [2]int{start, end})
+		start = end
+	}
+	return fields
+}
+
+// isBlank reports whether b separates fields
+func isBlank(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// compareNumbers compares the numbers at the start of a and b. Keys that do
+// not start with a number count as zero.
+func compareNumbers(a, b string) int {
+	x, y := leadingNumber(a), leadingNumber(b)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// leadingNumber parses the number at the start of s after blanks
+func leadingNumber(s string) float64 {
+	s = strings.TrimLeft(s, " \t")
+	end := 0
+	if end < len(s) && (s[end] == '-' || s[end] == '+') {
+		end++
+	}
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' && !strings.Contains(s[:end], ".")) {
+		end++
+	}
+
+	n, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Help returns the help text
+func (c *SortCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of sort
+func (c *SortCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "sort",
+		Summary: "Sort lines of text.",
+		Description: "Prints the lines of every file relative to the working directory, or of the input when there is no file or it is -, sorted together. " +
+			"Lines are compared byte by byte, or by number with -n, by the keys of -k in turn and then by the whole line." +
+			"\n\n" +
+			"Unlike the other text commands sort has to read all of its input before printing, so it keeps it in memory.",
+		Examples: []shell.HelpExample{
+			{Command: "sort -u names.txt", Description: "Sort a file, dropping duplicate lines."},
+			{Command: "sort -t , -k 3n data.csv", Description: "Sort CSV rows by the number in the third column."},
+			{Command: "sort -k 2,2 -k 1,1nr scores.txt", Description: "Sort by the second field, then by the first in decreasing numeric order."},
+		},
+		SeeAlso: []string{"uniq", "grep"},
+	}
+}
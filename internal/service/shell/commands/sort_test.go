@@ -0,0 +1,108 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "names.txt"), []byte("carol\nalice\nbob\nalice"), 0644)
+	os.WriteFile(filepath.Join(dir, "scores.csv"), []byte("bob,7,b\nalice,10,a\ncarol,7,c\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "more.txt"), []byte("dave\n"), 0644)
+
+	cases := []struct {
+		name           string
+		args           []string
+		input          string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - input",
+			input:          "b\nc\na\n",
+			expectedOutput: "a\nb\nc\n",
+		},
+		{
+			name:           "success - several files together",
+			args:           []string{"names.txt", "more.txt"},
+			expectedOutput: "alice\nalice\nbob\ncarol\ndave\n",
+		},
+		{
+			name:           "success - unique and reverse",
+			args:           []string{"-ur", "names.txt"},
+			expectedOutput: "carol\nbob\nalice\n",
+		},
+		{
+			name:           "success - numeric",
+			args:           []string{"-n"},
+			input:          "10\n9\n-1.5\nx\n 2\n",
+			expectedOutput: "-1.5\nx\n 2\n9\n10\n",
+		},
+		{
+			name:           "success - numeric key with a separator",
+			args:           []string{"-t", ",", "-k", "2n", "scores.csv"},
+			expectedOutput: "bob,7,b\ncarol,7,c\nalice,10,a\n",
+		},
+		{
+			name:           "success - several keys",
+			args:           []string{"-t", ",", "-k", "2,2nr", "-k", "3,3r", "scores.csv"},
+			expectedOutput: "alice,10,a\ncarol,7,c\nbob,7,b\n",
+		},
+		{
+			name:           "success - blanks before a field count in its characters",
+			args:           []string{"-k", "2.2"},
+			input:          "x  ab\ny ba\nz   ac\n",
+			expectedOutput: "z   ac\nx  ab\ny ba\n",
+		},
+		{
+			name:           "success - unique by key",
+			args:           []string{"-u", "-t", ",", "-k", "2,2", "scores.csv"},
+			expectedOutput: "alice,10,a\nbob,7,b\n",
+		},
+		{
+			name:           "failure - missing file",
+			args:           []string{"missing.txt", "more.txt"},
+			expectedOutput: "dave\n",
+			expectedError:  "sort: missing.txt: no such file or directory\n",
+		},
+		{
+			name:          "failure - invalid key",
+			args:          []string{"-k", "0"},
+			expectedError: "sort: invalid key '0'\n",
+		},
+		{
+			name:          "failure - invalid separator",
+			args:          []string{"-t", "::"},
+			expectedError: "sort: the separator must be a single character: '::'\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewSortCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.input), &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// tailFollowInterval is how often tail -f checks the files for new data
+const tailFollowInterval = 250 * time.Millisecond
+
+// tailBlockSize is the size of the blocks read from the end of a file to
+// find its last lines
+const tailBlockSize = 8192
+
+// TailCommand implements the tail command
+type TailCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewTailCommand creates a new tail command
+func NewTailCommand(sessionRepo shell.SessionRepository) *TailCommand {
+	return &TailCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *TailCommand) Name() string {
+	return "tail"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *TailCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *TailCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'n', Long: "lines", Type: argparse.String, Value: "count", Description: "Print the last count lines instead of 10, or the lines from line count on with +count."},
+			{Short: 'c', Long: "bytes", Type: argparse.String, Value: "count", Description: "Print the last count bytes instead of lines, or the bytes from byte count on with +count."},
+			{Short: 'f', Long: "follow", Description: "Keep printing data appended to the files until interrupted."},
+			{Short: 'q', Long: "quiet", Description: "Never print headers with the file names."},
+			{Short: 'v', Long: "verbose", Description: "Always print headers with the file names."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Optional: true, Variadic: true},
+		},
+	}
+}
+
+// tailRange is the part of its input tail prints
+type tailRange struct {
+	count      int64
+	fromStart  bool // count is the first line or byte printed, not the number of them
+	countBytes bool
+}
+
+// followedFile is a file tail -f keeps reading
+type followedFile struct {
+	operand string
+	f       *os.File
+}
+
+// Execute runs the command
+func (c *TailCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	part := tailRange{count: defaultHeadLines}
+	value, given := parsed.String("n"), parsed.Has("n")
+	if parsed.Has("c") {
+		value, given, part.countBytes = parsed.String("c"), true, true
+	}
+	if given {
+		if part.count, part.fromStart, err = parseTailCount(value); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "tail: invalid number: %s\n", value)
+			return err
+		}
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	operands := operandsOrInput(parsed.Operands)
+	headers := &fileHeaders{show: (len(operands) > 1 || parsed.Bool("v")) && !parsed.Bool("q")}
+
+	var followed []*followedFile
+	defer func() {
+		for _, file := range followed {
+			file.f.Close()
+		}
+	}()
+
+	for _, operand := range operands {
+		f, err := c.tailFile(ctx, session.WorkingDir, operand, part, headers, inputReader, outputWriter)
+		if err != nil {
+			if done, err := reportInputError(errorOutputWriter, "tail", operand, err); done {
+				return err
+			}
+			continue
+		}
+
+		// the input is not followed, it ends when it is closed
+		if f != nil && parsed.Bool("f") {
+			followed = append(followed, &followedFile{operand: operand, f: f})
+		} else if f != nil {
+			f.Close()
+		}
+	}
+
+	if len(followed) == 0 {
+		return nil
+	}
+	return c.follow(ctx, followed, headers, outputWriter, errorOutputWriter)
+}
+
+// tailFile prints the end of one operand after its header. The file of the
+// operand is returned open, positioned at its end, to be followed.
+func (c *TailCommand) tailFile(ctx context.Context, workingDir, operand string, part tailRange, headers *fileHeaders, inputReader io.Reader, outputWriter io.Writer) (*os.File, error) {
+	r, f, err := openOperand(ctx, workingDir, operand, inputReader)
+	if err != nil {
+		return nil, err
+	}
+
+	err = headers.write(outputWriter, operand)
+	if err == nil {
+		err = part.print(r, f, outputWriter)
+	}
+	if err != nil {
+		if f != nil {
+			f.Close()
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// print prints the part of r wanted. When r reads a regular file f its end
+// is found by reading backwards instead of reading the whole file.
+func (part tailRange) print(r io.Reader, f *os.File, w io.Writer) error {
+	if part.fromStart {
+		return part.printFrom(bufio.NewReader(r), w)
+	}
+
+	if f != nil {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			offset := max(info.Size()-part.count, 0)
+			if !part.countBytes {
+				if offset, err = lastLinesOffset(f, info.Size(), part.count); err != nil {
+					return &inputError{err: err}
+				}
+			}
+			if _, err := f.Seek(offset, io.SeekStart); err != nil {
+				return &inputError{err: err}
+			}
+			_, err := io.Copy(w, r)
+			return err
+		}
+	}
+
+	if part.countBytes {
+		return printLastBytes(r, w, part.count)
+	}
+	return printLastLines(bufio.NewReader(r), w, part.count)
+}
+
+// printFrom prints r from the line or byte numbered part.count on
+func (part tailRange) printFrom(r *bufio.Reader, w io.Writer) error {
+	skip := max(part.count-1, 0)
+	if part.countBytes {
+		_, err := io.CopyN(io.Discard, r, skip)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for ; skip > 0 && !part.countBytes; skip-- {
+		_, err := r.ReadSlice('\n')
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = r.ReadSlice('\n')
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := io.Copy(w, r)
+	return err
+}
+
+// lastLinesOffset returns where the last count lines of the file f of the
+// given size start, reading it backwards a block at a time
+func lastLinesOffset(f *os.File, size, count int64) (int64, error) {
+	if count == 0 {
+		return size, nil
+	}
+
+	buf := make([]byte, tailBlockSize)
+	for pos := size; pos > 0; {
+		n := min(int64(len(buf)), pos)
+		pos -= n
+		if _, err := f.ReadAt(buf[:n], pos); err != nil {
+			return 0, err
+		}
+
+		for i := n - 1; i >= 0; i-- {
+			// the newline ending the last line does not start another one
+			if buf[i] != '\n' || pos+i == size-1 {
+				continue
+			}
+			if count--; count == 0 {
+				return pos + i + 1, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+// printLastLines prints the last count lines of a stream, keeping only those
+// lines while reading it
+func printLastLines(r *bufio.Reader, w io.Writer, count int64) error {
+	var ring [][]byte
+	next := 0
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 && count > 0 {
+			if int64(len(ring)) < count {
+				ring = append(ring, line)
+			} else {
+				ring[next] = line
+				next = (next + 1) % len(ring)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, line := range append(ring[next:], ring[:next]...) {
+		bw.Write(line)
+	}
+	return bw.Flush()
+}
+
+// printLastBytes prints the last count bytes of a stream
+func printLastBytes(r io.Reader, w io.Writer, count int64) error {
+	var last []byte
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		last = append(last, buf[:n]...)
+		if int64(len(last)) > count {
+			last = last[int64(len(last))-count:]
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(last)
+	return err
+}
+
+// follow prints what is appended to the files until ctx is cancelled. The
+// header of a file is printed again when the output switches to it, and a
+// file that shrinks is read again from its start.
+func (c *TailCommand) follow(ctx context.Context, files []*followedFile, headers *fileHeaders, outputWriter, errorOutputWriter io.Writer) error {
+	ticker := time.NewTicker(tailFollowInterval)
+	defer ticker.Stop()
+
+	current := files[len(files)-1]
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		for _, file := range files {
+			info, err := file.f.Stat()
+			if err != nil {
+				continue
+			}
+			offset, err := file.f.Seek(0, io.SeekCurrent)
+			if err != nil || info.Size() == offset {
+				continue
+			}
+
+			if info.Size() < offset {
+				if _, err := fmt.Fprintf(errorOutputWriter, "tail: %s: file truncated\n", file.operand); err != nil {
+					return err
+				}
+				if _, err := file.f.Seek(0, io.SeekStart); err != nil {
+					continue
+				}
+			}
+
+			if file != current {
+				if err := headers.write(outputWriter, file.operand); err != nil {
+					return err
+				}
+				current = file
+			}
+
+			_, err = io.Copy(outputWriter, &textReader{contextReader{ctx: ctx, r: file.f}})
+			if err != nil {
+				if done, err := reportInputError(errorOutputWriter, "tail", file.operand, err); done {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// parseTailCount parses the count of -n and -c: the number of lines or bytes
+// at the end, or with a leading + the first one printed
+func parseTailCount(value string) (int64, bool, error) {
+	fromStart := strings.HasPrefix(value, "+")
+	count, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimPrefix(value, "+"), "-"), 10, 64)
+	if err != nil || count < 0 {
+		return 0, false, fmt.Errorf("invalid number: %s", value)
+	}
+	return count, fromStart, nil
+}
+
+// Help returns the help text
+func (c *TailCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of tail
+func (c *TailCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "tail",
+		Summary: "Print the last lines of files.",
+		Description: "Prints the last 10 lines, or count lines or bytes, of each file relative to the working directory, or of the input when there is no file or it is -. With several files each starts with a ==> file <== header. " +
+			"The end of a file is read directly, so tail is fast on large files; the input is read through, keeping only the lines printed." +
+			"\n\n" +
+			"With -f tail keeps running after printing and prints what is appended to the files, checking for new data four times a second, until Ctrl-C. A file that is truncated is printed again from its start.",
+		Examples: []shell.HelpExample{
+			{Command: "tail -n 20 app.log", Description: "Print the last 20 lines."},
+			{Command: "tail -n +2 data.csv", Description: "Print everything but the header line."},
+			{Command: "tail -f app.log", Description: "Watch a log file as it grows."},
+		},
+		SeeAlso: []string{"head", "cat"},
+	}
+}
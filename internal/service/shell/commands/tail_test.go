@@ -0,0 +1,177 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "twenty.txt"), []byte(numberedLines(20)), 0644)
+	os.WriteFile(filepath.Join(dir, "short.txt"), []byte("a\nb"), 0644)
+	// more lines than one block read from the end holds
+	os.WriteFile(filepath.Join(dir, "big.txt"), []byte(numberedLines(5000)), 0644)
+
+	cases := []struct {
+		name           string
+		args           []string
+		input          string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - last ten lines of the input",
+			input:          numberedLines(12),
+			expectedOutput: strings.TrimPrefix(numberedLines(12), "1\n2\n"),
+		},
+		{
+			name:           "success - line count of a file",
+			args:           []string{"-n", "3", "twenty.txt"},
+			expectedOutput: "18\n19\n20\n",
+		},
+		{
+			name:           "success - many lines of a large file",
+			args:           []string{"-n", "4998", "big.txt"},
+			expectedOutput: strings.TrimPrefix(numberedLines(5000), "1\n2\n"),
+		},
+		{
+			name:           "success - last line without newline",
+			args:           []string{"-n", "1", "short.txt"},
+			expectedOutput: "b",
+		},
+		{
+			name:           "success - more lines than the file has",
+			args:           []string{"-n", "5", "short.txt"},
+			expectedOutput: "a\nb",
+		},
+		{
+			name:           "success - byte count",
+			args:           []string{"-c", "4", "twenty.txt"},
+			expectedOutput: "\n20\n",
+		},
+		{
+			name:           "success - byte count of the input",
+			args:           []string{"-c", "3"},
+			input:          "abcdef",
+			expectedOutput: "def",
+		},
+		{
+			name:           "success - from a line on",
+			args:           []string{"-n", "+19", "twenty.txt"},
+			expectedOutput: "19\n20\n",
+		},
+		{
+			name:           "success - from a line of the input on",
+			args:           []string{"-n", "+3"},
+			input:          "a\nb\nc\nd\n",
+			expectedOutput: "c\nd\n",
+		},
+		{
+			name:           "success - from a byte on",
+			args:           []string{"-c", "+3", "short.txt"},
+			expectedOutput: "b",
+		},
+		{
+			name:           "success - headers with several files",
+			args:           []string{"-n", "1", "twenty.txt", "short.txt"},
+			expectedOutput: "==> twenty.txt <==\n20\n\n==> short.txt <==\nb",
+		},
+		{
+			name:          "failure - missing file",
+			args:          []string{"missing.txt"},
+			expectedError: "tail: missing.txt: no such file or directory\n",
+		},
+		{
+			name:          "failure - invalid count",
+			args:          []string{"-n", "x", "twenty.txt"},
+			expectedError: "tail: invalid number: x\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewTailCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.input), &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+		})
+	}
+}
+
+// syncBuffer is a bytes.Buffer that can be written by a running command
+// while the test reads it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestTailCommand_Follow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	os.WriteFile(path, []byte("one\ntwo\n"), 0644)
+
+	mockRepo := new(repository.SessionRepositoryMock)
+	mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var outputBuffer, errorBuffer syncBuffer
+	done := make(chan error)
+	go func() {
+		cmd := commands.NewTailCommand(mockRepo)
+		done <- cmd.Execute(ctx, []string{"-f", "-n", "1", "app.log"}, nil, &outputBuffer, &errorBuffer)
+	}()
+
+	assert.Eventually(t, func() bool { return outputBuffer.String() == "two\n" }, 2*time.Second, 10*time.Millisecond)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	f.WriteString("three\n")
+	f.Close()
+	assert.Eventually(t, func() bool { return outputBuffer.String() == "two\nthree\n" }, 2*time.Second, 10*time.Millisecond)
+
+	// a truncated file is printed again from its start
+	os.WriteFile(path, []byte("new\n"), 0644)
+	assert.Eventually(t, func() bool { return outputBuffer.String() == "two\nthree\nnew\n" }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "tail: app.log: file truncated\n", errorBuffer.String())
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("tail -f did not stop when interrupted")
+	}
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// TeeCommand implements the tee command
+type TeeCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewTeeCommand creates a new tee command
+func NewTeeCommand(sessionRepo shell.SessionRepository) *TeeCommand {
+	return &TeeCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *TeeCommand) Name() string {
+	return "tee"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *TeeCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *TeeCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'a', Long: "append", Description: "Append to the files instead of overwriting them."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Optional: true, Variadic: true},
+		},
+	}
+}
+
+// teeFile is a file tee writes to
+type teeFile struct {
+	operand string
+	f       *os.File
+}
+
+// Execute runs the command
+func (c *TeeCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if parsed.Bool("a") {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	var files []*teeFile
+	defer func() {
+		for _, file := range files {
+			file.f.Close()
+		}
+	}()
+	for _, operand := range parsed.Operands {
+		f, err := os.OpenFile(resolvePath(session.WorkingDir, operand), flags, 0666)
+		if err != nil {
+			if _, err := fmt.Fprintf(errorOutputWriter, "tee: %s: %v\n", operand, pathError(err)); err != nil {
+				return err
+			}
+			continue
+		}
+		files = append(files, &teeFile{operand: operand, f: f})
+	}
+
+	// opening the input cannot fail
+	r, _, _ := openOperand(ctx, session.WorkingDir, stdinOperand, inputReader)
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			if _, err := outputWriter.Write(buf[:n]); err != nil {
+				_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+				return err
+			}
+
+			// a file that cannot be written is reported once and left out
+			for i := 0; i < len(files); i++ {
+				if _, err := files[i].f.Write(buf[:n]); err != nil {
+					if _, err := fmt.Fprintf(errorOutputWriter, "tee: %s: %v\n", files[i].operand, pathError(err)); err != nil {
+						return err
+					}
+					files[i].f.Close()
+					files = append(files[:i], files[i+1:]...)
+					i--
+				}
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			_, err := reportInputError(errorOutputWriter, "tee", operandName(stdinOperand), readErr)
+			return err
+		}
+	}
+
+	for _, file := range files {
+		if err := file.f.Close(); err != nil {
+			if _, err := fmt.Fprintf(errorOutputWriter, "tee: %s: %v\n", file.operand, pathError(err)); err != nil {
+				return err
+			}
+		}
+	}
+	files = nil
+
+	return nil
+}
+
+// Help returns the help text
+func (c *TeeCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of tee
+func (c *TeeCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "tee",
+		Summary: "Copy the input to the output and to files.",
+		Description: "Copies the input to the output and to each file, relative to the working directory, as it is read. Files are created or overwritten, or appended to with -a. " +
+			"A file that cannot be opened or written is reported and the copy goes on to the output and the other files.",
+		Examples: []shell.HelpExample{
+			{Command: "tee -a session.log < notes.txt", Description: "Print a file while appending it to a log."},
+		},
+		SeeAlso: []string{"cat"},
+	}
+}
@@ -0,0 +1,93 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeeCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name           string
+		args           []string
+		input          string
+		check          func(t *testing.T, dir string)
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - output only",
+			input:          "hello\n",
+			expectedOutput: "hello\n",
+		},
+		{
+			name:           "success - overwrite files",
+			args:           []string{"old.txt", "new.txt"},
+			input:          "hello\n",
+			expectedOutput: "hello\n",
+			check: func(t *testing.T, dir string) {
+				for _, name := range []string{"old.txt", "new.txt"} {
+					content, err := os.ReadFile(filepath.Join(dir, name))
+					assert.NoError(t, err)
+					assert.Equal(t, "hello\n", string(content))
+				}
+			},
+		},
+		{
+			name:           "success - append",
+			args:           []string{"-a", "old.txt"},
+			input:          "hello\n",
+			expectedOutput: "hello\n",
+			check: func(t *testing.T, dir string) {
+				content, err := os.ReadFile(filepath.Join(dir, "old.txt"))
+				assert.NoError(t, err)
+				assert.Equal(t, "old\nhello\n", string(content))
+			},
+		},
+		{
+			name:           "failure - file in a missing directory",
+			args:           []string{"missing/out.txt", "new.txt"},
+			input:          "hello\n",
+			expectedOutput: "hello\n",
+			expectedError:  "tee: missing/out.txt: no such file or directory\n",
+			check: func(t *testing.T, dir string) {
+				content, err := os.ReadFile(filepath.Join(dir, "new.txt"))
+				assert.NoError(t, err)
+				assert.Equal(t, "hello\n", string(content))
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.WriteFile(filepath.Join(dir, "old.txt"), []byte("old\n"), 0644)
+
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewTeeCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.input), &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			if tc.check != nil {
+				tc.check(t, dir)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// The text builtins head, tail, wc, grep, sort, uniq and tee read each file
+// operand, or the input when there is none or it is -, a piece at a time so
+// that inputs of any size use little memory; only sort keeps its input. An
+// unreadable file is reported and the other operands are still processed.
+
+// stdinOperand is the operand that names the input
+const stdinOperand = "-"
+
+// inputError wraps failures to open or read an input, as opposed to
+// failures to write the output
+type inputError struct {
+	err error
+}
+
+func (e *inputError) Error() string { return e.err.Error() }
+func (e *inputError) Unwrap() error { return e.err }
+
+// openOperand opens a file operand relative to workingDir, or returns the
+// input for -, as a reader that stops once ctx is cancelled. The file is
+// returned too, for seeking; the caller closes it when it is not nil.
+func openOperand(ctx context.Context, workingDir, operand string, inputReader io.Reader) (io.Reader, *os.File, error) {
+	if operand == stdinOperand {
+		if inputReader == nil {
+			inputReader = strings.NewReader("")
+		}
+		return &textReader{contextReader{ctx: ctx, r: inputReader}}, nil, nil
+	}
+
+	f, err := os.Open(resolvePath(workingDir, operand))
+	if err != nil {
+		return nil, nil, &inputError{err: err}
+	}
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		f.Close()
+		return nil, nil, &inputError{err: errors.New("is a directory")}
+	}
+	return &textReader{contextReader{ctx: ctx, r: f}}, f, nil
+}
+
+// operandName returns how an operand is named in headers
+func operandName(operand string) string {
+	if operand == stdinOperand {
+		return "standard input"
+	}
+	return operand
+}
+
+// operandsOrInput returns operands, or - to read the input when there are none
+func operandsOrInput(operands []string) []string {
+	if len(operands) == 0 {
+		return []string{stdinOperand}
+	}
+	return operands
+}
+
+// reportInputError reports err, returned while processing operand. Input
+// errors are written as "cmd: file: reason" and the command goes on with the
+// next operand; done is set when it must stop instead because it was
+// interrupted or the output failed.
+func reportInputError(w io.Writer, cmd, operand string, err error) (done bool, _ error) {
+	var inErr *inputError
+	switch {
+	case isCancelled(err):
+		return true, nil
+	case errors.As(err, &inErr):
+		_, err = fmt.Fprintf(w, "%s: %s: %v\n", cmd, operand, pathError(inErr.err))
+		return err != nil, err
+	default:
+		_, err = fmt.Fprintf(w, "error writing output: %v\n", err)
+		return true, err
+	}
+}
+
+// textReader reads an operand, stopping once the command is interrupted and
+// marking read errors
+type textReader struct {
+	contextReader
+}
+
+func (r *textReader) Read(p []byte) (int, error) {
+	n, err := r.contextReader.Read(p)
+	if err != nil && err != io.EOF && !isCancelled(err) {
+		err = &inputError{err: err}
+	}
+	return n, err
+}
+
+// flushIfIdle flushes w once everything read so far has been processed, so
+// that output follows input typed or piped slowly without a write per line
+func flushIfIdle(w *bufio.Writer, r *bufio.Reader) error {
+	if r.Buffered() > 0 {
+		return nil
+	}
+	return w.Flush()
+}
+
+// writeLine writes line, adding the newline a last line may be missing
+func writeLine(w *bufio.Writer, line []byte) {
+	w.Write(line)
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		w.WriteByte('\n')
+	}
+}
+
+// fileHeaders writes the "==> file <==" line put before each operand by head
+// and tail when there are several, with a blank line before all but the first
+type fileHeaders struct {
+	show    bool
+	written bool
+}
+
+// write writes the header of operand when headers are shown
+func (h *fileHeaders) write(w io.Writer, operand string) error {
+	if !h.show {
+		return nil
+	}
+
+	separator := "\n"
+	if !h.written {
+		separator = ""
+	}
+	h.written = true
+	_, err := fmt.Fprintf(w, "%s==> %s <==\n", separator, operandName(operand))
+	return err
+}
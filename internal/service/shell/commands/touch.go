@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// touchDateLayouts are the formats accepted by touch -d, in local time
+// unless the date has a zone
+var touchDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// TouchCommand implements the touch command
+type TouchCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewTouchCommand creates a new touch command
+func NewTouchCommand(sessionRepo shell.SessionRepository) *TouchCommand {
+	return &TouchCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *TouchCommand) Name() string {
+	return "touch"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *TouchCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *TouchCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'a', Description: "Change only the access time."},
+			{Short: 'm', Description: "Change only the modification time."},
+			{Short: 'c', Description: "Do not create files that do not exist."},
+			{Short: 'd', Type: argparse.String, Value: "date", Description: "Use date instead of the current time, as 2006-01-02, 2006-01-02 15:04:05 or RFC 3339."},
+			{Short: 'v', Description: "Print each file created or changed."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Variadic: true},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *TouchCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	now := time.Now()
+	if parsed.Has("d") {
+		if now, err = parseTouchDate(parsed.String("d")); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "touch: invalid date format '%s'\n", parsed.String("d"))
+			return err
+		}
+	}
+
+	// a zero time leaves that time of the file unchanged
+	atime, mtime := now, now
+	switch {
+	case parsed.Bool("a") && !parsed.Bool("m"):
+		mtime = time.Time{}
+	case parsed.Bool("m") && !parsed.Bool("a"):
+		atime = time.Time{}
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	for _, operand := range parsed.Strings("file") {
+		if ctx.Err() != nil {
+			return &shell.ExitStatus{Code: interruptedStatus}
+		}
+
+		created, err := touchFile(resolvePath(session.WorkingDir, operand), atime, mtime, !parsed.Bool("c"))
+		if err != nil {
+			if err := writeFileError(errorOutputWriter, "touch", "cannot touch", session.WorkingDir, operand, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if parsed.Bool("v") {
+			action := "touched"
+			if created {
+				action = "created"
+			}
+			if _, err := fmt.Fprintf(outputWriter, "%s '%s'\n", action, operand); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// touchFile sets the times of path, creating it first when it does not
+// exist and create is set. It reports whether the file was created; a
+// missing file that is not created is not an error.
+func touchFile(path string, atime, mtime time.Time, create bool) (bool, error) {
+	err := os.Chtimes(path, atime, mtime)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	if !create {
+		return false, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0666)
+	if err != nil {
+		return false, err
+	}
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+	return true, os.Chtimes(path, atime, mtime)
+}
+
+// parseTouchDate parses the date of touch -d
+func parseTouchDate(value string) (time.Time, error) {
+	for _, layout := range touchDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %s", value)
+}
+
+// Help returns the help text
+func (c *TouchCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of touch
+func (c *TouchCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "touch",
+		Summary:     "Change file times or create empty files.",
+		Description: "Sets the access and modification times of each file, relative to the working directory, to now or to the date of -d. Files that do not exist are created empty unless -c is given.",
+		Examples: []shell.HelpExample{
+			{Command: "touch notes.txt", Description: "Create an empty file, or mark an existing one as modified now."},
+			{Command: "touch -m -d 2024-01-31 report.pdf", Description: "Set only the modification time."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Every file was touched."},
+			{Code: 1, Description: "A file could not be created or changed."},
+			{Code: 130, Description: "Ctrl-C stopped touch before every file was touched."},
+		},
+		SeeAlso: []string{"ls", "mkdir"},
+	}
+}
@@ -0,0 +1,120 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTouchCommand_Execute(t *testing.T) {
+	oldTime := time.Date(2020, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	modTime := func(t *testing.T, path string) time.Time {
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+		return info.ModTime()
+	}
+
+	cases := []struct {
+		name           string
+		args           []string
+		cancelled      bool
+		check          func(t *testing.T, dir string)
+		expectedOutput string
+		expectedError  string
+		expectedStatus int
+	}{
+		{
+			name:           "success - create and update verbosely",
+			args:           []string{"-v", "new.txt", "old.txt"},
+			expectedOutput: "created 'new.txt'\ntouched 'old.txt'\n",
+			check: func(t *testing.T, dir string) {
+				assert.FileExists(t, filepath.Join(dir, "new.txt"))
+				assert.True(t, modTime(t, filepath.Join(dir, "old.txt")).After(oldTime))
+			},
+		},
+		{
+			name: "success - no create",
+			args: []string{"-c", "new.txt"},
+			check: func(t *testing.T, dir string) {
+				assert.NoFileExists(t, filepath.Join(dir, "new.txt"))
+			},
+		},
+		{
+			name: "success - date",
+			args: []string{"-d", "2024-01-31 12:30:00", "old.txt"},
+			check: func(t *testing.T, dir string) {
+				expected := time.Date(2024, 1, 31, 12, 30, 0, 0, time.Local)
+				assert.True(t, expected.Equal(modTime(t, filepath.Join(dir, "old.txt"))))
+			},
+		},
+		{
+			name: "success - access time only",
+			args: []string{"-a", "old.txt"},
+			check: func(t *testing.T, dir string) {
+				assert.True(t, oldTime.Equal(modTime(t, filepath.Join(dir, "old.txt"))))
+			},
+		},
+		{
+			name:          "failure - missing directory",
+			args:          []string{"missing/new.txt"},
+			expectedError: "touch: cannot touch 'missing/new.txt': no such file or directory\n",
+		},
+		{
+			name:          "failure - invalid date",
+			args:          []string{"-d", "yesterday", "old.txt"},
+			expectedError: "touch: invalid date format 'yesterday'\n",
+		},
+		{
+			name:           "failure - interrupted",
+			args:           []string{"new.txt"},
+			cancelled:      true,
+			expectedStatus: 130,
+			check: func(t *testing.T, dir string) {
+				assert.NoFileExists(t, filepath.Join(dir, "new.txt"))
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.WriteFile(filepath.Join(dir, "old.txt"), nil, 0644)
+			os.Chtimes(filepath.Join(dir, "old.txt"), oldTime, oldTime)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelled {
+				cancel()
+			}
+
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewTouchCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			if tc.expectedStatus == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, &shell.ExitStatus{Code: tc.expectedStatus}, err)
+			}
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			if tc.check != nil {
+				tc.check(t, dir)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 	"github.com/Ali-Farhadnia/goshell/pkg/execpath"
 )
 
@@ -29,40 +30,128 @@ func (t *TypeCommand) Name() string {
 }
 
 func (t *TypeCommand) MaxArguments() int {
-	return 1
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (t *TypeCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'a', Description: "Show every builtin and executable called name, not only the one that runs."},
+			{Short: 'p', Description: "Print only the path of the executable that runs, nothing for a builtin."},
+			{Short: 't', Description: "Print only the kind of each match, builtin or file."},
+			{Short: 'P', Description: "Search PATH even when name is a builtin, printing the path like -p."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "name", Variadic: true},
+		},
+	}
 }
 
 // Execute runs the command
 func (t *TypeCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
-	if len(args) == 0 {
-		_, err := fmt.Fprintf(errorOutputWriter, "usage: type <command>\n")
-		return err
+	parsed, err := shell.ParseArguments(ctx, t.Name(), t.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
 	}
+	forcePath := parsed.Bool("P")
+	pathOnly := parsed.Bool("p") || forcePath
 
-	cmdName := args[0]
+	for _, name := range parsed.Strings("name") {
+		matches := lookupCommand(t.cmdRepo, name, shell.SearchPath(t.path), !forcePath, parsed.Bool("a"))
+		if len(matches) == 0 {
+			if _, err := fmt.Fprintf(errorOutputWriter, "type: %s: not found\n", name); err != nil {
+				return err
+			}
+			continue
+		}
 
-	// Check if it's a shell builtin
-	if _, err := t.cmdRepo.Get(cmdName); err == nil {
-		_, err = fmt.Fprintf(outputWriter, "%s is a shell builtin\n", cmdName)
-		return err
-	}
+		for _, match := range matches {
+			var line string
+			switch {
+			case parsed.Bool("t"):
+				line = match.kind
+			case pathOnly && match.kind == kindBuiltin:
+				continue
+			case pathOnly:
+				line = match.path
+			default:
+				line = match.describe(name)
+			}
 
-	// Check if it's an executable in $PATH
-	cmdPath, err := execpath.FindExecutable(cmdName, t.path)
-	if err != nil {
-		_, err = fmt.Fprintf(errorOutputWriter, "%v\n", err)
-		return err
-	}
-
-	_, err = fmt.Fprintf(outputWriter, "%s is %s\n", cmdName, cmdPath)
-	if err != nil {
-		_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
-		return err
+			if _, err := fmt.Fprintln(outputWriter, line); err != nil {
+				_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// Help returns the help text
 func (t *TypeCommand) Help() string {
-	return "type <command> - Identifies if the command is a shell builtin or an external executable"
+	return shell.HelpLine(shell.CommandDoc(t))
+}
+
+// Doc returns the documentation of type
+func (t *TypeCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "type",
+		Summary: "Describe how a command name is interpreted.",
+		Description: "Tells whether each name is a shell builtin or an executable found in $PATH, and where. Builtins are found first, like when a command runs. goshell has no aliases or shell functions, so these are the only kinds." +
+			"\n\n" +
+			"A name that is not found is reported on the error output.",
+		Examples: []shell.HelpExample{
+			{Command: "type ls", Description: "Check whether ls is the builtin."},
+			{Command: "type -a ls", Description: "Show the builtin and every ls executable in PATH."},
+			{Command: "type -t cd", Description: "Print builtin, for use in scripts."},
+		},
+		SeeAlso: []string{"which", "command", "builtin", "help"},
+	}
+}
+
+// Kinds of commands reported by type -t
+const (
+	kindBuiltin = "builtin"
+	kindFile    = "file"
+)
+
+// commandMatch is a way a command name can run
+type commandMatch struct {
+	kind string
+	path string // the executable of a file
+}
+
+// describe returns the match as printed by type
+func (m commandMatch) describe(name string) string {
+	if m.kind == kindBuiltin {
+		return name + " is a shell builtin"
+	}
+	return name + " is " + m.path
+}
+
+// lookupCommand returns the ways name can run in the order they are tried:
+// the builtin, when builtins is set, then the executables in path. Only the
+// first match is returned unless all is set.
+func lookupCommand(cmdRepo shell.CommandRepository, name, path string, builtins, all bool) []commandMatch {
+	var matches []commandMatch
+	if builtins {
+		if _, err := cmdRepo.Get(name); err == nil {
+			matches = append(matches, commandMatch{kind: kindBuiltin})
+			if !all {
+				return matches
+			}
+		}
+	}
+
+	if all {
+		for _, exePath := range execpath.FindAllExecutables(name, path) {
+			matches = append(matches, commandMatch{kind: kindFile, path: exePath})
+		}
+	} else if exePath, err := execpath.FindExecutable(name, path); err == nil {
+		matches = append(matches, commandMatch{kind: kindFile, path: exePath})
+	}
+
+	return matches
 }
@@ -28,6 +28,9 @@ func TestTypeCommand_Execute(t *testing.T) {
 	// Add the directory containing the executable to the PATH environment variable
 	path := os.Getenv("PATH")
 	path = filepath.Dir(tempExec.Name()) + string(os.PathListSeparator) + path
+	execName := filepath.Base(tempExec.Name())
+	// type follows PATH when it changes
+	t.Setenv("PATH", path)
 
 	cases := []struct {
 		name           string
@@ -61,14 +64,50 @@ func TestTypeCommand_Execute(t *testing.T) {
 				repo.On("Get", "nonexistent").Return(&MockCommand{}, errors.New("not found")).Once()
 			},
 			expectedOutput: "",
-			expectedError:  "command not found: nonexistent\n",
+			expectedError:  "type: nonexistent: not found\n",
 		},
 		{
 			name:           "failure - missing argument",
 			args:           []string{},
 			setupRepo:      func(repo *repository.CommandRepositoryMock) {},
 			expectedOutput: "",
-			expectedError:  "usage: type <command>\n",
+			expectedError:  "type: missing name\nusage: type [-aptP] <name> ...\n",
+		},
+		{
+			name: "success - all matches",
+			args: []string{"-a", execName},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", execName).Return(&MockCommand{}, nil).Once()
+			},
+			expectedOutput: execName + " is a shell builtin\n" + execName + " is " + tempExec.Name() + "\n",
+			expectedError:  "",
+		},
+		{
+			name: "success - kinds of several names",
+			args: []string{"-t", "builtin", execName, "nonexistent"},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", "builtin").Return(&MockCommand{}, nil).Once()
+				repo.On("Get", execName).Return(&MockCommand{}, errors.New("not found")).Once()
+				repo.On("Get", "nonexistent").Return(&MockCommand{}, errors.New("not found")).Once()
+			},
+			expectedOutput: "builtin\nfile\n",
+			expectedError:  "type: nonexistent: not found\n",
+		},
+		{
+			name: "success - path of a builtin is empty",
+			args: []string{"-p", execName},
+			setupRepo: func(repo *repository.CommandRepositoryMock) {
+				repo.On("Get", execName).Return(&MockCommand{}, nil).Once()
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name:           "success - path search ignores builtins",
+			args:           []string{"-P", execName},
+			setupRepo:      func(repo *repository.CommandRepositoryMock) {},
+			expectedOutput: tempExec.Name() + "\n",
+			expectedError:  "",
 		},
 	}
 
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// UniqCommand implements the uniq command
+type UniqCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewUniqCommand creates a new uniq command
+func NewUniqCommand(sessionRepo shell.SessionRepository) *UniqCommand {
+	return &UniqCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *UniqCommand) Name() string {
+	return "uniq"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *UniqCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *UniqCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'c', Long: "count", Description: "Prefix each line with the number of times it was repeated."},
+			{Short: 'd', Long: "repeated", Description: "Print only lines that are repeated."},
+			{Short: 'u', Long: "unique", Description: "Print only lines that are not repeated."},
+			{Short: 'i', Long: "ignore-case", Description: "Ignore case when comparing lines."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Optional: true, Description: "The file to read instead of the input."},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *UniqCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	operand := operandsOrInput(parsed.Operands)[0]
+	err = c.uniq(ctx, session.WorkingDir, operand, parsed, inputReader, outputWriter)
+	if err != nil {
+		_, err = reportInputError(errorOutputWriter, "uniq", operand, err)
+		return err
+	}
+
+	return nil
+}
+
+// uniq prints the lines of an operand, writing each run of equal lines once
+// as soon as the next line differs
+func (c *UniqCommand) uniq(ctx context.Context, workingDir, operand string, parsed *argparse.Args, inputReader io.Reader, outputWriter io.Writer) error {
+	r, f, err := openOperand(ctx, workingDir, operand, inputReader)
+	if err != nil {
+		return err
+	}
+	if f != nil {
+		defer f.Close()
+	}
+
+	equal := bytes.Equal
+	if parsed.Bool("i") {
+		equal = bytes.EqualFold
+	}
+
+	br := bufio.NewReader(r)
+	out := bufio.NewWriter(outputWriter)
+
+	var previous []byte
+	repeats := 0
+	flush := func() {
+		if repeats == 0 || (parsed.Bool("d") && repeats == 1) || (parsed.Bool("u") && repeats > 1) {
+			return
+		}
+		if parsed.Bool("c") {
+			fmt.Fprintf(out, "%7d ", repeats)
+		}
+		out.Write(previous)
+		out.WriteByte('\n')
+	}
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			line = bytes.TrimSuffix(line, []byte("\n"))
+			if repeats > 0 && equal(line, previous) {
+				repeats++
+			} else {
+				flush()
+				previous, repeats = line, 1
+			}
+		}
+
+		if err == io.EOF {
+			flush()
+			return out.Flush()
+		}
+		if err != nil {
+			flush()
+			out.Flush()
+			return err
+		}
+		if err := flushIfIdle(out, br); err != nil {
+			return err
+		}
+	}
+}
+
+// Help returns the help text
+func (c *UniqCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of uniq
+func (c *UniqCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "uniq",
+		Summary: "Drop repeated lines.",
+		Description: "Prints the lines of file, relative to the working directory, or of the input, writing adjacent equal lines once. " +
+			"Only adjacent lines are compared, so the input is usually sorted first.",
+		Examples: []shell.HelpExample{
+			{Command: "uniq -c visits.txt", Description: "Count how many times each line repeats."},
+			{Command: "uniq -d sorted.txt", Description: "Print the lines that appear more than once."},
+		},
+		SeeAlso: []string{"sort"},
+	}
+}
@@ -0,0 +1,79 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniqCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "visits.txt"), []byte("a\na\nb\nc\nc\nc\na"), 0644)
+
+	cases := []struct {
+		name           string
+		args           []string
+		input          string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - input",
+			input:          "x\nx\ny\nx\n",
+			expectedOutput: "x\ny\nx\n",
+		},
+		{
+			name:           "success - count",
+			args:           []string{"-c", "visits.txt"},
+			expectedOutput: "      2 a\n      1 b\n      3 c\n      1 a\n",
+		},
+		{
+			name:           "success - repeated",
+			args:           []string{"-d", "visits.txt"},
+			expectedOutput: "a\nc\n",
+		},
+		{
+			name:           "success - unique",
+			args:           []string{"-u", "visits.txt"},
+			expectedOutput: "b\na\n",
+		},
+		{
+			name:           "success - ignore case",
+			args:           []string{"-ic"},
+			input:          "Go\ngo\nGO\n",
+			expectedOutput: "      3 Go\n",
+		},
+		{
+			name:          "failure - missing file",
+			args:          []string{"missing.txt"},
+			expectedError: "uniq: missing.txt: no such file or directory\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewUniqCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.input), &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+		})
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
 	"github.com/Ali-Farhadnia/goshell/internal/service/user"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
 )
 
 // UsersCommand implements the users command
@@ -32,8 +34,17 @@ func (c *UsersCommand) MaxArguments() int {
 	return 0
 }
 
+// Arguments declares the arguments of the command
+func (c *UsersCommand) Arguments() argparse.Spec {
+	return argparse.Spec{}
+}
+
 // Execute runs the command
 func (c *UsersCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	if _, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args); err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
 	users, err := c.userSVC.ListUsers()
 	if err != nil {
 		_, err = fmt.Fprintf(errorOutputWriter, "%v\n", err)
@@ -74,5 +85,14 @@ func (c *UsersCommand) Execute(ctx context.Context, args []string, inputReader i
 
 // Help returns the help text
 func (c *UsersCommand) Help() string {
-	return "users - List all registered users"
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of users
+func (c *UsersCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "users",
+		Summary: "List all registered users.",
+		SeeAlso: []string{"adduser", "login"},
+	}
 }
@@ -0,0 +1,246 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// wcInputWidth is the minimum width of the counts when an input that is not
+// a regular file is counted, as its size is not known beforehand
+const wcInputWidth = 7
+
+// WcCommand implements the wc command
+type WcCommand struct {
+	sessionRepo shell.SessionRepository
+}
+
+// NewWcCommand creates a new wc command
+func NewWcCommand(sessionRepo shell.SessionRepository) *WcCommand {
+	return &WcCommand{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Name returns the command name
+func (c *WcCommand) Name() string {
+	return "wc"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *WcCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *WcCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'l', Long: "lines", Description: "Print the number of lines."},
+			{Short: 'w', Long: "words", Description: "Print the number of words."},
+			{Short: 'm', Long: "chars", Description: "Print the number of characters."},
+			{Short: 'c', Long: "bytes", Description: "Print the number of bytes."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "file", Optional: true, Variadic: true},
+		},
+	}
+}
+
+// wcCounts holds the counts of one input, in the order they are printed
+type wcCounts [4]int64
+
+// The counts wc makes
+const (
+	wcLines = iota
+	wcWords
+	wcChars
+	wcBytes
+)
+
+// Execute runs the command
+func (c *WcCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	shown := []bool{parsed.Bool("l"), parsed.Bool("w"), parsed.Bool("m"), parsed.Bool("c")}
+	if !parsed.Bool("l") && !parsed.Bool("w") && !parsed.Bool("m") && !parsed.Bool("c") {
+		shown = []bool{true, true, false, true}
+	}
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	operands := operandsOrInput(parsed.Operands)
+	width := wcWidth(session.WorkingDir, operands, shown)
+
+	var total wcCounts
+	for _, operand := range operands {
+		counts, err := c.count(ctx, session.WorkingDir, operand, shown[wcWords] || shown[wcChars], inputReader)
+		if err != nil {
+			if done, err := reportInputError(errorOutputWriter, "wc", operand, err); done {
+				return err
+			}
+			continue
+		}
+
+		for i := range total {
+			total[i] += counts[i]
+		}
+
+		// the input is only named when it was given as -
+		name := operand
+		if len(parsed.Operands) == 0 {
+			name = ""
+		}
+		if err := writeCounts(outputWriter, counts, shown, width, name); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+			return err
+		}
+	}
+
+	if len(operands) > 1 {
+		if err := writeCounts(outputWriter, total, shown, width, "total"); err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// count counts one operand. Words and characters need the input decoded,
+// so they are only counted when wanted.
+func (c *WcCommand) count(ctx context.Context, workingDir, operand string, decode bool, inputReader io.Reader) (wcCounts, error) {
+	var counts wcCounts
+
+	r, f, err := openOperand(ctx, workingDir, operand, inputReader)
+	if err != nil {
+		return counts, err
+	}
+	if f != nil {
+		defer f.Close()
+	}
+
+	if !decode {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := r.Read(buf)
+			counts[wcBytes] += int64(n)
+			for _, b := range buf[:n] {
+				if b == '\n' {
+					counts[wcLines]++
+				}
+			}
+
+			if err == io.EOF {
+				return counts, nil
+			}
+			if err != nil {
+				return counts, err
+			}
+		}
+	}
+
+	br := bufio.NewReader(r)
+	inWord := false
+	for {
+		ch, size, err := br.ReadRune()
+		if err == io.EOF {
+			return counts, nil
+		}
+		if err != nil {
+			return counts, err
+		}
+
+		counts[wcBytes] += int64(size)
+		counts[wcChars]++
+		if ch == '\n' {
+			counts[wcLines]++
+		}
+
+		space := unicode.IsSpace(ch)
+		if !space && !inWord {
+			counts[wcWords]++
+		}
+		inWord = !space
+	}
+}
+
+// wcWidth returns the width the counts are aligned to, like GNU wc: wide
+// enough for the size of all the files, as no count can be larger. A single
+// count of a single file is not aligned.
+func wcWidth(workingDir string, operands []string, shown []bool) int {
+	columns := 0
+	for _, show := range shown {
+		if show {
+			columns++
+		}
+	}
+	if columns == 1 && len(operands) == 1 {
+		return 1
+	}
+
+	var size int64
+	width := 1
+	for _, operand := range operands {
+		info, err := os.Stat(resolvePath(workingDir, operand))
+		switch {
+		case operand == stdinOperand || (err == nil && !info.Mode().IsRegular()):
+			width = wcInputWidth
+		case err == nil:
+			size += info.Size()
+		}
+	}
+	return max(width, len(strconv.FormatInt(size, 10)))
+}
+
+// writeCounts prints the counts shown, aligned to width, followed by name
+// unless it is empty
+func writeCounts(w io.Writer, counts wcCounts, shown []bool, width int, name string) error {
+	var fields []string
+	for i, show := range shown {
+		if show {
+			fields = append(fields, fmt.Sprintf("%*d", width, counts[i]))
+		}
+	}
+	if name != "" {
+		fields = append(fields, name)
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(fields, " "))
+	return err
+}
+
+// Help returns the help text
+func (c *WcCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of wc
+func (c *WcCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "wc",
+		Summary: "Count lines, words and bytes.",
+		Description: "Prints the number of lines, words and bytes of each file relative to the working directory, or of the input when there is no file or it is -, followed by a total with several files. " +
+			"The flags choose the counts printed, always in the order lines, words, characters, bytes. Words are separated by white space and characters are decoded as UTF-8.",
+		Examples: []shell.HelpExample{
+			{Command: "wc -l main.go", Description: "Count the lines of a file."},
+			{Command: "wc -w < essay.txt", Description: "Count the words of the input."},
+		},
+		SeeAlso: []string{"cat", "grep"},
+	}
+}
@@ -0,0 +1,86 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWcCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "twenty.txt"), []byte(numberedLines(20)), 0644)
+	os.WriteFile(filepath.Join(dir, "words.txt"), []byte("héllo  wörld\n\tthird word"), 0644)
+
+	cases := []struct {
+		name           string
+		args           []string
+		input          string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - lines of a file",
+			args:           []string{"-l", "twenty.txt"},
+			expectedOutput: "20 twenty.txt\n",
+		},
+		{
+			name:           "success - default counts of a file",
+			args:           []string{"twenty.txt"},
+			expectedOutput: "20 20 51 twenty.txt\n",
+		},
+		{
+			name:           "success - default counts of the input",
+			input:          "one two\nthree\n",
+			expectedOutput: "      2       3      14\n",
+		},
+		{
+			name:           "success - single count of the input",
+			args:           []string{"-w"},
+			input:          "one two\nthree\n",
+			expectedOutput: "3\n",
+		},
+		{
+			name:           "success - characters and bytes",
+			args:           []string{"-mc", "words.txt"},
+			expectedOutput: "24 26 words.txt\n",
+		},
+		{
+			name:           "success - several files with a total",
+			args:           []string{"-lw", "twenty.txt", "words.txt"},
+			expectedOutput: "20 20 twenty.txt\n 1  4 words.txt\n21 24 total\n",
+		},
+		{
+			name:           "failure - missing file",
+			args:           []string{"-l", "missing.txt", "twenty.txt"},
+			expectedOutput: "20 twenty.txt\n20 total\n",
+			expectedError:  "wc: missing.txt: no such file or directory\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(repository.SessionRepositoryMock)
+			mockRepo.On("GetSession").Return(shell.Session{WorkingDir: dir}, nil)
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewWcCommand(mockRepo)
+			err := cmd.Execute(ctx, tc.args, strings.NewReader(tc.input), &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+	"github.com/Ali-Farhadnia/goshell/pkg/execpath"
+)
+
+// WhichCommand implements the which command
+type WhichCommand struct {
+	path string
+}
+
+// NewWhichCommand creates a new which command searching path
+func NewWhichCommand(path string) *WhichCommand {
+	return &WhichCommand{
+		path: path,
+	}
+}
+
+// Name returns the command name
+func (c *WhichCommand) Name() string {
+	return "which"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *WhichCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *WhichCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Flags: []argparse.Flag{
+			{Short: 'a', Description: "Print every matching executable in PATH, not only the first."},
+		},
+		Positionals: []argparse.Positional{
+			{Name: "name", Variadic: true},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *WhichCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+
+	path := shell.SearchPath(c.path)
+	for _, name := range parsed.Strings("name") {
+		paths := execpath.FindAllExecutables(name, path)
+		if len(paths) == 0 {
+			if _, err := fmt.Fprintf(errorOutputWriter, "which: no %s in (%s)\n", name, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if !parsed.Bool("a") {
+			paths = paths[:1]
+		}
+
+		for _, path := range paths {
+			if _, err := fmt.Fprintln(outputWriter, path); err != nil {
+				_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Help returns the help text
+func (c *WhichCommand) Help() string {
+	return shell.HelpLine(shell.CommandDoc(c))
+}
+
+// Doc returns the documentation of which
+func (c *WhichCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "which",
+		Summary:     "Locate executables in PATH.",
+		Description: "Prints the path of the executable that runs for each name. Unlike type, builtins are not considered.",
+		Examples: []shell.HelpExample{
+			{Command: "which -a python3", Description: "List every python3 in PATH, the one that runs first."},
+		},
+		ExitCodes: []shell.HelpExitCode{
+			{Code: 0, Description: "Every name was found."},
+			{Code: 1, Description: "A name was not found."},
+		},
+		SeeAlso: []string{"type", "command"},
+	}
+}
@@ -0,0 +1,71 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhichCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	// The same executable in two directories of PATH
+	dirs := []string{t.TempDir(), t.TempDir()}
+	for _, dir := range dirs {
+		err := os.WriteFile(filepath.Join(dir, "tool"), []byte("#!/bin/sh\n"), 0755)
+		assert.NoError(t, err)
+	}
+	path := dirs[0] + string(os.PathListSeparator) + dirs[1]
+	t.Setenv("PATH", path)
+
+	cases := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "success - first match",
+			args:           []string{"tool"},
+			expectedOutput: filepath.Join(dirs[0], "tool") + "\n",
+			expectedError:  "",
+		},
+		{
+			name:           "success - all matches",
+			args:           []string{"-a", "tool"},
+			expectedOutput: filepath.Join(dirs[0], "tool") + "\n" + filepath.Join(dirs[1], "tool") + "\n",
+			expectedError:  "",
+		},
+		{
+			name:           "failure - not found",
+			args:           []string{"nonexistent", "tool"},
+			expectedOutput: filepath.Join(dirs[0], "tool") + "\n",
+			expectedError:  "which: no nonexistent in (" + path + ")\n",
+		},
+		{
+			name:           "failure - no name",
+			args:           []string{},
+			expectedOutput: "",
+			expectedError:  "which: missing name\nusage: which [-a] <name> ...\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewWhichCommand(path)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// ZCommand implements the z command, which jumps to the most frecent
+// directory matching the given keywords
+type ZCommand struct {
+	sessionRepo shell.SessionRepository
+	frecencySVC *frecency.Service
+}
+
+// NewZCommand creates a new z command
+func NewZCommand(sessionRepo shell.SessionRepository, frecencySVC *frecency.Service) *ZCommand {
+	return &ZCommand{
+		sessionRepo: sessionRepo,
+		frecencySVC: frecencySVC,
+	}
+}
+
+// Name returns the command name
+func (c *ZCommand) Name() string {
+	return "z"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *ZCommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *ZCommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Positionals: []argparse.Positional{
+			{Name: "keyword", Optional: true, Variadic: true},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *ZCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	keywords := parsed.Strings("keyword")
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	// like cd, no keywords means home and an existing directory is used as is
+	var dir string
+	switch {
+	case len(keywords) == 0:
+		dir, err = homeDir()
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "z: %v\n", err)
+			return err
+		}
+	case len(keywords) == 1 && isDirectory(resolvePath(session.WorkingDir, keywords[0])):
+		dir = resolvePath(session.WorkingDir, keywords[0])
+	default:
+		matches, err := c.frecencySVC.Query(sessionUserID(session), keywords, session.WorkingDir)
+		if err != nil {
+			_, err = fmt.Fprintf(errorOutputWriter, "z: %v\n", err)
+			return err
+		}
+		if len(matches) == 0 {
+			_, err = fmt.Fprintf(errorOutputWriter, "z: no match found\n")
+			return err
+		}
+		dir = matches[0].Path
+	}
+
+	return changeWorkingDir(c.sessionRepo, c.frecencySVC, session, dir, errorOutputWriter)
+}
+
+// Help returns the help text
+func (c *ZCommand) Help() string {
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of z
+func (c *ZCommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:    "z",
+		Summary: "Jump to a frequently used directory.",
+		Description: "Changes to the best ranked directory matching the keywords. Directories are ranked by how often and how recently they were visited with cd, z and the other directory commands, per user." +
+			"\n\n" +
+			"Keywords must appear in the path in order, ignoring case, and the last one must match the last path component. Without keywords z goes to the best ranked directory.",
+		Examples: []shell.HelpExample{
+			{Command: "z proj", Description: "Jump to the project directory used most."},
+			{Command: "z src api", Description: "Jump to an api directory below a src directory."},
+		},
+		SeeAlso: []string{"zi", "cd"},
+	}
+}
@@ -0,0 +1,166 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	frecencyRepository "github.com/Ali-Farhadnia/goshell/internal/service/frecency/repository"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	shellRepository "github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/Ali-Farhadnia/goshell/internal/service/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// visitedDirs creates directories and a frecency service where each one was
+// visited as often as listed
+func visitedDirs(t *testing.T, userID *int64, visits map[string]int) (string, *frecency.Service) {
+	root := t.TempDir()
+	service := frecency.New(frecencyRepository.NewInMemory(), frecencyRepository.NewInMemory(), -1)
+	for dir, count := range visits {
+		path := filepath.Join(root, dir)
+		assert.NoError(t, os.MkdirAll(path, 0o755))
+		for i := 0; i < count; i++ {
+			assert.NoError(t, service.Visit(userID, path))
+		}
+	}
+	return root, service
+}
+
+func TestZCommand_Execute(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(3)
+
+	cases := []struct {
+		name          string
+		args          []string
+		session       func(root string) shell.Session
+		expectedDir   string // relative to the temporary root, empty when not changed
+		expectedError string
+	}{
+		{
+			name:        "best match",
+			args:        []string{"proj"},
+			session:     func(root string) shell.Session { return shell.Session{WorkingDir: root} },
+			expectedDir: "src/project",
+		},
+		{
+			name:        "several keywords",
+			args:        []string{"other", "proj"},
+			session:     func(root string) shell.Session { return shell.Session{WorkingDir: root} },
+			expectedDir: "other/project",
+		},
+		{
+			name:        "working directory is skipped",
+			args:        []string{"proj"},
+			session:     func(root string) shell.Session { return shell.Session{WorkingDir: filepath.Join(root, "src/project")} },
+			expectedDir: "other/project",
+		},
+		{
+			name:        "existing directory is used as is",
+			args:        []string{"src"},
+			session:     func(root string) shell.Session { return shell.Session{WorkingDir: root} },
+			expectedDir: "src",
+		},
+		{
+			name:        "scores are per user",
+			args:        []string{"proj"},
+			session:     func(root string) shell.Session { return shell.Session{WorkingDir: root, User: &user.User{ID: userID}} },
+			expectedDir: "other/project",
+		},
+		{
+			name:          "no match",
+			args:          []string{"nothing"},
+			session:       func(root string) shell.Session { return shell.Session{WorkingDir: root} },
+			expectedError: "z: no match found\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, service := visitedDirs(t, nil, map[string]int{"src/project": 3, "other/project": 1})
+			assert.NoError(t, service.Visit(&userID, filepath.Join(root, "other/project")))
+
+			mockSessionRepo := new(shellRepository.SessionRepositoryMock)
+			mockSessionRepo.On("GetSession").Return(tc.session(root), nil).Once()
+			if tc.expectedDir != "" {
+				mockSessionRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == filepath.Join(root, tc.expectedDir)
+				})).Return(nil).Once()
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			cmd := commands.NewZCommand(mockSessionRepo, service)
+			err := cmd.Execute(ctx, tc.args, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Empty(t, outputBuffer.String())
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockSessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestZICommand_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name          string
+		lines         []string
+		expectedDir   string
+		expectedError string
+	}{
+		{
+			name:        "select the second match",
+			lines:       []string{"2"},
+			expectedDir: "other/project",
+		},
+		{
+			name:  "empty answer keeps the directory",
+			lines: []string{""},
+		},
+		{
+			name:          "invalid selection",
+			lines:         []string{"9"},
+			expectedError: "zi: invalid selection: 9\n",
+		},
+		{
+			name:          "no terminal",
+			expectedError: "zi: input is not a terminal\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, service := visitedDirs(t, nil, map[string]int{"src/project": 3, "other/project": 1})
+
+			mockSessionRepo := new(shellRepository.SessionRepositoryMock)
+			mockSessionRepo.On("GetSession").Return(shell.Session{WorkingDir: root}, nil).Once()
+			if tc.expectedDir != "" {
+				mockSessionRepo.On("SetSession", mock.MatchedBy(func(s shell.Session) bool {
+					return s.WorkingDir == filepath.Join(root, tc.expectedDir)
+				})).Return(nil).Once()
+			}
+
+			var outputBuffer bytes.Buffer
+			var errorBuffer bytes.Buffer
+
+			reader := &PasswordReaderStub{Lines: tc.lines}
+			cmd := commands.NewZICommand(mockSessionRepo, service, reader)
+			err := cmd.Execute(ctx, []string{"proj"}, nil, &outputBuffer, &errorBuffer)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "  1    12.0  "+filepath.Join(root, "src/project")+"\n  2     4.0  "+filepath.Join(root, "other/project")+"\n", outputBuffer.String())
+			assert.Equal(t, []string{"Select a directory: "}, reader.Prompts)
+			assert.Equal(t, tc.expectedError, errorBuffer.String())
+			mockSessionRepo.AssertExpectations(t)
+		})
+	}
+}
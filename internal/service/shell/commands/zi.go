@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/frecency"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+	"github.com/Ali-Farhadnia/goshell/pkg/lineeditor"
+)
+
+// ziMaxChoices is the number of directories zi offers
+const ziMaxChoices = 20
+
+// ZICommand implements the zi command, which lists the directories matching
+// the keywords and jumps to the one the user picks
+type ZICommand struct {
+	sessionRepo shell.SessionRepository
+	frecencySVC *frecency.Service
+	lineReader  shell.LineReader
+}
+
+// NewZICommand creates a new zi command
+func NewZICommand(sessionRepo shell.SessionRepository, frecencySVC *frecency.Service, lineReader shell.LineReader) *ZICommand {
+	return &ZICommand{
+		sessionRepo: sessionRepo,
+		frecencySVC: frecencySVC,
+		lineReader:  lineReader,
+	}
+}
+
+// Name returns the command name
+func (c *ZICommand) Name() string {
+	return "zi"
+}
+
+// MaxArguments returns the maximum number of arguments allowed for the Command.
+func (c *ZICommand) MaxArguments() int {
+	return -1
+}
+
+// Arguments declares the arguments of the command
+func (c *ZICommand) Arguments() argparse.Spec {
+	return argparse.Spec{
+		Positionals: []argparse.Positional{
+			{Name: "keyword", Optional: true, Variadic: true},
+		},
+	}
+}
+
+// Execute runs the command
+func (c *ZICommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), c.Arguments(), args)
+	if err != nil {
+		return shell.WriteArgsError(errorOutputWriter, err)
+	}
+	keywords := parsed.Strings("keyword")
+
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return err
+	}
+
+	matches, err := c.frecencySVC.Query(sessionUserID(session), keywords, session.WorkingDir)
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "zi: %v\n", err)
+		return err
+	}
+	if len(matches) == 0 {
+		_, err = fmt.Fprintf(errorOutputWriter, "zi: no match found\n")
+		return err
+	}
+	if len(matches) > ziMaxChoices {
+		matches = matches[:ziMaxChoices]
+	}
+
+	for i, match := range matches {
+		_, err = fmt.Fprintf(outputWriter, "%3d  %6.1f  %s\n", i+1, match.Score, match.Path)
+		if err != nil {
+			return err
+		}
+	}
+
+	answer, err := c.lineReader.ReadLine("Select a directory: ")
+	if errors.Is(err, lineeditor.ErrInterrupted) || errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "zi: %v\n", err)
+		return err
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return nil
+	}
+
+	choice, err := strconv.Atoi(answer)
+	if err != nil || choice < 1 || choice > len(matches) {
+		_, err = fmt.Fprintf(errorOutputWriter, "zi: invalid selection: %s\n", answer)
+		return err
+	}
+
+	return changeWorkingDir(c.sessionRepo, c.frecencySVC, session, matches[choice-1].Path, errorOutputWriter)
+}
+
+// Help returns the help text
+func (c *ZICommand) Help() string {
+	return shell.HelpLine(c.Doc())
+}
+
+// Doc returns the documentation of zi
+func (c *ZICommand) Doc() shell.HelpDoc {
+	return shell.HelpDoc{
+		Name:        "zi",
+		Summary:     "Choose a frequently used directory interactively.",
+		Description: "Lists up to 20 of the best ranked directories matching the keywords, with their scores, and changes to the one selected by number. Keywords match like in z.",
+		Examples: []shell.HelpExample{
+			{Command: "zi proj", Description: "Pick among the directories matching proj."},
+		},
+		SeeAlso: []string{"z", "cd"},
+	}
+}
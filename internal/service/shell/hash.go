@@ -0,0 +1,148 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Ali-Farhadnia/goshell/pkg/execpath"
+)
+
+// HashEntry is an executable remembered by the command hash table
+type HashEntry struct {
+	Name string
+	Path string
+	Hits int // times the entry was used to run the command
+}
+
+// CommandHash remembers where the executables run by a session were found,
+// so PATH is not searched again every time a command runs. The entries are
+// dropped when PATH changes. A nil CommandHash searches PATH every time.
+type CommandHash struct {
+	mu      sync.Mutex
+	path    string // the PATH the entries were found in
+	entries map[string]*HashEntry
+}
+
+// NewCommandHash creates an empty command hash table
+func NewCommandHash() *CommandHash {
+	return &CommandHash{
+		entries: make(map[string]*HashEntry),
+	}
+}
+
+// Find returns the executable that runs for name, searching path only when
+// name is not in the table or its executable has been removed since. Names
+// containing a slash are paths and are never hashed.
+func (h *CommandHash) Find(name, path string) (string, error) {
+	if h == nil || strings.ContainsRune(name, '/') {
+		return execpath.FindExecutable(name, path)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checkPath(path)
+	if entry, ok := h.entries[name]; ok && execpath.IsExecutable(entry.Path) {
+		entry.Hits++
+		return entry.Path, nil
+	}
+
+	exePath, err := execpath.FindExecutable(name, path)
+	if err != nil {
+		delete(h.entries, name)
+		return "", err
+	}
+	h.entries[name] = &HashEntry{Name: name, Path: exePath, Hits: 1}
+	return exePath, nil
+}
+
+// Add searches path for name and remembers the executable without running it
+func (h *CommandHash) Add(name, path string) error {
+	exePath, err := execpath.FindExecutable(name, path)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checkPath(path)
+	h.entries[name] = &HashEntry{Name: name, Path: exePath}
+	return nil
+}
+
+// Set remembers exePath as the executable of name, whether or not it is in
+// path, until path changes
+func (h *CommandHash) Set(name, exePath, path string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checkPath(path)
+	h.entries[name] = &HashEntry{Name: name, Path: filepath.Clean(exePath)}
+}
+
+// Delete forgets name and reports whether it was in the table
+func (h *CommandHash) Delete(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, ok := h.entries[name]
+	delete(h.entries, name)
+	return ok
+}
+
+// Reset forgets every executable
+func (h *CommandHash) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = make(map[string]*HashEntry)
+}
+
+// Entries returns the remembered executables of path sorted by name
+func (h *CommandHash) Entries(path string) []HashEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checkPath(path)
+	entries := make([]HashEntry, 0, len(h.entries))
+	for _, entry := range h.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// checkPath drops the entries when they were found in another PATH. The
+// caller holds the lock.
+func (h *CommandHash) checkPath(path string) {
+	if h.path != path {
+		h.entries = make(map[string]*HashEntry)
+		h.path = path
+	}
+}
+
+// SearchPath returns the PATH executables are searched in: the PATH
+// environment variable, which can be changed while the shell runs, or path,
+// the PATH the shell started with, when the variable is unset.
+func SearchPath(path string) string {
+	if current, ok := os.LookupEnv("PATH"); ok {
+		return current
+	}
+	return path
+}
+
+// CommandPath returns the path of an executable given as a relative path,
+// such as ./build.sh, from the working directory of the session. Other names
+// are returned unchanged.
+func CommandPath(name, workingDir string) string {
+	if strings.ContainsRune(name, '/') && !filepath.IsAbs(name) {
+		return filepath.Join(workingDir, name)
+	}
+	return name
+}
@@ -0,0 +1,310 @@
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+)
+
+// HelpDoc is the structured documentation of a builtin. It is shown by
+// help <command> and <command> --help, and rendered to Markdown and man pages.
+type HelpDoc struct {
+	Name        string
+	Summary     string   // one line description
+	Synopsis    []string // usage lines
+	Description string   // paragraphs separated by blank lines
+	Flags       []HelpFlag
+	Examples    []HelpExample
+	ExitCodes   []HelpExitCode
+	SeeAlso     []string
+}
+
+// HelpFlag documents a flag or operand
+type HelpFlag struct {
+	Flag        string
+	Description string
+}
+
+// HelpExample documents a command line and what it does
+type HelpExample struct {
+	Command     string
+	Description string
+}
+
+// HelpExitCode documents an exit status
+type HelpExitCode struct {
+	Code        int
+	Description string
+}
+
+// Documented is implemented by commands with structured documentation
+type Documented interface {
+	Doc() HelpDoc
+}
+
+// defaultExitCodes is the exit status of builtins that report errors on
+// stderr, used when a command does not document its own
+var defaultExitCodes = []HelpExitCode{
+	{Code: 0, Description: "Success."},
+	{Code: 1, Description: "An error was reported."},
+}
+
+// CommandDoc returns the documentation of cmd. Commands that are not
+// Documented get one made from their "synopsis - summary" help line, and
+// commands that declare their arguments get their synopsis and flags from
+// the declaration.
+func CommandDoc(cmd Command) HelpDoc {
+	var doc HelpDoc
+	if documented, ok := cmd.(Documented); ok {
+		doc = documented.Doc()
+	} else {
+		synopsis, summary, found := strings.Cut(cmd.Help(), " - ")
+		if found {
+			doc.Synopsis, doc.Summary = []string{synopsis}, summary
+		} else {
+			doc.Summary = cmd.Help()
+		}
+	}
+
+	if doc.Name == "" {
+		doc.Name = cmd.Name()
+	}
+
+	// the usage lines and flags of declared arguments are documented from
+	// the declaration unless the command documents them itself
+	if declarer, ok := cmd.(ArgumentDeclarer); ok {
+		spec := declarer.Arguments()
+		if len(doc.Synopsis) == 0 {
+			doc.Synopsis = argparse.Synopsis(doc.Name, spec)
+		}
+		if len(doc.Flags) == 0 {
+			doc.Flags = argumentHelp(spec, "")
+		}
+		if len(doc.ExitCodes) == 0 {
+			doc.ExitCodes = defaultExitCodes
+		}
+		if !slices.ContainsFunc(doc.ExitCodes, func(c HelpExitCode) bool { return c.Code == usageExitCode }) {
+			doc.ExitCodes = append(slices.Clip(doc.ExitCodes), HelpExitCode{Code: usageExitCode, Description: "Invalid arguments."})
+		}
+	}
+
+	if len(doc.Synopsis) == 0 {
+		doc.Synopsis = []string{doc.Name}
+	}
+	if len(doc.ExitCodes) == 0 {
+		doc.ExitCodes = defaultExitCodes
+	}
+
+	return doc
+}
+
+// HelpLine returns the one line help of a documented command, its first
+// synopsis followed by the summary
+func HelpLine(doc HelpDoc) string {
+	synopsis := doc.Name
+	if len(doc.Synopsis) > 0 {
+		synopsis = doc.Synopsis[0]
+	}
+	return synopsis + " - " + doc.Summary
+}
+
+// MatchesKeyword reports whether the name, summary, description or flags of
+// doc contain keyword, ignoring case
+func (doc HelpDoc) MatchesKeyword(keyword string) bool {
+	keyword = strings.ToLower(keyword)
+	texts := []string{doc.Name, doc.Summary, doc.Description}
+	for _, flag := range doc.Flags {
+		texts = append(texts, flag.Flag, flag.Description)
+	}
+
+	for _, text := range texts {
+		if strings.Contains(strings.ToLower(text), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteHelp writes doc as plain text for the terminal
+func WriteHelp(w io.Writer, doc HelpDoc) error {
+	bw := bufio.NewWriter(w)
+	indent := func(text string) {
+		for _, line := range strings.Split(text, "\n") {
+			if line == "" {
+				fmt.Fprintln(bw)
+			} else {
+				fmt.Fprintf(bw, "    %s\n", line)
+			}
+		}
+	}
+
+	fmt.Fprintf(bw, "NAME\n    %s - %s\n", doc.Name, doc.Summary)
+
+	fmt.Fprintf(bw, "\nSYNOPSIS\n")
+	for _, synopsis := range doc.Synopsis {
+		indent(synopsis)
+	}
+
+	if doc.Description != "" {
+		fmt.Fprintf(bw, "\nDESCRIPTION\n")
+		indent(doc.Description)
+	}
+
+	if len(doc.Flags) > 0 {
+		fmt.Fprintf(bw, "\nOPTIONS\n")
+		for _, flag := range doc.Flags {
+			fmt.Fprintf(bw, "    %s\n        %s\n", flag.Flag, flag.Description)
+		}
+	}
+
+	if len(doc.Examples) > 0 {
+		fmt.Fprintf(bw, "\nEXAMPLES\n")
+		for _, example := range doc.Examples {
+			fmt.Fprintf(bw, "    $ %s\n        %s\n", example.Command, example.Description)
+		}
+	}
+
+	fmt.Fprintf(bw, "\nEXIT STATUS\n")
+	for _, exitCode := range doc.ExitCodes {
+		fmt.Fprintf(bw, "    %-4d%s\n", exitCode.Code, exitCode.Description)
+	}
+
+	if len(doc.SeeAlso) > 0 {
+		fmt.Fprintf(bw, "\nSEE ALSO\n    %s\n", strings.Join(doc.SeeAlso, ", "))
+	}
+
+	return bw.Flush()
+}
+
+// WriteMarkdown writes the docs as one Markdown document, a section per
+// command
+func WriteMarkdown(w io.Writer, docs []HelpDoc) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Builtin commands\n")
+
+	for _, doc := range docs {
+		fmt.Fprintf(bw, "\n## %s\n\n%s\n\n```\n%s\n```\n", doc.Name, markdownEscape(doc.Summary), strings.Join(doc.Synopsis, "\n"))
+
+		if doc.Description != "" {
+			fmt.Fprintf(bw, "\n%s\n", markdownEscape(doc.Description))
+		}
+
+		if len(doc.Flags) > 0 {
+			fmt.Fprintf(bw, "\n### Options\n\n| Option | Description |\n| --- | --- |\n")
+			for _, flag := range doc.Flags {
+				fmt.Fprintf(bw, "| `%s` | %s |\n", markdownCell(flag.Flag), markdownCell(markdownEscape(flag.Description)))
+			}
+		}
+
+		if len(doc.Examples) > 0 {
+			fmt.Fprintf(bw, "\n### Examples\n")
+			for _, example := range doc.Examples {
+				fmt.Fprintf(bw, "\n%s\n\n```bash\n$ %s\n```\n", markdownEscape(example.Description), example.Command)
+			}
+		}
+
+		fmt.Fprintf(bw, "\n### Exit status\n\n| Code | Meaning |\n| --- | --- |\n")
+		for _, exitCode := range doc.ExitCodes {
+			fmt.Fprintf(bw, "| %d | %s |\n", exitCode.Code, markdownCell(markdownEscape(exitCode.Description)))
+		}
+
+		if len(doc.SeeAlso) > 0 {
+			links := make([]string, len(doc.SeeAlso))
+			for i, name := range doc.SeeAlso {
+				links[i] = fmt.Sprintf("[%s](#%s)", name, name)
+			}
+			fmt.Fprintf(bw, "\nSee also: %s\n", strings.Join(links, ", "))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// markdownEscape escapes the characters that would start Markdown formatting
+// in running text
+func markdownEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "<", `&lt;`).Replace(s)
+}
+
+// markdownCell makes text safe to use in a table cell
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// WriteMan writes doc as a man(1) page in roff
+func WriteMan(w io.Writer, doc HelpDoc) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, ".TH %s 1 \"\" \"goshell\" \"goshell builtins\"\n", strings.ToUpper(doc.Name))
+	fmt.Fprintf(bw, ".SH NAME\n%s \\- %s\n", roffEscape(doc.Name), roffEscape(doc.Summary))
+
+	fmt.Fprintf(bw, ".SH SYNOPSIS\n.nf\n")
+	for _, synopsis := range doc.Synopsis {
+		fmt.Fprintln(bw, roffLine(synopsis))
+	}
+	fmt.Fprintf(bw, ".fi\n")
+
+	if doc.Description != "" {
+		fmt.Fprintf(bw, ".SH DESCRIPTION\n")
+		for i, paragraph := range strings.Split(doc.Description, "\n\n") {
+			if i > 0 {
+				fmt.Fprintln(bw, ".PP")
+			}
+			fmt.Fprintln(bw, roffLine(paragraph))
+		}
+	}
+
+	if len(doc.Flags) > 0 {
+		fmt.Fprintf(bw, ".SH OPTIONS\n")
+		for _, flag := range doc.Flags {
+			fmt.Fprintf(bw, ".TP\n.B %s\n%s\n", roffEscape(flag.Flag), roffLine(flag.Description))
+		}
+	}
+
+	if len(doc.Examples) > 0 {
+		fmt.Fprintf(bw, ".SH EXAMPLES\n")
+		for _, example := range doc.Examples {
+			fmt.Fprintf(bw, ".TP\n.B %s\n%s\n", roffEscape(example.Command), roffLine(example.Description))
+		}
+	}
+
+	fmt.Fprintf(bw, ".SH EXIT STATUS\n")
+	for _, exitCode := range doc.ExitCodes {
+		fmt.Fprintf(bw, ".TP\n.B %s\n%s\n", strconv.Itoa(exitCode.Code), roffLine(exitCode.Description))
+	}
+
+	if len(doc.SeeAlso) > 0 {
+		fmt.Fprintf(bw, ".SH \"SEE ALSO\"\n")
+		for i, name := range doc.SeeAlso {
+			sep := ","
+			if i == len(doc.SeeAlso)-1 {
+				sep = ""
+			}
+			fmt.Fprintf(bw, ".BR %s (1)%s\n", roffEscape(name), sep)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// roffEscape escapes backslashes and hyphens for roff
+func roffEscape(s string) string {
+	return strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+}
+
+// roffLine escapes text and keeps lines starting with . or ' from being read
+// as requests
+func roffLine(s string) string {
+	lines := strings.Split(roffEscape(s), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,129 @@
+package shell
+
+import (
+	"strings"
+
+	"github.com/Ali-Farhadnia/goshell/pkg/execpath"
+	"github.com/Ali-Farhadnia/goshell/pkg/inputprocessor"
+)
+
+// ANSI colours used by the highlighter
+const (
+	colorReset        = "\x1b[0m"
+	colorBuiltin      = "\x1b[1;32m"
+	colorExecutable   = "\x1b[32m"
+	colorUnknown      = "\x1b[1;31m"
+	colorString       = "\x1b[33m"
+	colorVariable     = "\x1b[36m"
+	colorRedirection  = "\x1b[35m"
+	colorUnterminated = "\x1b[4;31m"
+)
+
+// Highlighter colours an input line while it is typed so that unknown
+// commands and unterminated quotes stand out before Enter is pressed.
+type Highlighter struct {
+	commandRepo CommandRepository
+	path        string
+
+	lastCommand string
+	lastColor   string
+}
+
+// NewHighlighter creates a new input highlighter
+func NewHighlighter(commandRepo CommandRepository, path string) *Highlighter {
+	return &Highlighter{
+		commandRepo: commandRepo,
+		path:        path,
+	}
+}
+
+// Highlight returns the line with ANSI colour codes added
+func (h *Highlighter) Highlight(line string) string {
+	tokens := inputprocessor.Tokenize(line)
+	commandArg := commandArgument(tokens)
+
+	var b strings.Builder
+	last := 0
+	for _, tok := range tokens {
+		b.WriteString(line[last:tok.Start])
+		last = tok.End
+
+		color := ""
+		switch tok.Kind {
+		case inputprocessor.TokenString:
+			color = colorString
+		case inputprocessor.TokenVariable:
+			color = colorVariable
+		case inputprocessor.TokenRedirection:
+			color = colorRedirection
+		case inputprocessor.TokenUnterminated:
+			color = colorUnterminated
+		case inputprocessor.TokenWord:
+			if tok.Arg == commandArg {
+				color = h.commandColor(commandName(tokens, commandArg))
+			}
+		}
+
+		if color == "" {
+			b.WriteString(tok.Text)
+			continue
+		}
+		b.WriteString(color)
+		b.WriteString(tok.Text)
+		b.WriteString(colorReset)
+	}
+	b.WriteString(line[last:])
+
+	return b.String()
+}
+
+// commandColor returns the colour for a command name, remembering the last
+// lookup since the same name is highlighted on every key press.
+func (h *Highlighter) commandColor(name string) string {
+	if name == h.lastCommand && h.lastColor != "" {
+		return h.lastColor
+	}
+
+	color := colorUnknown
+	if _, err := h.commandRepo.Get(name); err == nil {
+		color = colorBuiltin
+	} else if _, err := execpath.FindExecutable(name, SearchPath(h.path)); err == nil {
+		color = colorExecutable
+	}
+
+	h.lastCommand = name
+	h.lastColor = color
+	return color
+}
+
+// commandArgument returns the index of the argument holding the command
+// name, skipping redirections and their targets like ProcessRedirections does.
+func commandArgument(tokens []inputprocessor.Token) int {
+	skip := -1
+	for _, tok := range tokens {
+		if tok.Kind == inputprocessor.TokenRedirection {
+			skip = tok.Arg + 1
+			continue
+		}
+		if tok.Arg != skip {
+			return tok.Arg
+		}
+	}
+	return -1
+}
+
+// commandName returns the unquoted text of the given argument.
+func commandName(tokens []inputprocessor.Token, arg int) string {
+	var b strings.Builder
+	for _, tok := range tokens {
+		if tok.Arg == arg {
+			b.WriteString(tok.Text)
+		}
+	}
+
+	args, err := inputprocessor.ParseArguments(b.String())
+	if err != nil || len(args) != 1 {
+		return b.String()
+	}
+	return args[0]
+}
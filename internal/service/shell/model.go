@@ -3,6 +3,12 @@ package shell
 import "github.com/Ali-Farhadnia/goshell/internal/service/user"
 
 type Session struct {
-	User       *user.User
-	WorkingDir string
+	ID            string // identifies the shell process in history records
+	User          *user.User
+	WorkingDir    string
+	OldWorkingDir string   // previous working directory, used by cd -
+	DirStack      []string // directories saved by pushd, top first
+	EditingMode   string
+	KeyBindings   map[string]map[string]string // keymap -> key -> action
+	Hash          *CommandHash                 // executables already found in PATH
 }
@@ -0,0 +1,127 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Ali-Farhadnia/goshell/pkg/execpath"
+	"github.com/Ali-Farhadnia/goshell/pkg/fuzzy"
+)
+
+// CommandNotFoundHandle is the name of the command run, like in bash, when a
+// command cannot be found. It receives the missing command and its arguments.
+const CommandNotFoundHandle = "command_not_found_handle"
+
+// maxSuggestions limits the number of "did you mean" suggestions
+const maxSuggestions = 3
+
+// CommandNotFoundError is returned when a command is neither a builtin nor an
+// executable in PATH. It matches ErrCommandNotFound with errors.Is.
+type CommandNotFoundError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *CommandNotFoundError) Error() string {
+	msg := "command not found: " + e.Name
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+
+	quoted := make([]string, len(e.Suggestions))
+	for i, suggestion := range e.Suggestions {
+		quoted[i] = "`" + suggestion + "`"
+	}
+
+	alternatives := quoted[0]
+	if len(quoted) > 1 {
+		alternatives = strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+	}
+	return fmt.Sprintf("%s, did you mean %s?", msg, alternatives)
+}
+
+// Unwrap allows errors.Is(err, ErrCommandNotFound).
+func (e *CommandNotFoundError) Unwrap() error {
+	return ErrCommandNotFound
+}
+
+// executableCache caches the executables found in PATH. The listing is
+// refreshed when PATH changes.
+type executableCache struct {
+	mu          sync.Mutex
+	path        string
+	loaded      bool
+	executables []string
+}
+
+// list returns the executables in path, reading the directories only once per path.
+func (c *executableCache) list(path string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded || c.path != path {
+		c.executables = execpath.ListExecutables(path)
+		c.path = path
+		c.loaded = true
+	}
+	return c.executables
+}
+
+// commandNotFound runs the command_not_found_handle hook when one is
+// available, otherwise it returns a CommandNotFoundError with suggestions.
+// Either way the attempt is recorded in history, with status 127 when no hook ran.
+func (s *Service) commandNotFound(ctx context.Context, cmdName string, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	return s.runAndRecord(ctx, cmdName, args, nil, func() (int, error) {
+		if cmdName != CommandNotFoundHandle {
+			hookArgs := append([]string{cmdName}, args...)
+			if hook, err := s.commandRepo.Get(CommandNotFoundHandle); err == nil {
+				stderr := &errorOutputTracker{w: errorOutputWriter}
+				err := hook.Execute(ctx, hookArgs, inputReader, outputWriter, stderr)
+				var exitStatus *ExitStatus
+				if errors.As(err, &exitStatus) {
+					return exitStatus.Code, nil
+				}
+				if err != nil || stderr.written {
+					return ExitNotFound, err
+				}
+				return 0, nil
+			}
+			if hookPath, err := execpath.FindExecutable(CommandNotFoundHandle, SearchPath(s.path)); err == nil {
+				return s.systemCommand.Run(ctx, hookPath, hookArgs, inputReader, outputWriter, errorOutputWriter)
+			}
+		}
+
+		return ExitNotFound, &CommandNotFoundError{
+			Name:        cmdName,
+			Suggestions: s.suggestCommands(cmdName),
+		}
+	})
+}
+
+// suggestCommands returns the builtins and PATH executables closest to name.
+func (s *Service) suggestCommands(name string) []string {
+	var candidates []string
+	if cmds, err := s.commandRepo.List(); err == nil {
+		for _, cmd := range cmds {
+			candidates = append(candidates, cmd.Name())
+		}
+	}
+	candidates = append(candidates, s.executables.list(SearchPath(s.path))...)
+
+	// short names only tolerate a single typo
+	maxDistance := 2
+	if utf8.RuneCountInString(name) <= 4 {
+		maxDistance = 1
+	}
+
+	suggestions := fuzzy.Closest(name, candidates, maxDistance)
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
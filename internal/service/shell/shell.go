@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/Ali-Farhadnia/goshell/internal/service/history"
-	"github.com/Ali-Farhadnia/goshell/pkg/execpath"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+	"github.com/Ali-Farhadnia/goshell/pkg/inputprocessor"
 )
 
 var (
@@ -41,12 +44,66 @@ type Command interface {
 	Help() string
 }
 
+// SecretArguments is implemented by commands that accept secrets such as
+// passwords as arguments. The arguments at the returned indices are redacted
+// before the command is saved to history.
+type SecretArguments interface {
+	SecretArguments(args []string) []int
+}
+
+// PasswordReader reads a password from the user without echoing it
+type PasswordReader interface {
+	ReadPassword(prompt string) (string, error)
+}
+
+// LineReader reads a line typed by the user, such as the answer to a question
+type LineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+// TerminalInput reads the input of the shell through the line editor, which
+// may already hold input it read ahead. Builtins that consume their input
+// themselves, such as read, use it when their input is the shell's.
+type TerminalInput interface {
+	LineReader
+	// ReadChars reads up to and including delim, or count characters when
+	// count is positive, echoing them unless echo is false
+	ReadChars(prompt string, count int, delim rune, echo bool) (string, error)
+	// SetDeadline makes reads fail with os.ErrDeadlineExceeded after t
+	SetDeadline(t time.Time)
+	IsTerminal() bool
+	Input() *os.File
+}
+
+// ExitStatus is returned by a command to end with a status other than the
+// one derived from its error output, such as grep finding no match. It is
+// not reported as an error.
+type ExitStatus struct {
+	Code int
+}
+
+func (e *ExitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitShell is returned by the exit builtin to end the shell with Code. The
+// shell exits once the command has been recorded in history, so it is passed
+// on by RunBuiltin with Code as the exit status.
+type ExitShell struct {
+	Code int
+}
+
+func (e *ExitShell) Error() string {
+	return fmt.Sprintf("exit %d", e.Code)
+}
+
 type Service struct {
 	historySVC    *history.Service
 	sessionRepo   SessionRepository
 	commandRepo   CommandRepository
 	systemCommand *SystemCommand
 	path          string
+	executables   *executableCache
 }
 
 func NewService(
@@ -62,6 +119,7 @@ func NewService(
 		commandRepo:   commandRepo,
 		systemCommand: systemCommand,
 		path:          path,
+		executables:   &executableCache{},
 	}
 }
 
@@ -79,12 +137,17 @@ func (s *Service) ExecuteCommand(ctx context.Context, cmdName string, args []str
 			return err
 		}
 
-		// Check if it's a system command
-		if _, err := execpath.FindExecutable(cmdName, s.path); err != nil {
+		// Check if it's a system command, found once and run by its path
+		session, err := s.sessionRepo.GetSession()
+		if err != nil {
 			return err
 		}
+		cmdPath, err := session.Hash.Find(CommandPath(cmdName, session.WorkingDir), SearchPath(s.path))
+		if err != nil {
+			return s.commandNotFound(ctx, cmdName, args, inputReader, outputWriter, errorOutputWriter)
+		}
 
-		return s.executeSystemCommand(ctx, cmdName, args, inputReader, outputWriter, errorOutputWriter)
+		return s.executeSystemCommand(ctx, cmdName, cmdPath, args, inputReader, outputWriter, errorOutputWriter)
 	}
 
 	return s.executeBuiltinCommand(ctx, cmd, args, inputReader, outputWriter, errorOutputWriter)
@@ -92,38 +155,94 @@ func (s *Service) ExecuteCommand(ctx context.Context, cmdName string, args []str
 
 // executeBuiltinCommand runs a built-in command after performing necessary checks.
 func (s *Service) executeBuiltinCommand(ctx context.Context, cmd Command, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	ctx, argsErr := withParsedArguments(ctx, cmd, args)
+
 	// Handle help flag
-	if isHelpRequested(args) {
-		_, err := fmt.Fprintf(outputWriter, "%s", cmd.Help())
-		if err != nil {
-			_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
+	if helpRequested(cmd, args, argsErr) {
+		return writeCommandHelp(cmd, outputWriter, errorOutputWriter)
+	}
+
+	// Validate argument count; declared arguments are checked by runBuiltin
+	// so that invalid ones are recorded with their exit status
+	if _, ok := cmd.(ArgumentDeclarer); !ok {
+		if err := validateArgs(cmd, args); err != nil {
 			return err
 		}
+	}
 
-		return nil
+	var secrets []int
+	if secretArgs, ok := cmd.(SecretArguments); ok {
+		secrets = secretArgs.SecretArguments(args)
 	}
 
-	// Validate argument count
-	if err := validateArgs(cmd, args); err != nil {
-		return err
+	return s.runAndRecord(ctx, cmd.Name(), args, secrets, func() (int, error) {
+		return runBuiltin(ctx, cmd, args, argsErr, inputReader, outputWriter, errorOutputWriter)
+	})
+}
+
+// RunBuiltin runs a builtin the way the Service does, handling --help and
+// invalid arguments, but without recording it in history. It is used by
+// builtins that run other builtins and returns the exit status.
+func RunBuiltin(ctx context.Context, cmd Command, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) (int, error) {
+	ctx, argsErr := withParsedArguments(ctx, cmd, args)
+	return runBuiltin(ctx, cmd, args, argsErr, inputReader, outputWriter, errorOutputWriter)
+}
+
+// runBuiltin runs a builtin whose declared arguments were parsed into ctx,
+// argsErr being the error of parsing them
+func runBuiltin(ctx context.Context, cmd Command, args []string, argsErr error, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) (int, error) {
+	if helpRequested(cmd, args, argsErr) {
+		return 0, writeCommandHelp(cmd, outputWriter, errorOutputWriter)
 	}
 
-	// Get user ID
-	userID, err := s.getUserID()
-	if err != nil {
-		return err
+	if _, ok := cmd.(ArgumentDeclarer); ok {
+		if argsErr != nil {
+			return usageExitCode, WriteArgsError(errorOutputWriter, argsErr)
+		}
+	} else if err := validateArgs(cmd, args); err != nil {
+		_, err = fmt.Fprintln(errorOutputWriter, err)
+		return usageExitCode, err
 	}
 
-	// Save command to history
-	if err := s.saveCommandHistory(userID, cmd.Name(), args); err != nil {
+	// builtins report failures on stderr, so any error output means a failed status
+	stderr := &errorOutputTracker{w: errorOutputWriter}
+	err := cmd.Execute(ctx, args, inputReader, outputWriter, stderr)
+	var exitStatus *ExitStatus
+	if errors.As(err, &exitStatus) {
+		return exitStatus.Code, nil
+	}
+	var exitShell *ExitShell
+	if errors.As(err, &exitShell) {
+		return exitShell.Code, err
+	}
+	if err != nil || stderr.written {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// helpRequested reports whether args ask for the documentation of cmd.
+// argsErr is the error of parsing them when cmd declares its arguments.
+func helpRequested(cmd Command, args []string, argsErr error) bool {
+	if _, ok := cmd.(ArgumentDeclarer); ok {
+		return errors.Is(argsErr, argparse.ErrHelp)
+	}
+	return isHelpRequested(args)
+}
+
+// writeCommandHelp writes the documentation of a builtin
+func writeCommandHelp(cmd Command, outputWriter, errorOutputWriter io.Writer) error {
+	err := WriteHelp(outputWriter, CommandDoc(cmd))
+	if err != nil {
+		_, err = fmt.Fprintf(errorOutputWriter, "error writing output: %v\n", err)
 		return err
 	}
 
-	return cmd.Execute(ctx, args, inputReader, outputWriter, errorOutputWriter)
+	return nil
 }
 
-// executeSystemCommand runs a system command.
-func (s *Service) executeSystemCommand(ctx context.Context, cmdName string, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+// executeSystemCommand runs the executable found for a system command.
+func (s *Service) executeSystemCommand(ctx context.Context, cmdName, cmdPath string, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
 	// Handle help flag
 	if isHelpRequested(args) {
 		_, err := fmt.Fprintf(outputWriter, "%s", s.systemCommand.Help())
@@ -135,18 +254,60 @@ func (s *Service) executeSystemCommand(ctx context.Context, cmdName string, args
 		return nil
 	}
 
-	// Get user ID
-	userID, err := s.getUserID()
+	return s.runAndRecord(ctx, cmdName, args, nil, func() (int, error) {
+		return s.systemCommand.Run(ctx, cmdPath, args, inputReader, outputWriter, errorOutputWriter)
+	})
+}
+
+// runAndRecord runs a command and saves it to history once it has finished,
+// together with its exit status and timing. secrets lists argument indices
+// that must not reach history.
+func (s *Service) runAndRecord(ctx context.Context, cmdName string, args []string, secrets []int, run func() (int, error)) error {
+	// the session is read first so the record has the directory and user the command started with
+	session, err := s.sessionRepo.GetSession()
 	if err != nil {
 		return err
 	}
 
-	// Save command to history
-	if err := s.saveCommandHistory(userID, cmdName, args); err != nil {
-		return err
+	startedAt := time.Now()
+	exitCode, err := run()
+	finishedAt := time.Now()
+
+	// a command that logs in is saved to the history of the user it logged in
+	// as, the guest history is cleared at login
+	if session.User == nil {
+		if current, getErr := s.sessionRepo.GetSession(); getErr == nil {
+			session.User = current.User
+		}
+	}
+
+	record := history.CommandHistory{
+		Command:    commandLine(ctx, cmdName, args, secrets),
+		WorkingDir: session.WorkingDir,
+		ExitCode:   exitCode,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		SessionID:  session.ID,
+		Pipeline:   pipeline(ctx, cmdName, args, secrets),
 	}
+	if saveErr := s.historySVC.SaveCommandHistory(sessionUserID(session), record); saveErr != nil && err == nil {
+		err = saveErr
+	}
+
+	return err
+}
+
+// errorOutputTracker records whether anything was written to the error output
+type errorOutputTracker struct {
+	w       io.Writer
+	written bool
+}
 
-	return s.systemCommand.Execute(ctx, cmdName, args, inputReader, outputWriter, errorOutputWriter)
+func (t *errorOutputTracker) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		t.written = true
+	}
+	return t.w.Write(p)
 }
 
 // Helper function to check if help is requested
@@ -164,20 +325,99 @@ func validateArgs(cmd Command, args []string) error {
 }
 
 // Retrieves the user ID from the session
-func (s *Service) getUserID() (*int64, error) {
-	session, err := s.sessionRepo.GetSession()
+func sessionUserID(session Session) *int64 {
+	if session.User != nil {
+		return &session.User.ID
+	}
+	return nil
+}
+
+// commandLineKey is the context key of the command line typed by the user
+type commandLineKey struct{}
+
+// WithCommandLine returns a context carrying the logical command line as typed,
+// possibly spanning several lines. It is saved to history in place of the
+// parsed command and arguments.
+func WithCommandLine(ctx context.Context, line string) context.Context {
+	return context.WithValue(ctx, commandLineKey{}, line)
+}
+
+// redactedArgument replaces secret arguments in history
+const redactedArgument = "********"
+
+// redactArgs returns a copy of args with the secret indices replaced
+func redactArgs(args []string, secrets []int) []string {
+	redacted := append([]string(nil), args...)
+	for _, i := range secrets {
+		if i >= 0 && i < len(redacted) {
+			redacted[i] = redactedArgument
+		}
+	}
+	return redacted
+}
+
+// commandLine returns the command as saved to history. When secrets lists
+// argument indices the typed command line is not used, so the secrets never
+// reach history, but its leading white space is kept: a command typed after
+// a space is not recorded.
+func commandLine(ctx context.Context, cmdName string, args []string, secrets []int) string {
+	line, ok := ctx.Value(commandLineKey{}).(string)
+	if !ok || len(secrets) > 0 {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		line = indent + strings.Join(append([]string{cmdName}, redactArgs(args, secrets)...), " ")
+	}
+	return line
+}
+
+// RedactedLine returns the typed line with the secret arguments of its
+// command redacted, as it may be kept in the line editor's history.
+func (s *Service) RedactedLine(line string) string {
+	parsed, err := inputprocessor.ParseArguments(line)
 	if err != nil {
-		return nil, err
+		return line
 	}
 
-	if session.User != nil {
-		return &session.User.ID, nil
+	var args []string
+	for i := 0; i < len(parsed); i++ {
+		if inputprocessor.IsRedirection(parsed[i]) {
+			i++
+			continue
+		}
+		args = append(args, parsed[i])
+	}
+	if len(args) == 0 {
+		return line
+	}
+
+	cmd, err := s.commandRepo.Get(args[0])
+	if err != nil {
+		return line
+	}
+	secretArgs, ok := cmd.(SecretArguments)
+	if !ok {
+		return line
 	}
-	return nil, nil
+	return commandLine(WithCommandLine(context.Background(), line), args[0], args[1:], secretArgs.SecretArguments(args[1:]))
 }
 
-// Saves the command execution to history
-func (s *Service) saveCommandHistory(userID *int64, cmdName string, args []string) error {
-	history := fmt.Sprintf("%s %s", cmdName, strings.Join(args, " "))
-	return s.historySVC.SaveCommandHistory(userID, history)
+// pipeline describes the executed command. Redirections are taken from the
+// typed command line since they are applied before the command runs.
+func pipeline(ctx context.Context, cmdName string, args []string, secrets []int) []history.PipelineStage {
+	stage := history.PipelineStage{
+		Name: cmdName,
+		Args: redactArgs(args, secrets),
+	}
+
+	if line, ok := ctx.Value(commandLineKey{}).(string); ok {
+		if parsed, err := inputprocessor.ParseArguments(line); err == nil {
+			for i := 0; i < len(parsed)-1; i++ {
+				if inputprocessor.IsRedirection(parsed[i]) {
+					stage.Redirections = append(stage.Redirections, parsed[i]+" "+parsed[i+1])
+					i++
+				}
+			}
+		}
+	}
+
+	return []history.PipelineStage{stage}
 }
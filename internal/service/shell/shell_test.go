@@ -0,0 +1,181 @@
+package shell_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Ali-Farhadnia/goshell/internal/service/history"
+	historyrepo "github.com/Ali-Farhadnia/goshell/internal/service/history/repository"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/commands"
+	"github.com/Ali-Farhadnia/goshell/internal/service/shell/repository"
+	"github.com/Ali-Farhadnia/goshell/internal/service/user"
+	userrepo "github.com/Ali-Farhadnia/goshell/internal/service/user/repository"
+	"github.com/Ali-Farhadnia/goshell/pkg/argparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const guestID = int64(-1)
+
+// newTestService returns a shell service recording the guest history in guestHistory
+func newTestService(t *testing.T, guestHistory *historyrepo.InMemoryRepository, cmds ...shell.Command) *shell.Service {
+	sessionRepo := repository.NewSessionRepository()
+	sessionRepo.SetSession(shell.Session{ID: "test", WorkingDir: t.TempDir(), Hash: shell.NewCommandHash()})
+
+	historySVC := history.New(historyrepo.NewInMemory(), guestHistory, guestID)
+	service := shell.NewService(historySVC, sessionRepo, repository.NewInMemoryCommandRepository(), shell.NewSystemCommand(sessionRepo, ""), "")
+	for _, cmd := range cmds {
+		require.NoError(t, service.RegisterCommand(cmd))
+	}
+	return service
+}
+
+// recordedCommands returns the commands in the guest history, newest first
+func recordedCommands(t *testing.T, guestHistory *historyrepo.InMemoryRepository) []string {
+	records, err := guestHistory.GetUserHistory(guestID, 0)
+	require.NoError(t, err)
+
+	var lines []string
+	for _, record := range records {
+		lines = append(lines, record.Command)
+	}
+	return lines
+}
+
+func TestService_ExecuteCommand_SecretArguments(t *testing.T) {
+	cases := []struct {
+		name             string
+		line             string
+		expectedCommands []string
+	}{
+		{
+			name:             "secret arguments are redacted",
+			line:             "adduser bob hunter2",
+			expectedCommands: []string{"adduser bob ********"},
+		},
+		{
+			name: "a leading space keeps the command out of history",
+			line: " adduser bob hunter2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := new(userrepo.UserRepositoryMock)
+			userRepo.On("FindUserByUsername", "bob").Return(nil, user.ErrUserNotFound)
+			userRepo.On("CreateUser", mock.Anything).Return(nil)
+
+			guestHistory := historyrepo.NewInMemory()
+			service := newTestService(t, guestHistory, commands.NewAddUserCommand(user.New(userRepo), nil))
+
+			args := strings.Fields(tc.line)
+			var outputBuffer, errorBuffer bytes.Buffer
+			ctx := shell.WithCommandLine(context.Background(), tc.line)
+			err := service.ExecuteCommand(ctx, args[0], args[1:], nil, &outputBuffer, &errorBuffer)
+
+			require.NoError(t, err)
+			assert.Empty(t, errorBuffer.String())
+			assert.Equal(t, tc.expectedCommands, recordedCommands(t, guestHistory))
+		})
+	}
+}
+
+func TestService_RedactedLine(t *testing.T) {
+	service := newTestService(t, historyrepo.NewInMemory(), commands.NewAddUserCommand(user.New(new(userrepo.UserRepositoryMock)), nil))
+
+	cases := []struct {
+		name     string
+		line     string
+		expected string
+	}{
+		{
+			name:     "secret arguments are redacted",
+			line:     `adduser bob "hunter 2"`,
+			expected: "adduser bob ********",
+		},
+		{
+			name:     "redirections are skipped",
+			line:     "adduser bob hunter2 > out.txt",
+			expected: "adduser bob ********",
+		},
+		{
+			name:     "a line without secrets is kept",
+			line:     "adduser bob --password-stdin < pass.txt",
+			expected: "adduser bob --password-stdin < pass.txt",
+		},
+		{
+			name:     "other commands are kept",
+			line:     "echo  hunter2",
+			expected: "echo  hunter2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, service.RedactedLine(tc.line))
+		})
+	}
+}
+
+func TestService_ExecuteCommand_Exit(t *testing.T) {
+	guestHistory := historyrepo.NewInMemory()
+	service := newTestService(t, guestHistory, commands.NewExitCommand())
+
+	var outputBuffer, errorBuffer bytes.Buffer
+	ctx := shell.WithCommandLine(context.Background(), "exit 3")
+	err := service.ExecuteCommand(ctx, "exit", []string{"3"}, nil, &outputBuffer, &errorBuffer)
+
+	// the shell exits after the command was recorded
+	assert.Equal(t, &shell.ExitShell{Code: 3}, err)
+	records, err := guestHistory.GetUserHistory(guestID, 0)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "exit 3", records[0].Command)
+	assert.Equal(t, 3, records[0].ExitCode)
+}
+
+// verboseCommand declares a -v flag and reads its arguments with an empty
+// spec, so that it only sees the flag when given the arguments already parsed
+type verboseCommand struct {
+	verbose bool
+	err     error
+}
+
+func (c *verboseCommand) Name() string      { return "verbose" }
+func (c *verboseCommand) MaxArguments() int { return -1 }
+func (c *verboseCommand) Help() string      { return "verbose [-v]" }
+
+func (c *verboseCommand) Arguments() argparse.Spec {
+	return argparse.Spec{Flags: []argparse.Flag{{Short: 'v', Type: argparse.Bool}}}
+}
+
+func (c *verboseCommand) Execute(ctx context.Context, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+	parsed, err := shell.ParseArguments(ctx, c.Name(), argparse.Spec{}, args)
+	if err != nil {
+		c.err = err
+		return nil
+	}
+	c.verbose = parsed.Bool("v")
+	return nil
+}
+
+func TestRunBuiltin_ParsesArgumentsOnce(t *testing.T) {
+	cmd := &verboseCommand{}
+	var outputBuffer, errorBuffer bytes.Buffer
+	code, err := shell.RunBuiltin(context.Background(), cmd, []string{"-v"}, nil, &outputBuffer, &errorBuffer)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+	assert.NoError(t, cmd.err)
+	assert.True(t, cmd.verbose)
+
+	// called directly, Execute parses the arguments itself
+	cmd = &verboseCommand{}
+	require.NoError(t, cmd.Execute(context.Background(), []string{"-v"}, nil, &outputBuffer, &errorBuffer))
+	assert.Error(t, cmd.err)
+}
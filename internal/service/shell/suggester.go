@@ -0,0 +1,35 @@
+package shell
+
+import (
+	"github.com/Ali-Farhadnia/goshell/internal/service/history"
+)
+
+// Suggester proposes autosuggestions from the current user's history
+type Suggester struct {
+	historySVC  *history.Service
+	sessionRepo SessionRepository
+}
+
+// NewSuggester creates a new history based suggester
+func NewSuggester(historySVC *history.Service, sessionRepo SessionRepository) *Suggester {
+	return &Suggester{
+		historySVC:  historySVC,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Suggest returns the most recent history entry extending line, preferring
+// entries run in the current working directory
+func (s *Suggester) Suggest(line string) string {
+	session, err := s.sessionRepo.GetSession()
+	if err != nil {
+		return ""
+	}
+
+	suggestion, err := s.historySVC.SuggestCommand(sessionUserID(session), line, session.WorkingDir)
+	if err != nil {
+		return ""
+	}
+
+	return suggestion
+}
@@ -2,10 +2,12 @@ package shell
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"syscall"
 
 	"github.com/Ali-Farhadnia/goshell/pkg/execpath"
 )
@@ -27,24 +29,48 @@ func NewSystemCommand(
 	}
 }
 
-// Execute runs the system command
-func (c *SystemCommand) Execute(ctx context.Context, cmdName string, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) error {
+// Exit statuses used when the command could not be run, as in POSIX shells
+const (
+	ExitCannotExecute = 126
+	ExitNotFound      = 127
+)
+
+// Execute runs the system command and returns its exit status
+func (c *SystemCommand) Execute(ctx context.Context, cmdName string, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) (int, error) {
+	// Get the current working directory from session
+	session, err := c.sessionRepo.GetSession()
+	if err != nil {
+		_, err := fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
+		return 1, err
+	}
+
 	// Check if it's an executable in $PATH
-	cmdPath, err := execpath.FindExecutable(cmdName, c.path)
+	cmdPath, err := execpath.FindExecutable(CommandPath(cmdName, session.WorkingDir), c.path)
 	if err != nil {
 		_, err := fmt.Fprintf(errorOutputWriter, "command not found: %s\n", cmdName)
-		return err
+		return ExitNotFound, err
 	}
 
-	// Get the current working directory from session
+	return c.run(ctx, session, cmdPath, args, inputReader, outputWriter, errorOutputWriter)
+}
+
+// Run runs the executable at cmdPath, which has already been looked up, and
+// returns its exit status
+func (c *SystemCommand) Run(ctx context.Context, cmdPath string, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) (int, error) {
 	session, err := c.sessionRepo.GetSession()
 	if err != nil {
 		_, err := fmt.Fprintf(errorOutputWriter, "error getting session: %v\n", err)
-		return err
+		return 1, err
 	}
 
-	// Prepare command execution
-	cmd := exec.CommandContext(ctx, cmdPath, args...)
+	return c.run(ctx, session, cmdPath, args, inputReader, outputWriter, errorOutputWriter)
+}
+
+// run executes cmdPath in the working directory of the session
+func (c *SystemCommand) run(ctx context.Context, session Session, cmdPath string, args []string, inputReader io.Reader, outputWriter, errorOutputWriter io.Writer) (int, error) {
+	// Prepare command execution. Ctrl-C reaches the program directly from the
+	// terminal and it decides how to react, so cancelling ctx does not kill it.
+	cmd := exec.CommandContext(context.WithoutCancel(ctx), cmdPath, args...)
 	cmd.Dir = session.WorkingDir
 	cmd.Env = os.Environ()
 
@@ -54,13 +80,23 @@ func (c *SystemCommand) Execute(ctx context.Context, cmdName string, args []stri
 	cmd.Stderr = errorOutputWriter
 
 	// Execute command
-	err = cmd.Run()
+	err := cmd.Run()
 	if err != nil {
+		exitCode := ExitCannotExecute
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+			// killed by a signal
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+				exitCode = 128 + int(status.Signal())
+			}
+		}
+
 		_, err := fmt.Fprintf(errorOutputWriter, "command execution failed: %v\n", err)
-		return err
+		return exitCode, err
 	}
 
-	return nil
+	return 0, nil
 }
 
 // Help returns the help text
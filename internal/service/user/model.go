@@ -17,3 +17,15 @@ type User struct {
 	PasswordHash *string    `json:"-"`
 	LastLogin    *time.Time `json:"last_login"`
 }
+
+// KeyBinding is a line editor key binding saved for a user
+type KeyBinding struct {
+	ID        int64     `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID int64  `gorm:"uniqueIndex:idx_key_bindings_user_key;not null;references:users(id)" json:"user_id"`
+	Keymap string `gorm:"uniqueIndex:idx_key_bindings_user_key;not null" json:"keymap"`
+	Key    string `gorm:"uniqueIndex:idx_key_bindings_user_key;not null" json:"key"`
+	Action string `gorm:"not null" json:"action"`
+}
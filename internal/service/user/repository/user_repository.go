@@ -7,6 +7,7 @@ import (
 	"github.com/Ali-Farhadnia/goshell/internal/database"
 	"github.com/Ali-Farhadnia/goshell/internal/service/user"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // repository implements the Repository interface
@@ -60,3 +61,24 @@ func (r *Repository) UpdateLastLogin(userID int64) error {
 		Where("id = ?", userID).
 		Update("last_login", now.LastLogin).Error
 }
+
+// ListKeyBindings lists the key bindings saved for a user
+func (r *Repository) ListKeyBindings(userID int64) ([]user.KeyBinding, error) {
+	var bindings []user.KeyBinding
+	result := r.db.Where("user_id = ?", userID).Order("keymap, key").Find(&bindings)
+	return bindings, result.Error
+}
+
+// SaveKeyBinding creates or replaces a key binding
+func (r *Repository) SaveKeyBinding(binding user.KeyBinding) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "keymap"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"action", "updated_at"}),
+	}).Create(&binding).Error
+}
+
+// DeleteKeyBinding deletes a key binding
+func (r *Repository) DeleteKeyBinding(userID int64, keymap, key string) error {
+	return r.db.Where("user_id = ? AND keymap = ? AND key = ?", userID, keymap, key).
+		Delete(&user.KeyBinding{}).Error
+}
@@ -39,3 +39,21 @@ func (m *UserRepositoryMock) UpdateLastLogin(userID int64) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
+
+func (m *UserRepositoryMock) ListKeyBindings(userID int64) ([]user.KeyBinding, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]user.KeyBinding), args.Error(1)
+}
+
+func (m *UserRepositoryMock) SaveKeyBinding(binding user.KeyBinding) error {
+	args := m.Called(binding)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) DeleteKeyBinding(userID int64, keymap, key string) error {
+	args := m.Called(userID, keymap, key)
+	return args.Error(0)
+}
@@ -18,6 +18,9 @@ type UserRepository interface {
 	UpdateUser(user User) error
 	ListUsers() ([]User, error)
 	UpdateLastLogin(userID int64) error
+	ListKeyBindings(userID int64) ([]KeyBinding, error)
+	SaveKeyBinding(binding KeyBinding) error
+	DeleteKeyBinding(userID int64, keymap, key string) error
 }
 
 // Service provides high-level functionality for the shell
@@ -74,6 +77,14 @@ func (s *Service) CreateUser(username, password string) (User, error) {
 
 // LoginUser logs in a user and updates last login time
 func (s *Service) LoginUser(username, password string) (User, error) {
+	return s.LoginUserFunc(username, func() (string, error) {
+		return password, nil
+	})
+}
+
+// LoginUserFunc logs in a user like LoginUser, calling readPassword only when
+// the account has a password, so callers can prompt for it lazily.
+func (s *Service) LoginUserFunc(username string, readPassword func() (string, error)) (User, error) {
 	user, err := s.userRepo.FindUserByUsername(username)
 	if err != nil {
 		return User{}, fmt.Errorf("error on finding user: %w", err)
@@ -81,6 +92,10 @@ func (s *Service) LoginUser(username, password string) (User, error) {
 
 	// Verify password if it is set
 	if user.PasswordHash != nil {
+		password, err := readPassword()
+		if err != nil {
+			return User{}, fmt.Errorf("failed to read password: %w", err)
+		}
 		if err := verifyPassword(*user.PasswordHash, password); err != nil {
 			return User{}, fmt.Errorf("invalid password")
 		}
@@ -99,6 +114,39 @@ func (s *Service) ListUsers() ([]User, error) {
 	return s.userRepo.ListUsers()
 }
 
+// KeyBindings returns the key bindings saved for a user as keymap to key to action
+func (s *Service) KeyBindings(userID int64) (map[string]map[string]string, error) {
+	bindings, err := s.userRepo.ListKeyBindings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keymaps := make(map[string]map[string]string)
+	for _, binding := range bindings {
+		if keymaps[binding.Keymap] == nil {
+			keymaps[binding.Keymap] = make(map[string]string)
+		}
+		keymaps[binding.Keymap][binding.Key] = binding.Action
+	}
+
+	return keymaps, nil
+}
+
+// BindKey saves a key binding for a user, replacing any previous binding of the key
+func (s *Service) BindKey(userID int64, keymap, key, action string) error {
+	return s.userRepo.SaveKeyBinding(KeyBinding{
+		UserID: userID,
+		Keymap: keymap,
+		Key:    key,
+		Action: action,
+	})
+}
+
+// UnbindKey removes a saved key binding of a user
+func (s *Service) UnbindKey(userID int64, keymap, key string) error {
+	return s.userRepo.DeleteKeyBinding(userID, keymap, key)
+}
+
 // hashPassword hashes a password using bcrypt
 func hashPassword(password string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -0,0 +1,418 @@
+package argparse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Type is the type of the value of a flag or positional argument.
+type Type int
+
+const (
+	// Bool is a flag that takes no value.
+	Bool Type = iota
+	// String is a value of any text.
+	String
+	// Int is a decimal integer.
+	Int
+)
+
+// ErrHelp is returned by Parse when --help is given before --.
+var ErrHelp = errors.New("help requested")
+
+// Flag declares a flag. Short is used as -x and can be combined with other
+// short flags, Long is used as --name, --name=value or --name value.
+type Flag struct {
+	Short       rune
+	Long        string
+	Type        Type
+	Value       string   // name of the value in usage lines and errors
+	Choices     []string // the values allowed, any when empty
+	Implied     string   // value of --name given alone, the others then follow =
+	Description string
+}
+
+// Positional declares a positional argument. Positional arguments are text
+// unless their Type is Int.
+type Positional struct {
+	Name        string
+	Type        Type
+	Optional    bool
+	Variadic    bool // takes the remaining arguments, only followed by required ones
+	Description string
+}
+
+// Subcommand declares a subcommand with its own arguments, such as list in
+// "history list -n 10".
+type Subcommand struct {
+	Name        string
+	Description string
+	Spec        Spec
+}
+
+// Spec declares the arguments of a command.
+type Spec struct {
+	Flags       []Flag
+	Positionals []Positional
+	Subcommands []Subcommand
+
+	// FlagsFirst ends the flags at the first positional argument, for
+	// commands whose operands are another command line. Otherwise flags and
+	// positional arguments may be mixed like in GNU tools.
+	FlagsFirst bool
+}
+
+// Error reports invalid arguments. Command is the command, followed by the
+// subcommand when there is one, and Usage the usage lines of it.
+type Error struct {
+	Command string
+	Usage   []string
+	Msg     string
+}
+
+func (e *Error) Error() string {
+	return e.Command + ": " + e.Msg
+}
+
+// Args holds parsed arguments. Flags and positional arguments are looked up
+// by name, the long name or the short letter of a flag.
+type Args struct {
+	Command    string   // the command name, followed by the subcommand
+	Subcommand string   // the subcommand given, empty when none
+	Operands   []string // the positional arguments in order
+
+	values      map[string][]string
+	positionals map[string][]string
+	order       map[string]int // the number of flags given when each one was last
+	given       int
+}
+
+// Parse parses args according to spec. Name is the command name used in
+// errors and usage lines.
+func Parse(name string, spec Spec, args []string) (*Args, error) {
+	a := &Args{
+		Command:     name,
+		values:      make(map[string][]string),
+		positionals: make(map[string][]string),
+		order:       make(map[string]int),
+	}
+	if err := a.parse(spec, args); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Bool reports whether the flag was given.
+func (a *Args) Bool(name string) bool {
+	return len(a.values[name]) > 0
+}
+
+// String returns the last value of the flag, or of the positional argument,
+// or an empty string when it was not given.
+func (a *Args) String(name string) string {
+	values := a.Strings(name)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// Strings returns every value of a repeated flag, or of a variadic positional
+// argument.
+func (a *Args) Strings(name string) []string {
+	if values, ok := a.values[name]; ok {
+		return values
+	}
+	return a.positionals[name]
+}
+
+// Int returns String(name) as an integer, 0 when it was not given. Values are
+// checked by Parse.
+func (a *Args) Int(name string) int {
+	n, _ := strconv.Atoi(a.String(name))
+	return n
+}
+
+// Last returns which of the flags named was given last, or an empty string
+// when none was, for flags overriding each other such as -t and -S of ls.
+func (a *Args) Last(names ...string) string {
+	last := ""
+	for _, name := range names {
+		if order, ok := a.order[name]; ok && (last == "" || order > a.order[last]) {
+			last = name
+		}
+	}
+	return last
+}
+
+// Has reports whether the flag or positional argument was given.
+func (a *Args) Has(name string) bool {
+	return len(a.Strings(name)) > 0
+}
+
+// parse parses the arguments of spec, continuing with the subcommand named
+// by the first positional argument when spec has subcommands
+func (a *Args) parse(spec Spec, args []string) error {
+	var operands []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			operands = append(operands, args[i+1:]...)
+			i = len(args)
+
+		case arg == "--help":
+			return ErrHelp
+
+		case strings.HasPrefix(arg, "--"):
+			consumed, err := a.parseLong(spec, args[i:])
+			if err != nil {
+				return a.errorf(spec, "%v", err)
+			}
+			i += consumed
+
+		case len(arg) > 1 && arg[0] == '-' && !spec.isNegativeNumber(arg):
+			consumed, err := a.parseShort(spec, args[i:])
+			if err != nil {
+				return a.errorf(spec, "%v", err)
+			}
+			i += consumed
+
+		default:
+			if len(spec.Subcommands) > 0 && len(operands) == 0 {
+				if sub := spec.subcommand(arg); sub != nil {
+					a.Subcommand = sub.Name
+					a.Command += " " + sub.Name
+					return a.parse(sub.Spec, args[i+1:])
+				}
+			}
+
+			operands = append(operands, arg)
+			if spec.FlagsFirst {
+				operands = append(operands, args[i+1:]...)
+				i = len(args)
+			}
+		}
+	}
+
+	return a.assign(spec, operands)
+}
+
+// parseLong parses the long flag at args[0] and returns how many of the
+// following arguments it consumed as its value
+func (a *Args) parseLong(spec Spec, args []string) (int, error) {
+	name, value, hasValue := strings.Cut(args[0][2:], "=")
+	flag := spec.long(name)
+	if flag == nil {
+		return 0, fmt.Errorf("unrecognized option '--%s'", name)
+	}
+
+	if flag.Type == Bool {
+		if hasValue {
+			return 0, fmt.Errorf("option '--%s' doesn't allow an argument", name)
+		}
+		return 0, a.set(flag, "true")
+	}
+
+	if hasValue {
+		return 0, a.set(flag, value)
+	}
+	if flag.Implied != "" {
+		return 0, a.set(flag, flag.Implied)
+	}
+	if len(args) < 2 {
+		return 0, fmt.Errorf("option '--%s' requires an argument", name)
+	}
+	return 1, a.set(flag, args[1])
+}
+
+// parseShort parses the short flags combined in args[0] and returns how many
+// of the following arguments were consumed as a value
+func (a *Args) parseShort(spec Spec, args []string) (int, error) {
+	shorts := args[0][1:]
+
+	for j, r := range shorts {
+		flag := spec.short(r)
+		if flag == nil {
+			return 0, fmt.Errorf("invalid option -- '%c'", r)
+		}
+
+		if flag.Type == Bool {
+			if err := a.set(flag, "true"); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		// the rest of the argument, or else the next one, is the value
+		if rest := shorts[j+len(string(r)):]; rest != "" {
+			return 0, a.set(flag, rest)
+		}
+		if len(args) < 2 {
+			return 0, fmt.Errorf("option requires an argument -- '%c'", r)
+		}
+		return 1, a.set(flag, args[1])
+	}
+
+	return 0, nil
+}
+
+// set checks and stores a value of flag
+func (a *Args) set(flag *Flag, value string) error {
+	if err := check(flag.Type, flag.Choices, flag.valueName(), value); err != nil {
+		return err
+	}
+
+	a.given++
+	for _, key := range flag.keys() {
+		a.values[key] = append(a.values[key], value)
+		a.order[key] = a.given
+	}
+	return nil
+}
+
+// assign matches the operands with the positional arguments of spec
+func (a *Args) assign(spec Spec, operands []string) error {
+	a.Operands = operands
+
+	if len(spec.Subcommands) > 0 && len(spec.Positionals) == 0 && len(operands) > 0 {
+		return a.errorf(spec, "invalid command '%s'", operands[0])
+	}
+
+	next := 0
+	for i, positional := range spec.Positionals {
+		var values []string
+		switch {
+		case positional.Variadic && next < len(operands):
+			// the positional arguments after it take the last operands, as
+			// in "cp <source> ... <dest>"
+			end := max(len(operands)-(len(spec.Positionals)-1-i), next+1)
+			values = operands[next:end]
+		case next < len(operands):
+			values = operands[next : next+1]
+		}
+		next += len(values)
+
+		if len(values) == 0 {
+			if !positional.Optional {
+				return a.errorf(spec, "missing %s", positional.Name)
+			}
+			continue
+		}
+
+		for _, value := range values {
+			if err := check(positional.Type, nil, positional.Name, value); err != nil {
+				return a.errorf(spec, "%v", err)
+			}
+		}
+		a.positionals[positional.Name] = values
+	}
+
+	if next < len(operands) {
+		return a.errorf(spec, "too many arguments")
+	}
+
+	return nil
+}
+
+// errorf returns an Error for the command being parsed
+func (a *Args) errorf(spec Spec, format string, args ...any) error {
+	return &Error{
+		Command: a.Command,
+		Usage:   Synopsis(a.Command, spec),
+		Msg:     fmt.Sprintf(format, args...),
+	}
+}
+
+// check reports whether value is valid for typ and choices. name is the name
+// of the value used in the error.
+func check(typ Type, choices []string, name, value string) error {
+	if typ == Int {
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("invalid %s: %s", name, value)
+		}
+	}
+
+	if len(choices) > 0 {
+		for _, choice := range choices {
+			if value == choice {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid %s: %s", name, value)
+	}
+
+	return nil
+}
+
+// long returns the flag named --name
+func (s Spec) long(name string) *Flag {
+	for i := range s.Flags {
+		if s.Flags[i].Long != "" && s.Flags[i].Long == name {
+			return &s.Flags[i]
+		}
+	}
+	return nil
+}
+
+// short returns the flag named -r
+func (s Spec) short(r rune) *Flag {
+	for i := range s.Flags {
+		if s.Flags[i].Short != 0 && s.Flags[i].Short == r {
+			return &s.Flags[i]
+		}
+	}
+	return nil
+}
+
+// subcommand returns the subcommand called name
+func (s Spec) subcommand(name string) *Subcommand {
+	for i := range s.Subcommands {
+		if s.Subcommands[i].Name == name {
+			return &s.Subcommands[i]
+		}
+	}
+	return nil
+}
+
+// isNegativeNumber reports whether arg is a negative number to be taken as
+// an operand, which is the case unless a short flag is a digit
+func (s Spec) isNegativeNumber(arg string) bool {
+	if _, err := strconv.Atoi(arg); err != nil {
+		return false
+	}
+	for _, flag := range s.Flags {
+		if flag.Short >= '0' && flag.Short <= '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// keys returns the names the flag is looked up by
+func (f *Flag) keys() []string {
+	var keys []string
+	if f.Long != "" {
+		keys = append(keys, f.Long)
+	}
+	if f.Short != 0 {
+		keys = append(keys, string(f.Short))
+	}
+	return keys
+}
+
+// valueName returns the name of the value of the flag
+func (f *Flag) valueName() string {
+	switch {
+	case f.Value != "":
+		return f.Value
+	case f.Type == Int:
+		return "number"
+	default:
+		return "value"
+	}
+}
@@ -0,0 +1,193 @@
+package argparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSpec = Spec{
+	Flags: []Flag{
+		{Short: 'a', Long: "all"},
+		{Short: 'l'},
+		{Short: 'n', Long: "limit", Type: Int, Value: "limit"},
+		{Long: "format", Type: String, Choices: []string{"json", "csv"}},
+	},
+	Positionals: []Positional{
+		{Name: "pattern"},
+		{Name: "file", Optional: true, Variadic: true},
+	},
+}
+
+var subcommandSpec = Spec{
+	Flags: []Flag{{Short: 'n', Type: Int, Value: "limit"}},
+	Subcommands: []Subcommand{
+		{Name: "list", Spec: Spec{Flags: []Flag{{Short: 'n', Type: Int, Value: "limit"}}}},
+		{Name: "import", Spec: Spec{
+			Flags:       []Flag{{Short: 'f', Long: "format", Type: String, Choices: []string{"bash", "zsh"}}},
+			Positionals: []Positional{{Name: "file"}},
+		}},
+	},
+}
+
+func TestParse(t *testing.T) {
+	args, err := Parse("cmd", testSpec, []string{"-al", "foo", "--limit=5", "a.txt", "--format", "csv", "-n3", "b.txt"})
+	require.NoError(t, err)
+
+	assert.True(t, args.Bool("all"))
+	assert.True(t, args.Bool("a"))
+	assert.True(t, args.Bool("l"))
+	assert.Equal(t, 3, args.Int("limit"))
+	assert.Equal(t, []string{"5", "3"}, args.Strings("n"))
+	assert.Equal(t, "csv", args.String("format"))
+	assert.Equal(t, "foo", args.String("pattern"))
+	assert.Equal(t, []string{"a.txt", "b.txt"}, args.Strings("file"))
+	assert.Equal(t, []string{"foo", "a.txt", "b.txt"}, args.Operands)
+	assert.False(t, args.Has("missing"))
+}
+
+func TestParse_DoubleDashAndNegativeNumbers(t *testing.T) {
+	args, err := Parse("cmd", testSpec, []string{"-n", "-2", "--", "-a", "--help"})
+	require.NoError(t, err)
+	assert.Equal(t, -2, args.Int("n"))
+	assert.False(t, args.Bool("a"))
+	assert.Equal(t, []string{"-a", "--help"}, args.Operands)
+
+	args, err = Parse("exit", Spec{Positionals: []Positional{{Name: "code", Type: Int, Optional: true}}}, []string{"-1"})
+	require.NoError(t, err)
+	assert.Equal(t, -1, args.Int("code"))
+}
+
+func TestParse_FlagsFirst(t *testing.T) {
+	spec := Spec{
+		Flags:       []Flag{{Short: 'p'}},
+		Positionals: []Positional{{Name: "command"}, {Name: "args", Optional: true, Variadic: true}},
+		FlagsFirst:  true,
+	}
+
+	args, err := Parse("command", spec, []string{"-p", "ls", "-l", "--help"})
+	require.NoError(t, err)
+	assert.True(t, args.Bool("p"))
+	assert.Equal(t, "ls", args.String("command"))
+	assert.Equal(t, []string{"-l", "--help"}, args.Strings("args"))
+}
+
+func TestParse_VariadicBeforeLast(t *testing.T) {
+	spec := Spec{Positionals: []Positional{{Name: "source", Variadic: true}, {Name: "dest"}}}
+
+	args, err := Parse("cp", spec, []string{"a", "b", "dir"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, args.Strings("source"))
+	assert.Equal(t, "dir", args.String("dest"))
+
+	_, err = Parse("cp", spec, []string{"a"})
+	assert.EqualError(t, err, "cp: missing dest")
+	assert.Equal(t, []string{"cp <source> ... <dest>"}, err.(*Error).Usage)
+}
+
+func TestParse_Subcommands(t *testing.T) {
+	args, err := Parse("history", subcommandSpec, []string{"import", "-f", "zsh", "file"})
+	require.NoError(t, err)
+	assert.Equal(t, "import", args.Subcommand)
+	assert.Equal(t, "history import", args.Command)
+	assert.Equal(t, "zsh", args.String("format"))
+	assert.Equal(t, "file", args.String("file"))
+
+	args, err = Parse("history", subcommandSpec, []string{"-n", "4"})
+	require.NoError(t, err)
+	assert.Empty(t, args.Subcommand)
+	assert.Equal(t, 4, args.Int("n"))
+}
+
+func TestParse_ImpliedValue(t *testing.T) {
+	spec := Spec{
+		Flags:       []Flag{{Long: "color", Type: String, Implied: "always", Choices: []string{"always", "never"}}},
+		Positionals: []Positional{{Name: "file", Optional: true}},
+	}
+
+	args, err := Parse("ls", spec, []string{"--color", "never"})
+	require.NoError(t, err)
+	assert.Equal(t, "always", args.String("color"))
+	assert.Equal(t, "never", args.String("file"))
+
+	args, err = Parse("ls", spec, []string{"--color=never"})
+	require.NoError(t, err)
+	assert.Equal(t, "never", args.String("color"))
+
+	assert.Equal(t, []string{"ls [--color[=always|never]] [file]"}, Synopsis("ls", spec))
+	assert.Equal(t, "--color[=always|never]", spec.Flags[0].Usage())
+}
+
+func TestArgs_Last(t *testing.T) {
+	spec := Spec{Flags: []Flag{{Short: 't'}, {Short: 'S', Long: "size"}, {Short: 'r'}}}
+
+	args, err := Parse("ls", spec, []string{"-tS", "-r", "-t"})
+	require.NoError(t, err)
+	assert.Equal(t, "t", args.Last("t", "S"))
+
+	args, err = Parse("ls", spec, []string{"-t", "--size"})
+	require.NoError(t, err)
+	assert.Equal(t, "S", args.Last("t", "S"))
+	assert.Equal(t, "size", args.Last("t", "size"))
+
+	args, err = Parse("ls", spec, []string{"-r"})
+	require.NoError(t, err)
+	assert.Empty(t, args.Last("t", "S"))
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     Spec
+		args     []string
+		expected string
+	}{
+		{"unknown short", testSpec, []string{"-ax", "p"}, "cmd: invalid option -- 'x'"},
+		{"unknown long", testSpec, []string{"--nope", "p"}, "cmd: unrecognized option '--nope'"},
+		{"bool with value", testSpec, []string{"--all=yes", "p"}, "cmd: option '--all' doesn't allow an argument"},
+		{"missing long value", testSpec, []string{"p", "--limit"}, "cmd: option '--limit' requires an argument"},
+		{"missing short value", testSpec, []string{"p", "-n"}, "cmd: option requires an argument -- 'n'"},
+		{"invalid int", testSpec, []string{"-n", "abc", "p"}, "cmd: invalid limit: abc"},
+		{"invalid choice", testSpec, []string{"--format=xml", "p"}, "cmd: invalid value: xml"},
+		{"missing positional", testSpec, []string{"-a"}, "cmd: missing pattern"},
+		{"too many", Spec{}, []string{"extra"}, "cmd: too many arguments"},
+		{"invalid command", subcommandSpec, []string{"nope"}, "cmd: invalid command 'nope'"},
+		{"subcommand error", subcommandSpec, []string{"import"}, "cmd import: missing file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("cmd", tt.spec, tt.args)
+			require.Error(t, err)
+			assert.Equal(t, tt.expected, err.Error())
+
+			var argsErr *Error
+			assert.ErrorAs(t, err, &argsErr)
+			assert.NotEmpty(t, argsErr.Usage)
+		})
+	}
+}
+
+func TestParse_Help(t *testing.T) {
+	_, err := Parse("cmd", testSpec, []string{"p", "--help"})
+	assert.ErrorIs(t, err, ErrHelp)
+
+	_, err = Parse("history", subcommandSpec, []string{"list", "--help"})
+	assert.ErrorIs(t, err, ErrHelp)
+}
+
+func TestSynopsis(t *testing.T) {
+	assert.Equal(t,
+		[]string{"cmd [-al] [-n <limit>] [--format json|csv] <pattern> [file ...]"},
+		Synopsis("cmd", testSpec))
+	assert.Equal(t,
+		[]string{"history [-n <limit>]", "history list [-n <limit>]", "history import [-f bash|zsh] <file>"},
+		Synopsis("history", subcommandSpec))
+}
+
+func TestFlagUsage(t *testing.T) {
+	assert.Equal(t, "-a, --all", testSpec.Flags[0].Usage())
+	assert.Equal(t, "-n, --limit <limit>", testSpec.Flags[2].Usage())
+	assert.Equal(t, "--format json|csv", testSpec.Flags[3].Usage())
+}
@@ -0,0 +1,85 @@
+package argparse
+
+import (
+	"strings"
+)
+
+// Synopsis returns the usage lines of name: one for spec itself followed by
+// those of its subcommands.
+func Synopsis(name string, spec Spec) []string {
+	parts := []string{name}
+
+	// boolean short flags are shown combined, as in [-abc]
+	shorts := ""
+	for _, flag := range spec.Flags {
+		if flag.Type == Bool && flag.Short != 0 {
+			shorts += string(flag.Short)
+		}
+	}
+	if shorts != "" {
+		parts = append(parts, "[-"+shorts+"]")
+	}
+
+	for _, flag := range spec.Flags {
+		switch {
+		case flag.Type == Bool && flag.Short != 0:
+		case flag.Type == Bool:
+			parts = append(parts, "[--"+flag.Long+"]")
+		case flag.Implied != "":
+			parts = append(parts, "[--"+flag.Long+"[="+flag.placeholder()+"]]")
+		case flag.Short != 0:
+			parts = append(parts, "[-"+string(flag.Short)+" "+flag.placeholder()+"]")
+		default:
+			parts = append(parts, "[--"+flag.Long+" "+flag.placeholder()+"]")
+		}
+	}
+
+	for _, positional := range spec.Positionals {
+		switch {
+		case positional.Optional && positional.Variadic:
+			parts = append(parts, "["+positional.Name+" ...]")
+		case positional.Optional:
+			parts = append(parts, "["+positional.Name+"]")
+		case positional.Variadic:
+			parts = append(parts, "<"+positional.Name+"> ...")
+		default:
+			parts = append(parts, "<"+positional.Name+">")
+		}
+	}
+
+	lines := []string{strings.Join(parts, " ")}
+	for _, sub := range spec.Subcommands {
+		lines = append(lines, Synopsis(name+" "+sub.Name, sub.Spec)...)
+	}
+	return lines
+}
+
+// Usage returns the flag as shown in help, such as "-n, --limit <limit>".
+func (f Flag) Usage() string {
+	var names []string
+	if f.Short != 0 {
+		names = append(names, "-"+string(f.Short))
+	}
+	if f.Long != "" {
+		names = append(names, "--"+f.Long)
+	}
+
+	usage := strings.Join(names, ", ")
+	switch {
+	case f.Type == Bool:
+	case f.Implied != "":
+		usage += "[=" + f.placeholder() + "]"
+	default:
+		usage += " " + f.placeholder()
+	}
+	return usage
+}
+
+// placeholder returns the value of the flag in usage lines, its choices or
+// its name in angle brackets
+func (f *Flag) placeholder() string {
+	if len(f.Choices) > 0 {
+		return strings.Join(f.Choices, "|")
+	}
+	return "<" + f.valueName() + ">"
+}
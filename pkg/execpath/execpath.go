@@ -4,22 +4,98 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 // findExecutable searches for the executable in the system's PATH.
+// A name containing a slash, such as ./build.sh, is not searched for: it
+// is the path of the executable, relative to the current directory.
 func FindExecutable(cmd string, path string) (string, error) { //Capitalized function name.
+	if strings.ContainsRune(cmd, '/') {
+		if IsExecutable(cmd) {
+			return cmd, nil
+		}
+		return "", fmt.Errorf("command not found: %s", cmd)
+	}
+
 	paths := strings.Split(path, string(os.PathListSeparator))
 
 	for _, dir := range paths {
 		exePath := filepath.Join(dir, cmd)
 
 		// Check if the file exists and is executable
-		info, err := os.Stat(exePath)
-		if err == nil && !info.IsDir() && (info.Mode()&0111 != 0) { // Check if executable bit is set
+		if IsExecutable(exePath) {
 			return exePath, nil
 		}
 	}
 
 	return "", fmt.Errorf("command not found: %s", cmd)
 }
+
+// FindAllExecutables returns every executable named cmd in the directories
+// of path, in PATH order. A directory listed twice is searched once. Like
+// with FindExecutable, a name containing a slash is not searched for.
+func FindAllExecutables(cmd string, path string) []string {
+	if strings.ContainsRune(cmd, '/') {
+		if IsExecutable(cmd) {
+			return []string{cmd}
+		}
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var found []string
+
+	for _, dir := range strings.Split(path, string(os.PathListSeparator)) {
+		exePath := filepath.Join(dir, cmd)
+		if seen[exePath] {
+			continue
+		}
+		seen[exePath] = true
+
+		if IsExecutable(exePath) {
+			found = append(found, exePath)
+		}
+	}
+
+	return found
+}
+
+// IsExecutable reports whether path is a file with an executable bit set,
+// following symlinks
+func IsExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.Mode()&0111 != 0
+}
+
+// ListExecutables returns the names of all executables found in the
+// directories of path, sorted and without duplicates.
+func ListExecutables(path string) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	for _, dir := range strings.Split(path, string(os.PathListSeparator)) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if seen[entry.Name()] {
+				continue
+			}
+
+			// Stat follows symlinks, which are common in PATH directories
+			if !IsExecutable(filepath.Join(dir, entry.Name())) {
+				continue
+			}
+
+			seen[entry.Name()] = true
+			names = append(names, entry.Name())
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,72 @@
+package fuzzy
+
+import "sort"
+
+// Distance returns the Damerau-Levenshtein distance between a and b (optimal
+// string alignment variant): the number of insertions, deletions,
+// substitutions and transpositions of adjacent characters needed to turn a
+// into b.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// three rows are enough: the transposition looks two rows back
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return prev[len(rb)]
+}
+
+// Closest returns the candidates within maxDistance of name, nearest first
+// and alphabetically among equally distant ones. Duplicates and name itself
+// are skipped.
+func Closest(name string, candidates []string, maxDistance int) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	var matches []match
+	for _, candidate := range candidates {
+		if candidate == name || seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		if d := Distance(name, candidate); d <= maxDistance {
+			matches = append(matches, match{candidate: candidate, distance: d})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].candidate < matches[j].candidate
+	})
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.candidate
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"git", "git", 0},
+		{"", "ls", 2},
+		{"gti", "git", 1},
+		{"sl", "ls", 1},
+		{"pyhton", "python", 1},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 3},
+		{"héllo", "hello", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Distance(tt.a, tt.b))
+			assert.Equal(t, tt.expected, Distance(tt.b, tt.a))
+		})
+	}
+}
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"git", "gist", "grep", "gzip", "git", "cat", "gti"}
+
+	assert.Equal(t, []string{"git", "gzip"}, Closest("gti", candidates, 2))
+	assert.Equal(t, []string{"git"}, Closest("gti", candidates, 1))
+	assert.Empty(t, Closest("docker", candidates, 2))
+}
@@ -0,0 +1,213 @@
+package inputprocessor
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrIncompleteInput is matched by errors reporting that more lines are
+// needed to complete the command.
+var ErrIncompleteInput = errors.New("incomplete input")
+
+// IncompleteInputError reports why the input is not a complete command yet.
+type IncompleteInputError struct {
+	Reason string
+}
+
+func (e *IncompleteInputError) Error() string {
+	return e.Reason
+}
+
+// Unwrap allows errors.Is(err, ErrIncompleteInput).
+func (e *IncompleteInputError) Unwrap() error {
+	return ErrIncompleteInput
+}
+
+// hereDoc is a here-document waiting for its delimiter line.
+type hereDoc struct {
+	delimiter string
+	stripTabs bool
+}
+
+// CheckComplete reports whether input, which may span several lines, forms a
+// complete command. It returns an *IncompleteInputError for open quotes,
+// a trailing backslash, a trailing |, && or ||, an unclosed if or { group and
+// a here-document whose delimiter line has not been read yet.
+func CheckComplete(input string) error {
+	var pending []hereDoc
+	var command strings.Builder
+
+	for _, line := range strings.Split(input, "\n") {
+		if len(pending) > 0 {
+			text := line
+			if pending[0].stripTabs {
+				text = strings.TrimLeft(text, "\t")
+			}
+			if text == pending[0].delimiter {
+				pending = pending[1:]
+			}
+			continue
+		}
+
+		lineStart := command.Len()
+		if lineStart > 0 {
+			command.WriteByte('\n')
+			lineStart++
+		}
+		command.WriteString(line)
+		pending = append(pending, hereDocs(command.String(), lineStart)...)
+	}
+
+	if len(pending) > 0 {
+		return &IncompleteInputError{Reason: "unterminated here-document"}
+	}
+
+	text := command.String()
+	tokens := Tokenize(text)
+	for _, tok := range tokens {
+		if tok.Kind == TokenUnterminated {
+			return &IncompleteInputError{Reason: "unterminated quote detected"}
+		}
+	}
+
+	if hasLineContinuation(text) {
+		return &IncompleteInputError{Reason: "line continuation"}
+	}
+
+	if n := len(tokens); n > 0 && tokens[n-1].Kind == TokenWord {
+		last := tokens[n-1].Text
+		if strings.HasSuffix(last, "&&") || (strings.HasSuffix(last, "|") && !strings.HasSuffix(last, `\|`)) {
+			return &IncompleteInputError{Reason: "unfinished command list"}
+		}
+	}
+
+	ifDepth, braceDepth := 0, 0
+	for _, word := range commandWords(text, tokens) {
+		switch word {
+		case "if":
+			ifDepth++
+		case "fi":
+			ifDepth--
+		case "{":
+			braceDepth++
+		case "}":
+			braceDepth--
+		}
+	}
+	if ifDepth > 0 {
+		return &IncompleteInputError{Reason: "unclosed if"}
+	}
+	if braceDepth > 0 {
+		return &IncompleteInputError{Reason: "unclosed brace group"}
+	}
+
+	return nil
+}
+
+// JoinLines appends a continuation line to the input read so far. A trailing
+// backslash is removed together with the line break, as in POSIX shells.
+func JoinLines(input, line string) string {
+	if hasLineContinuation(input) {
+		return input[:len(input)-1] + line
+	}
+	return input + "\n" + line
+}
+
+// hasLineContinuation reports whether input ends with an unescaped backslash.
+func hasLineContinuation(input string) bool {
+	n := 0
+	for i := len(input) - 1; i >= 0 && input[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// hereDocs returns the here-documents started by operators at or after offset start.
+func hereDocs(input string, start int) []hereDoc {
+	var docs []hereDoc
+	args := splitArgs(Tokenize(input))
+
+	for i, arg := range args {
+		if len(arg) == 0 || arg[0].Start < start || arg[0].Kind != TokenWord || !strings.HasPrefix(arg[0].Text, "<<") || strings.HasPrefix(arg[0].Text, "<<<") {
+			continue
+		}
+
+		op := arg[0].Text
+		delimiter := argText(arg)[2:]
+		stripTabs := strings.HasPrefix(op, "<<-")
+		if stripTabs {
+			delimiter = delimiter[1:]
+		}
+		if delimiter == "" {
+			if i+1 == len(args) {
+				continue
+			}
+			delimiter = argText(args[i+1])
+		}
+
+		docs = append(docs, hereDoc{
+			delimiter: strings.NewReplacer(`"`, "", `'`, "", `\`, "").Replace(delimiter),
+			stripTabs: stripTabs,
+		})
+	}
+
+	return docs
+}
+
+// commandWords returns the unquoted words standing in command position, where
+// reserved words such as if, fi, { and } are recognized.
+func commandWords(input string, tokens []Token) []string {
+	var words []string
+	commandPosition := true
+	prevEnd := 0
+
+	for _, arg := range splitArgs(tokens) {
+		if strings.Contains(input[prevEnd:arg[0].Start], "\n") {
+			commandPosition = true
+		}
+		prevEnd = arg[len(arg)-1].End
+
+		word := argText(arg)
+		plain := len(arg) == 1 && arg[0].Kind == TokenWord
+		trimmed := strings.TrimSuffix(word, ";")
+
+		if plain && commandPosition {
+			words = append(words, trimmed)
+		}
+
+		switch {
+		case !plain:
+			commandPosition = false
+		case strings.HasSuffix(word, ";"):
+			commandPosition = true
+		case trimmed == "|" || trimmed == "||" || trimmed == "&&" || trimmed == "{" ||
+			trimmed == "then" || trimmed == "else" || trimmed == "do" || trimmed == "if":
+			commandPosition = true
+		default:
+			commandPosition = false
+		}
+	}
+
+	return words
+}
+
+// splitArgs groups tokens by the argument they belong to.
+func splitArgs(tokens []Token) [][]Token {
+	var args [][]Token
+	for i, tok := range tokens {
+		if i == 0 || tokens[i-1].Arg != tok.Arg {
+			args = append(args, nil)
+		}
+		args[len(args)-1] = append(args[len(args)-1], tok)
+	}
+	return args
+}
+
+// argText returns the source text of an argument.
+func argText(arg []Token) string {
+	var b strings.Builder
+	for _, tok := range arg {
+		b.WriteString(tok.Text)
+	}
+	return b.String()
+}
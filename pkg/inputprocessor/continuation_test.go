@@ -0,0 +1,64 @@
+package inputprocessor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckComplete(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		reason string
+	}{
+		{name: "simple command", input: "echo hello", reason: ""},
+		{name: "open quote", input: `echo "hello`, reason: "unterminated quote detected"},
+		{name: "quote closed on next line", input: "echo \"hello\nworld\"", reason: ""},
+		{name: "trailing backslash", input: `ls -la \`, reason: "line continuation"},
+		{name: "escaped backslash", input: `echo a\\`, reason: ""},
+		{name: "trailing pipe", input: "cat file |", reason: "unfinished command list"},
+		{name: "trailing and", input: "make &&", reason: "unfinished command list"},
+		{name: "trailing or", input: "make ||", reason: "unfinished command list"},
+		{name: "quoted pipe", input: `echo "|"`, reason: ""},
+		{name: "unclosed if", input: "if true; then\necho yes", reason: "unclosed if"},
+		{name: "closed if", input: "if true; then\necho yes\nfi", reason: ""},
+		{name: "if as an argument", input: "echo if", reason: ""},
+		{name: "unclosed brace group", input: "{ echo a", reason: "unclosed brace group"},
+		{name: "closed brace group", input: "{ echo a; }", reason: ""},
+		{name: "pending here-doc", input: "cat <<EOF\nline", reason: "unterminated here-document"},
+		{name: "finished here-doc", input: "cat <<EOF\nline\nEOF", reason: ""},
+		{name: "quoted here-doc delimiter", input: "cat << \"END\"\nEND", reason: ""},
+		{name: "here-doc with tab stripping", input: "cat <<-EOF\n\tline\n\tEOF", reason: ""},
+		{name: "quote inside here-doc body", input: "cat <<EOF\n\"\nEOF", reason: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckComplete(tt.input)
+
+			if tt.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, ErrIncompleteInput))
+			assert.EqualError(t, err, tt.reason)
+		})
+	}
+}
+
+func TestJoinLines(t *testing.T) {
+	assert.Equal(t, "ls -la", JoinLines(`ls \`, "-la"))
+	assert.Equal(t, "echo \"a\nb\"", JoinLines(`echo "a`, `b"`))
+	assert.Equal(t, "echo a\\\\\nb", JoinLines(`echo a\\`, "b"))
+}
+
+func TestParseArgumentsIncomplete(t *testing.T) {
+	_, err := ParseArguments(`echo "hello`)
+	assert.True(t, errors.Is(err, ErrIncompleteInput))
+
+	args, err := ParseArguments("echo \"a\nb\"\nc")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"echo", "a\nb", "c"}, args)
+}
@@ -50,7 +50,7 @@ func ParseArguments(input string) ([]string, error) {
 			continue
 		}
 
-		if (c == ' ' || c == '\t') && !inQuotes {
+		if (c == ' ' || c == '\t' || c == '\n') && !inQuotes {
 			// End
 			if current.Len() > 0 {
 				args = append(args, current.String())
@@ -68,7 +68,7 @@ func ParseArguments(input string) ([]string, error) {
 	}
 
 	if inQuotes {
-		return nil, fmt.Errorf("unterminated quote detected")
+		return nil, &IncompleteInputError{Reason: "unterminated quote detected"}
 	}
 
 	return args, nil
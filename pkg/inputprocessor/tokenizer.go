@@ -0,0 +1,175 @@
+package inputprocessor
+
+// TokenKind classifies a span of the input line.
+type TokenKind int
+
+const (
+	// TokenWord is an unquoted part of an argument.
+	TokenWord TokenKind = iota
+	// TokenString is a double-quoted part of an argument, including the quotes.
+	TokenString
+	// TokenVariable is an environment variable reference such as $HOME or ${HOME}.
+	TokenVariable
+	// TokenRedirection is a redirection operator understood by ProcessRedirections.
+	TokenRedirection
+	// TokenUnterminated is a quoted part of an argument that is never closed.
+	TokenUnterminated
+)
+
+// Token is a span of the input line. Start and End are byte offsets into the
+// input and Arg is the index of the argument the span belongs to.
+type Token struct {
+	Kind  TokenKind
+	Text  string
+	Start int
+	End   int
+	Arg   int
+}
+
+// redirectionOperators lists the operators handled by ProcessRedirections.
+var redirectionOperators = map[string]bool{
+	">":   true,
+	">>":  true,
+	"<":   true,
+	"2>":  true,
+	"2>>": true,
+}
+
+// IsRedirection reports whether arg is a redirection operator.
+func IsRedirection(arg string) bool {
+	return redirectionOperators[arg]
+}
+
+// Tokenize splits the input into classified spans using the same quoting and
+// escaping rules as ParseArguments. Unlike ParseArguments it never fails:
+// incomplete input is reported through TokenUnterminated spans so it can be
+// used while the line is still being typed.
+func Tokenize(input string) []Token {
+	var tokens []Token
+	arg := 0
+	argHasContent := false
+	inQuotes := false
+	quoteStart := 0 // index into tokens of the first span of the open quote
+	segStart := -1
+	segKind := TokenWord
+
+	flush := func(end int) {
+		if segStart >= 0 && end > segStart {
+			tokens = append(tokens, Token{Kind: segKind, Text: input[segStart:end], Start: segStart, End: end, Arg: arg})
+		}
+		segStart = -1
+	}
+	begin := func(i int, kind TokenKind) {
+		if segStart < 0 {
+			segStart = i
+			segKind = kind
+		}
+		argHasContent = true
+	}
+
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+
+		if c == '\\' {
+			kind := TokenWord
+			if inQuotes {
+				kind = TokenString
+			}
+			begin(i, kind)
+			i++ // skip the escaped character
+			continue
+		}
+
+		if c == '"' {
+			if inQuotes {
+				// The closing quote belongs to the string span
+				begin(i, TokenString)
+				flush(i + 1)
+				inQuotes = false
+				continue
+			}
+			flush(i)
+			quoteStart = len(tokens)
+			inQuotes = true
+			begin(i, TokenString)
+			continue
+		}
+
+		if c == '$' {
+			if end := variableEnd(input, i); end > i+1 {
+				flush(i)
+				argHasContent = true
+				tokens = append(tokens, Token{Kind: TokenVariable, Text: input[i:end], Start: i, End: end, Arg: arg})
+				i = end - 1
+				continue
+			}
+		}
+
+		if (c == ' ' || c == '\t' || c == '\n') && !inQuotes {
+			flush(i)
+			if argHasContent {
+				arg++
+				argHasContent = false
+			}
+			continue
+		}
+
+		kind := TokenWord
+		if inQuotes {
+			kind = TokenString
+		}
+		begin(i, kind)
+	}
+
+	flush(len(input))
+
+	if inQuotes {
+		for i := quoteStart; i < len(tokens); i++ {
+			if tokens[i].Kind == TokenString {
+				tokens[i].Kind = TokenUnterminated
+			}
+		}
+	}
+
+	// An argument made of a single word equal to an operator is a redirection
+	for i, tok := range tokens {
+		if tok.Kind != TokenWord || !IsRedirection(tok.Text) {
+			continue
+		}
+		if (i == 0 || tokens[i-1].Arg != tok.Arg) && (i == len(tokens)-1 || tokens[i+1].Arg != tok.Arg) {
+			tokens[i].Kind = TokenRedirection
+		}
+	}
+
+	return tokens
+}
+
+// variableEnd returns the end offset of the variable reference starting at
+// input[start], which must be a '$'. It returns start when there is none.
+func variableEnd(input string, start int) int {
+	i := start + 1
+	if i < len(input) && input[i] == '{' {
+		for j := i + 1; j < len(input); j++ {
+			if input[j] == '}' {
+				return j + 1
+			}
+			if !isNameChar(input[j]) {
+				return start
+			}
+		}
+		return start
+	}
+
+	for i < len(input) && isNameChar(input[i]) {
+		i++
+	}
+	if i == start+1 {
+		return start
+	}
+	return i
+}
+
+// isNameChar reports whether c may appear in an environment variable name.
+func isNameChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
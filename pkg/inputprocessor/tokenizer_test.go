@@ -0,0 +1,89 @@
+package inputprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Token
+	}{
+		{
+			name:  "simple command",
+			input: "ls -la",
+			expected: []Token{
+				{Kind: TokenWord, Text: "ls", Start: 0, End: 2, Arg: 0},
+				{Kind: TokenWord, Text: "-la", Start: 3, End: 6, Arg: 1},
+			},
+		},
+		{
+			name:  "quoted string with variable",
+			input: `echo "hi $USER"`,
+			expected: []Token{
+				{Kind: TokenWord, Text: "echo", Start: 0, End: 4, Arg: 0},
+				{Kind: TokenString, Text: `"hi `, Start: 5, End: 9, Arg: 1},
+				{Kind: TokenVariable, Text: "$USER", Start: 9, End: 14, Arg: 1},
+				{Kind: TokenString, Text: `"`, Start: 14, End: 15, Arg: 1},
+			},
+		},
+		{
+			name:  "braced variable",
+			input: "echo ${HOME}/x",
+			expected: []Token{
+				{Kind: TokenWord, Text: "echo", Start: 0, End: 4, Arg: 0},
+				{Kind: TokenVariable, Text: "${HOME}", Start: 5, End: 12, Arg: 1},
+				{Kind: TokenWord, Text: "/x", Start: 12, End: 14, Arg: 1},
+			},
+		},
+		{
+			name:  "escaped dollar is not a variable",
+			input: `echo \$HOME`,
+			expected: []Token{
+				{Kind: TokenWord, Text: "echo", Start: 0, End: 4, Arg: 0},
+				{Kind: TokenWord, Text: `\$HOME`, Start: 5, End: 11, Arg: 1},
+			},
+		},
+		{
+			name:  "redirection",
+			input: "cat < in.txt 2>> err.log",
+			expected: []Token{
+				{Kind: TokenWord, Text: "cat", Start: 0, End: 3, Arg: 0},
+				{Kind: TokenRedirection, Text: "<", Start: 4, End: 5, Arg: 1},
+				{Kind: TokenWord, Text: "in.txt", Start: 6, End: 12, Arg: 2},
+				{Kind: TokenRedirection, Text: "2>>", Start: 13, End: 16, Arg: 3},
+				{Kind: TokenWord, Text: "err.log", Start: 17, End: 24, Arg: 4},
+			},
+		},
+		{
+			name:  "operator inside a word is not a redirection",
+			input: "echo a>b",
+			expected: []Token{
+				{Kind: TokenWord, Text: "echo", Start: 0, End: 4, Arg: 0},
+				{Kind: TokenWord, Text: "a>b", Start: 5, End: 8, Arg: 1},
+			},
+		},
+		{
+			name:  "unterminated quote",
+			input: `echo "hello wor`,
+			expected: []Token{
+				{Kind: TokenWord, Text: "echo", Start: 0, End: 4, Arg: 0},
+				{Kind: TokenUnterminated, Text: `"hello wor`, Start: 5, End: 15, Arg: 1},
+			},
+		},
+		{
+			name:     "empty input",
+			input:    "   ",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Tokenize(tt.input))
+		})
+	}
+}
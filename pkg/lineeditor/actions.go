@@ -0,0 +1,119 @@
+package lineeditor
+
+import (
+	"io"
+)
+
+// runAction applies an editing action to the line. It reports whether the
+// line is finished.
+func (e *Editor) runAction(st *lineState, action string) (bool, error) {
+	switch action {
+	case "accept-line":
+		st.pos = len(st.buf)
+		st.suggestion = nil
+		st.suggestedFor = string(st.buf)
+		e.refresh(st)
+		io.WriteString(e.out, "\r\n")
+		return true, nil
+
+	case "interrupt":
+		io.WriteString(e.out, "^C\r\n")
+		st.buf = nil
+		return true, ErrInterrupted
+
+	case "delete-char-or-eof":
+		if len(st.buf) == 0 {
+			return true, io.EOF
+		}
+		st.delete(st.pos, st.pos+1)
+
+	case "beginning-of-line":
+		st.pos = 0
+	case "end-of-line":
+		if !st.acceptSuggestion(false) {
+			st.pos = len(st.buf)
+		}
+	case "backward-char":
+		st.pos = max(st.pos-1, 0)
+	case "forward-char":
+		if !st.acceptSuggestion(false) {
+			st.pos = min(st.pos+1, len(st.buf))
+		}
+	case "backward-word":
+		st.pos = st.wordStart()
+	case "forward-word":
+		if !st.acceptSuggestion(true) {
+			st.pos = st.wordEnd()
+		}
+
+	case "backward-delete-char":
+		st.delete(st.pos-1, st.pos)
+	case "delete-char":
+		st.delete(st.pos, st.pos+1)
+	case "kill-line":
+		e.killed = st.delete(st.pos, len(st.buf))
+	case "unix-line-discard":
+		e.killed = st.delete(0, st.pos)
+	case "unix-word-rubout":
+		e.killed = st.delete(st.fieldStart(), st.pos)
+	case "backward-kill-word":
+		e.killed = st.delete(st.wordStart(), st.pos)
+	case "kill-word":
+		e.killed = st.delete(st.pos, st.wordEnd())
+	case "yank":
+		st.insert(e.killed...)
+
+	case "transpose-chars":
+		if st.pos == 0 || len(st.buf) < 2 {
+			break
+		}
+		if st.pos == len(st.buf) {
+			st.pos--
+		}
+		st.buf[st.pos-1], st.buf[st.pos] = st.buf[st.pos], st.buf[st.pos-1]
+		st.pos++
+
+	case "clear-screen":
+		io.WriteString(e.out, "\x1b[H\x1b[2J")
+
+	case "previous-history":
+		e.moveHistory(st, -1)
+	case "next-history":
+		e.moveHistory(st, 1)
+
+	case "vi-movement-mode":
+		if e.mode == ModeVi {
+			e.enterViCommandMode(st)
+		}
+	case "vi-editing-mode":
+		e.mode = ModeVi
+		st.vi = viState{}
+	case "emacs-editing-mode":
+		e.mode = ModeEmacs
+		st.vi = viState{}
+
+	case "edit-and-execute-command":
+		return e.editAndExecute(st)
+	}
+
+	return false, nil
+}
+
+// moveHistory replaces the line with an older (delta < 0) or newer history entry.
+func (e *Editor) moveHistory(st *lineState, delta int) {
+	next := st.historyIndex + delta
+	if next < 0 || next > len(e.history) {
+		return
+	}
+
+	if st.historyIndex == len(e.history) {
+		st.saved = append([]rune(nil), st.buf...)
+	}
+	st.historyIndex = next
+
+	if next == len(e.history) {
+		st.setLine(st.saved)
+		return
+	}
+	st.setLine([]rune(e.history[next]))
+}
@@ -0,0 +1,125 @@
+package lineeditor
+
+import "unicode"
+
+// lineState holds the line being edited and the cursor position within it.
+type lineState struct {
+	prompt       string
+	buf          []rune
+	pos          int
+	historyIndex int
+	saved        []rune // line being edited before history navigation started
+	suggestion   []rune // autosuggestion for the line, including the line itself
+	suggestedFor string // line the suggestion was computed for
+	vi           viState
+}
+
+// insert inserts runes at the cursor.
+func (s *lineState) insert(r ...rune) {
+	buf := make([]rune, 0, len(s.buf)+len(r))
+	buf = append(buf, s.buf[:s.pos]...)
+	buf = append(buf, r...)
+	buf = append(buf, s.buf[s.pos:]...)
+	s.buf = buf
+	s.pos += len(r)
+}
+
+// delete removes the runes between from and to and returns them.
+func (s *lineState) delete(from, to int) []rune {
+	if from > to {
+		from, to = to, from
+	}
+	from = max(from, 0)
+	to = min(to, len(s.buf))
+	if from >= to {
+		return nil
+	}
+
+	removed := append([]rune(nil), s.buf[from:to]...)
+	s.buf = append(s.buf[:from], s.buf[to:]...)
+	if s.pos > to {
+		s.pos -= to - from
+	} else if s.pos > from {
+		s.pos = from
+	}
+	return removed
+}
+
+// setLine replaces the whole line and moves the cursor to its end.
+func (s *lineState) setLine(line []rune) {
+	s.buf = append([]rune(nil), line...)
+	s.pos = len(s.buf)
+}
+
+// suggestionSuffix returns the part of the suggestion not yet typed. It is
+// only shown while the cursor is at the end of the line.
+func (s *lineState) suggestionSuffix() string {
+	if s.pos != len(s.buf) || len(s.suggestion) <= len(s.buf) || string(s.suggestion[:len(s.buf)]) != string(s.buf) {
+		return ""
+	}
+	return string(s.suggestion[len(s.buf):])
+}
+
+// acceptSuggestion appends the rest of the suggestion to the line. When
+// word is true only the next word of it is accepted.
+func (s *lineState) acceptSuggestion(word bool) bool {
+	suffix := []rune(s.suggestionSuffix())
+	if len(suffix) == 0 {
+		return false
+	}
+
+	if word {
+		i := 0
+		for i < len(suffix) && !isWordRune(suffix[i]) {
+			i++
+		}
+		for i < len(suffix) && isWordRune(suffix[i]) {
+			i++
+		}
+		suffix = suffix[:i]
+	}
+
+	s.insert(suffix...)
+	return true
+}
+
+// wordStart returns the start of the word before the cursor.
+func (s *lineState) wordStart() int {
+	i := s.pos
+	for i > 0 && !isWordRune(s.buf[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(s.buf[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEnd returns the end of the word after the cursor.
+func (s *lineState) wordEnd() int {
+	i := s.pos
+	for i < len(s.buf) && !isWordRune(s.buf[i]) {
+		i++
+	}
+	for i < len(s.buf) && isWordRune(s.buf[i]) {
+		i++
+	}
+	return i
+}
+
+// fieldStart returns the start of the whitespace-delimited field before the cursor.
+func (s *lineState) fieldStart() int {
+	i := s.pos
+	for i > 0 && unicode.IsSpace(s.buf[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(s.buf[i-1]) {
+		i--
+	}
+	return i
+}
+
+// isWordRune reports whether r is part of a word for word motions.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
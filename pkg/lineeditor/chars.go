@@ -0,0 +1,107 @@
+package lineeditor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ReadChars displays the prompt and reads characters without line editing
+// until delim, which is included in the result, or until count characters
+// were read when count is positive. Typed characters are echoed unless echo
+// is false; backspace deletes the previous one unless count is set. It
+// returns io.EOF when the input ends first, ErrInterrupted when the user
+// presses Ctrl-C and, when the deadline passes, what was read with
+// os.ErrDeadlineExceeded.
+func (e *Editor) ReadChars(prompt string, count int, delim rune, echo bool) (string, error) {
+	if !e.IsTerminal() {
+		return e.readPlainChars(prompt, count, delim)
+	}
+
+	oldState, err := term.MakeRaw(int(e.in.Fd()))
+	if err != nil {
+		return e.readPlainChars(prompt, count, delim)
+	}
+	defer term.Restore(int(e.in.Fd()), oldState)
+
+	io.WriteString(e.out, prompt)
+
+	var buf []rune
+	for count <= 0 || len(buf) < count {
+		if err := e.waitInput(); err != nil {
+			e.endChars(buf, echo)
+			return string(buf), err
+		}
+
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return string(buf), err
+		}
+
+		switch {
+		case r == 0x03:
+			io.WriteString(e.out, "^C\r\n")
+			return string(buf), ErrInterrupted
+		case r == 0x04:
+			if len(buf) == 0 {
+				e.endChars(buf, echo)
+				return "", io.EOF
+			}
+			continue
+		case (r == 0x7f || r == 0x08) && count <= 0:
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+				if echo {
+					io.WriteString(e.out, "\b \b")
+				}
+			}
+			continue
+		case r == '\r':
+			r = '\n'
+		}
+
+		buf = append(buf, r)
+		if echo {
+			io.WriteString(e.out, strings.ReplaceAll(string(r), "\n", "\r\n"))
+		}
+		if r == delim {
+			break
+		}
+	}
+
+	e.endChars(buf, echo)
+	return string(buf), nil
+}
+
+// endChars moves to the next line after ReadChars unless the newline ending
+// the input was echoed, so that what is printed next starts on its own line.
+func (e *Editor) endChars(buf []rune, echo bool) {
+	if !echo || len(buf) == 0 || buf[len(buf)-1] != '\n' {
+		io.WriteString(e.out, "\r\n")
+	}
+}
+
+// readPlainChars reads characters without a terminal.
+func (e *Editor) readPlainChars(prompt string, count int, delim rune) (string, error) {
+	fmt.Fprint(e.out, prompt)
+
+	var buf []rune
+	for count <= 0 || len(buf) < count {
+		if err := e.waitInput(); err != nil {
+			return string(buf), err
+		}
+
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return string(buf), err
+		}
+		buf = append(buf, r)
+		if r == delim {
+			break
+		}
+	}
+
+	return string(buf), nil
+}
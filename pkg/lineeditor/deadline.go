@@ -0,0 +1,36 @@
+package lineeditor
+
+import (
+	"os"
+	"time"
+)
+
+// SetDeadline makes reads fail with os.ErrDeadlineExceeded once t has
+// passed, returning what was read so far. The zero time removes the deadline.
+func (e *Editor) SetDeadline(t time.Time) {
+	e.deadline = t
+}
+
+// Input returns the file the editor reads from.
+func (e *Editor) Input() *os.File {
+	return e.in
+}
+
+// waitInput waits until input is available or the deadline has passed.
+// Without a deadline it returns at once and the read that follows blocks.
+func (e *Editor) waitInput() error {
+	if e.deadline.IsZero() || e.reader.Buffered() > 0 {
+		return nil
+	}
+
+	for {
+		remaining := time.Until(e.deadline)
+		if remaining <= 0 {
+			return os.ErrDeadlineExceeded
+		}
+		ready, err := pollInput(e.in, remaining)
+		if err != nil || ready {
+			return err
+		}
+	}
+}
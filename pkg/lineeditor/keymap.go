@@ -0,0 +1,218 @@
+package lineeditor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Editing modes
+const (
+	ModeEmacs = "emacs"
+	ModeVi    = "vi"
+)
+
+// Keymaps that bindings can be added to
+const (
+	KeymapEmacs     = "emacs"
+	KeymapViInsert  = "vi-insert"
+	KeymapViCommand = "vi-command"
+)
+
+// emacsKeymap maps key names to the editing action they trigger.
+var emacsKeymap = map[string]string{
+	"enter":         "accept-line",
+	"ctrl-c":        "interrupt",
+	"ctrl-d":        "delete-char-or-eof",
+	"ctrl-a":        "beginning-of-line",
+	"home":          "beginning-of-line",
+	"ctrl-e":        "end-of-line",
+	"end":           "end-of-line",
+	"ctrl-b":        "backward-char",
+	"left":          "backward-char",
+	"ctrl-f":        "forward-char",
+	"right":         "forward-char",
+	"alt-b":         "backward-word",
+	"alt-left":      "backward-word",
+	"ctrl-left":     "backward-word",
+	"alt-f":         "forward-word",
+	"alt-right":     "forward-word",
+	"ctrl-right":    "forward-word",
+	"backspace":     "backward-delete-char",
+	"delete":        "delete-char",
+	"ctrl-k":        "kill-line",
+	"ctrl-u":        "unix-line-discard",
+	"ctrl-w":        "unix-word-rubout",
+	"alt-backspace": "backward-kill-word",
+	"alt-d":         "kill-word",
+	"ctrl-y":        "yank",
+	"ctrl-t":        "transpose-chars",
+	"ctrl-l":        "clear-screen",
+	"up":            "previous-history",
+	"ctrl-p":        "previous-history",
+	"down":          "next-history",
+	"ctrl-n":        "next-history",
+}
+
+// viInsertKeymap is the keymap used while typing in vi insert mode.
+var viInsertKeymap = map[string]string{
+	"enter":     "accept-line",
+	"ctrl-c":    "interrupt",
+	"ctrl-d":    "delete-char-or-eof",
+	"escape":    "vi-movement-mode",
+	"backspace": "backward-delete-char",
+	"delete":    "delete-char",
+	"left":      "backward-char",
+	"right":     "forward-char",
+	"home":      "beginning-of-line",
+	"end":       "end-of-line",
+	"up":        "previous-history",
+	"down":      "next-history",
+	"ctrl-w":    "unix-word-rubout",
+	"ctrl-u":    "unix-line-discard",
+	"ctrl-l":    "clear-screen",
+}
+
+// actionNames lists every action that keys can be bound to.
+var actionNames = []string{
+	"accept-line",
+	"backward-char",
+	"backward-delete-char",
+	"backward-kill-word",
+	"backward-word",
+	"beginning-of-line",
+	"clear-screen",
+	"delete-char",
+	"delete-char-or-eof",
+	"edit-and-execute-command",
+	"emacs-editing-mode",
+	"end-of-line",
+	"forward-char",
+	"forward-word",
+	"interrupt",
+	"kill-line",
+	"kill-word",
+	"next-history",
+	"previous-history",
+	"transpose-chars",
+	"unix-line-discard",
+	"unix-word-rubout",
+	"vi-editing-mode",
+	"vi-movement-mode",
+	"yank",
+}
+
+// namedKeys lists the key names produced by the key decoder.
+var namedKeys = map[string]bool{
+	"enter": true, "tab": true, "backspace": true, "escape": true, "space": true,
+	"up": true, "down": true, "left": true, "right": true, "home": true, "end": true,
+	"insert": true, "delete": true, "page-up": true, "page-down": true,
+}
+
+// Actions returns the names of all bindable actions.
+func Actions() []string {
+	return append([]string(nil), actionNames...)
+}
+
+// DefaultBindings returns the built-in bindings of a keymap.
+func DefaultBindings(keymap string) map[string]string {
+	var defaults map[string]string
+	switch keymap {
+	case KeymapEmacs:
+		defaults = emacsKeymap
+	case KeymapViInsert:
+		defaults = viInsertKeymap
+	}
+
+	bindings := make(map[string]string, len(defaults))
+	for key, action := range defaults {
+		bindings[key] = action
+	}
+	return bindings
+}
+
+// ValidateKeymap checks that keymap is one of the known keymaps.
+func ValidateKeymap(keymap string) error {
+	if keymap != KeymapEmacs && keymap != KeymapViInsert && keymap != KeymapViCommand {
+		return fmt.Errorf("unknown keymap: %s", keymap)
+	}
+	return nil
+}
+
+// ValidateBinding checks that a binding refers to a known keymap, key and action.
+func ValidateBinding(keymap, key, action string) error {
+	if err := ValidateKeymap(keymap); err != nil {
+		return err
+	}
+	if !validKey(key) {
+		return fmt.Errorf("unknown key: %s", key)
+	}
+
+	i := sort.SearchStrings(actionNames, action)
+	if i == len(actionNames) || actionNames[i] != action {
+		return fmt.Errorf("unknown action: %s", action)
+	}
+
+	return nil
+}
+
+// validKey reports whether name is a key name such as "ctrl-a", "alt-right" or "x".
+func validKey(name string) bool {
+	for _, prefix := range []string{"ctrl-", "alt-", "shift-"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" {
+			return validKey(rest)
+		}
+	}
+	return namedKeys[name] || utf8.RuneCountInString(name) == 1
+}
+
+// SetMode switches between the emacs and vi editing modes.
+func (e *Editor) SetMode(mode string) error {
+	if mode != ModeEmacs && mode != ModeVi {
+		return fmt.Errorf("unknown editing mode: %s", mode)
+	}
+	e.mode = mode
+	return nil
+}
+
+// Mode returns the current editing mode.
+func (e *Editor) Mode() string {
+	return e.mode
+}
+
+// SetBindings replaces the custom key bindings, given as keymap to key to action.
+func (e *Editor) SetBindings(bindings map[string]map[string]string) error {
+	custom := make(map[string]map[string]string, len(bindings))
+	for keymap, keys := range bindings {
+		for key, action := range keys {
+			if err := ValidateBinding(keymap, key, action); err != nil {
+				return err
+			}
+			if custom[keymap] == nil {
+				custom[keymap] = make(map[string]string)
+			}
+			custom[keymap][key] = action
+		}
+	}
+
+	e.bindings = custom
+	return nil
+}
+
+// lookup returns the action bound to a key in a keymap.
+func (e *Editor) lookup(keymap, key string) (string, bool) {
+	if action, ok := e.bindings[keymap][key]; ok {
+		return action, true
+	}
+
+	switch keymap {
+	case KeymapEmacs:
+		action, ok := emacsKeymap[key]
+		return action, ok
+	case KeymapViInsert:
+		action, ok := viInsertKeymap[key]
+		return action, ok
+	}
+	return "", false
+}
@@ -0,0 +1,167 @@
+package lineeditor
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Key is a decoded key press. Name is empty for printable characters, in
+// which case Rune holds the character.
+type Key struct {
+	Name string
+	Rune rune
+}
+
+// csiKeys maps the final byte of a CSI or SS3 sequence to a key name.
+var csiKeys = map[byte]string{
+	'A': "up",
+	'B': "down",
+	'C': "right",
+	'D': "left",
+	'H': "home",
+	'F': "end",
+	'Z': "shift-tab",
+}
+
+// tildeKeys maps the numeric parameter of a "CSI n ~" sequence to a key name.
+var tildeKeys = map[int]string{
+	1: "home",
+	2: "insert",
+	3: "delete",
+	4: "end",
+	5: "page-up",
+	6: "page-down",
+	7: "home",
+	8: "end",
+}
+
+// readKey reads and decodes a single key press from a terminal in raw mode.
+func readKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch {
+	case b == '\r' || b == '\n':
+		return Key{Name: "enter"}, nil
+	case b == '\t':
+		return Key{Name: "tab"}, nil
+	case b == 0x7f || b == 0x08:
+		return Key{Name: "backspace"}, nil
+	case b == 0x1b:
+		return readEscape(r)
+	case b == 0:
+		return Key{Name: "ctrl-space"}, nil
+	case b < 0x20:
+		return Key{Name: "ctrl-" + string(rune('a'+b-1))}, nil
+	}
+
+	if b < utf8.RuneSelf {
+		return Key{Rune: rune(b)}, nil
+	}
+
+	// Multi-byte UTF-8 character
+	if err := r.UnreadByte(); err != nil {
+		return Key{}, err
+	}
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{Rune: ch}, nil
+}
+
+// readEscape decodes the bytes following an ESC. Terminals send escape
+// sequences in a single write, so an ESC with nothing buffered after it is
+// treated as a lone escape key press.
+func readEscape(r *bufio.Reader) (Key, error) {
+	if r.Buffered() == 0 {
+		return Key{Name: "escape"}, nil
+	}
+
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch b {
+	case '[':
+		return readCSI(r)
+	case 'O':
+		final, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		if name, ok := csiKeys[final]; ok {
+			return Key{Name: name}, nil
+		}
+		return Key{Name: "unknown"}, nil
+	}
+
+	// ESC followed by another key is the Alt (meta) modifier
+	if err := r.UnreadByte(); err != nil {
+		return Key{}, err
+	}
+	key, err := readKey(r)
+	if err != nil {
+		return Key{}, err
+	}
+	if key.Name == "escape" {
+		return key, nil
+	}
+	if key.Name == "" {
+		return Key{Name: "alt-" + string(key.Rune)}, nil
+	}
+	return Key{Name: "alt-" + strings.TrimPrefix(key.Name, "alt-")}, nil
+}
+
+// readCSI decodes a "CSI params final" sequence such as "\x1b[1;3C".
+func readCSI(r *bufio.Reader) (Key, error) {
+	var params strings.Builder
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		if b >= 0x40 && b <= 0x7e {
+			return csiKey(params.String(), b), nil
+		}
+		params.WriteByte(b)
+	}
+}
+
+// csiKey names a CSI sequence from its parameters and final byte.
+func csiKey(params string, final byte) Key {
+	parts := strings.Split(params, ";")
+
+	name := ""
+	if final == '~' {
+		n, _ := strconv.Atoi(parts[0])
+		name = tildeKeys[n]
+	} else {
+		name = csiKeys[final]
+	}
+	if name == "" {
+		return Key{Name: "unknown"}
+	}
+
+	// xterm encodes modifiers as 1 + (shift=1 | alt=2 | ctrl=4)
+	if len(parts) > 1 {
+		mod, _ := strconv.Atoi(parts[1])
+		mod--
+		if mod&4 != 0 {
+			name = "ctrl-" + name
+		}
+		if mod&2 != 0 {
+			name = "alt-" + name
+		}
+		if mod&1 != 0 {
+			name = "shift-" + name
+		}
+	}
+
+	return Key{Name: name}
+}
@@ -0,0 +1,233 @@
+package lineeditor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+// Highlighter colours the line being edited. The returned string must have
+// the same printable width as the line; only escape sequences may be added.
+type Highlighter interface {
+	Highlight(line string) string
+}
+
+// Suggester proposes a completion of the line being edited, shown greyed
+// out after the cursor. The suggestion must start with the line; an empty
+// string means there is nothing to suggest.
+type Suggester interface {
+	Suggest(line string) string
+}
+
+// Editor reads lines from a terminal with in-place editing. When the input
+// is not a terminal it falls back to reading plain lines.
+type Editor struct {
+	in           *os.File
+	out          io.Writer
+	reader       *bufio.Reader
+	highlighter  Highlighter
+	suggester    Suggester
+	history      []string
+	killed       []rune
+	mode         string
+	bindings     map[string]map[string]string
+	termState    *term.State
+	viLastChange []Key
+	deadline     time.Time
+}
+
+// New creates a new line editor reading from in and drawing on out
+func New(in *os.File, out io.Writer) *Editor {
+	return &Editor{
+		in:     in,
+		out:    out,
+		reader: bufio.NewReader(in),
+		mode:   ModeEmacs,
+	}
+}
+
+// SetHighlighter sets the highlighter used to colour the line while typing.
+func (e *Editor) SetHighlighter(h Highlighter) {
+	e.highlighter = h
+}
+
+// SetSuggester sets the source of autosuggestions shown while typing.
+func (e *Editor) SetSuggester(s Suggester) {
+	e.suggester = s
+}
+
+// AddHistory appends a line to the history used by the up and down keys.
+func (e *Editor) AddHistory(line string) {
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+	e.history = append(e.history, line)
+}
+
+// IsTerminal reports whether the editor reads from a terminal.
+func (e *Editor) IsTerminal() bool {
+	return term.IsTerminal(int(e.in.Fd()))
+}
+
+// ReadLine displays the prompt and reads a line. It returns io.EOF when the
+// input is closed and ErrInterrupted when the user presses Ctrl-C. When the
+// deadline passes it returns what was typed with os.ErrDeadlineExceeded.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	if !e.IsTerminal() {
+		return e.readPlainLine(prompt)
+	}
+
+	oldState, err := term.MakeRaw(int(e.in.Fd()))
+	if err != nil {
+		return e.readPlainLine(prompt)
+	}
+	e.termState = oldState
+	defer term.Restore(int(e.in.Fd()), oldState)
+
+	st := &lineState{prompt: prompt, historyIndex: len(e.history)}
+	e.refresh(st)
+
+	for {
+		if err := e.waitInput(); err != nil {
+			io.WriteString(e.out, "\r\n")
+			return string(st.buf), err
+		}
+
+		key, err := readKey(e.reader)
+		if err != nil {
+			return "", err
+		}
+
+		done, err := e.handleKey(st, key)
+		if err != nil || done {
+			return string(st.buf), err
+		}
+		e.refresh(st)
+	}
+}
+
+// handleKey applies a key press to the line. It reports whether the line is finished.
+func (e *Editor) handleKey(st *lineState, key Key) (bool, error) {
+	if e.mode == ModeVi && st.vi.command {
+		return e.handleViCommandKey(st, key)
+	}
+
+	keymap := KeymapEmacs
+	if e.mode == ModeVi {
+		keymap = KeymapViInsert
+		if st.vi.recordingInsert {
+			st.vi.recording = append(st.vi.recording, key)
+		}
+	}
+
+	action, ok := e.lookup(keymap, keyName(key))
+	if !ok {
+		if key.Name == "" {
+			st.insert(key.Rune)
+		}
+		return false, nil
+	}
+
+	return e.runAction(st, action)
+}
+
+// keyName returns the name used to bind a key.
+func keyName(key Key) string {
+	switch {
+	case key.Name != "":
+		return key.Name
+	case key.Rune == ' ':
+		return "space"
+	}
+	return string(key.Rune)
+}
+
+// readPlainLine reads a line without editing support.
+func (e *Editor) readPlainLine(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+
+	var line []byte
+	for {
+		if err := e.waitInput(); err != nil {
+			return string(line), err
+		}
+
+		b, err := e.reader.ReadByte()
+		if err != nil {
+			if !errors.Is(err, io.EOF) || len(line) == 0 {
+				return "", err
+			}
+			break
+		}
+		if b == '\n' {
+			break
+		}
+		line = append(line, b)
+	}
+
+	return strings.TrimRight(string(line), "\r"), nil
+}
+
+// refresh redraws the prompt and line and places the cursor.
+func (e *Editor) refresh(st *lineState) {
+	line := string(st.buf)
+	e.updateSuggestion(st, line)
+
+	display := line
+	if e.highlighter != nil {
+		display = e.highlighter.Highlight(line)
+	}
+
+	var b strings.Builder
+	b.WriteString("\r")
+	b.WriteString(st.prompt)
+	b.WriteString(display)
+
+	back := len(st.buf) - st.pos
+	if hint := st.suggestionSuffix(); hint != "" {
+		b.WriteString("\x1b[90m")
+		b.WriteString(hint)
+		b.WriteString("\x1b[0m")
+		back += len([]rune(hint))
+	}
+
+	b.WriteString("\x1b[K")
+	if back > 0 {
+		fmt.Fprintf(&b, "\x1b[%dD", back)
+	}
+
+	io.WriteString(e.out, b.String())
+}
+
+// updateSuggestion asks the suggester for a new suggestion when the line changed.
+func (e *Editor) updateSuggestion(st *lineState, line string) {
+	if e.suggester == nil || line == st.suggestedFor {
+		return
+	}
+
+	st.suggestedFor = line
+
+	// Typing along the current suggestion keeps it without another lookup
+	if strings.HasPrefix(string(st.suggestion), line) && len(st.suggestion) > len([]rune(line)) {
+		return
+	}
+
+	st.suggestion = nil
+	if line == "" {
+		return
+	}
+
+	suggestion := e.suggester.Suggest(line)
+	if strings.HasPrefix(suggestion, line) && len(suggestion) > len(line) {
+		st.suggestion = []rune(suggestion)
+	}
+}
@@ -0,0 +1,224 @@
+package lineeditor
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Key
+	}{
+		{
+			name:     "printable and unicode characters",
+			input:    "aé",
+			expected: []Key{{Rune: 'a'}, {Rune: 'é'}},
+		},
+		{
+			name:     "control keys",
+			input:    "\x01\r\x7f\t",
+			expected: []Key{{Name: "ctrl-a"}, {Name: "enter"}, {Name: "backspace"}, {Name: "tab"}},
+		},
+		{
+			name:     "arrow keys",
+			input:    "\x1b[A\x1b[D\x1bOC",
+			expected: []Key{{Name: "up"}, {Name: "left"}, {Name: "right"}},
+		},
+		{
+			name:     "tilde sequences",
+			input:    "\x1b[3~\x1b[1~",
+			expected: []Key{{Name: "delete"}, {Name: "home"}},
+		},
+		{
+			name:     "modified arrows",
+			input:    "\x1b[1;3C\x1b[1;5D\x1b\x1b[C",
+			expected: []Key{{Name: "alt-right"}, {Name: "ctrl-left"}, {Name: "alt-right"}},
+		},
+		{
+			name:     "alt characters",
+			input:    "\x1bf\x1b\x7f",
+			expected: []Key{{Name: "alt-f"}, {Name: "alt-backspace"}},
+		},
+		{
+			name:     "lone escape",
+			input:    "\x1b",
+			expected: []Key{{Name: "escape"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.input))
+
+			var keys []Key
+			for {
+				key, err := readKey(r)
+				if err == io.EOF {
+					break
+				}
+				assert.NoError(t, err)
+				keys = append(keys, key)
+			}
+
+			assert.Equal(t, tt.expected, keys)
+		})
+	}
+}
+
+func TestRunAction(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		pos          int
+		actions      []string
+		expectedLine string
+		expectedPos  int
+	}{
+		{
+			name:         "move to beginning and delete",
+			line:         "echo hi",
+			pos:          7,
+			actions:      []string{"beginning-of-line", "delete-char"},
+			expectedLine: "cho hi",
+			expectedPos:  0,
+		},
+		{
+			name:         "word motions",
+			line:         "git commit -m",
+			pos:          13,
+			actions:      []string{"backward-word", "backward-word"},
+			expectedLine: "git commit -m",
+			expectedPos:  4,
+		},
+		{
+			name:         "kill and yank",
+			line:         "ls -la /tmp",
+			pos:          6,
+			actions:      []string{"kill-line", "beginning-of-line", "yank"},
+			expectedLine: " /tmpls -la",
+			expectedPos:  5,
+		},
+		{
+			name:         "unix word rubout",
+			line:         "cat some/file.txt",
+			pos:          17,
+			actions:      []string{"unix-word-rubout"},
+			expectedLine: "cat ",
+			expectedPos:  4,
+		},
+		{
+			name:         "transpose at end of line",
+			line:         "gti",
+			pos:          3,
+			actions:      []string{"backward-char", "transpose-chars"},
+			expectedLine: "git",
+			expectedPos:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			e := New(nil, &out)
+			st := &lineState{buf: []rune(tt.line), pos: tt.pos}
+
+			for _, action := range tt.actions {
+				done, err := e.runAction(st, action)
+				assert.NoError(t, err)
+				assert.False(t, done)
+			}
+
+			assert.Equal(t, tt.expectedLine, string(st.buf))
+			assert.Equal(t, tt.expectedPos, st.pos)
+		})
+	}
+}
+
+func TestHistoryNavigation(t *testing.T) {
+	var out bytes.Buffer
+	e := New(nil, &out)
+	e.AddHistory("ls")
+	e.AddHistory("pwd")
+	e.AddHistory("pwd")
+
+	st := &lineState{buf: []rune("ec"), pos: 2, historyIndex: len(e.history)}
+
+	e.runAction(st, "previous-history")
+	assert.Equal(t, "pwd", string(st.buf))
+
+	e.runAction(st, "previous-history")
+	e.runAction(st, "previous-history")
+	assert.Equal(t, "ls", string(st.buf))
+
+	e.runAction(st, "next-history")
+	e.runAction(st, "next-history")
+	assert.Equal(t, "ec", string(st.buf))
+}
+
+type staticSuggester map[string]string
+
+func (s staticSuggester) Suggest(line string) string {
+	return s[line]
+}
+
+func TestAutosuggestion(t *testing.T) {
+	tests := []struct {
+		name         string
+		action       string
+		expectedLine string
+	}{
+		{
+			name:         "forward-char accepts the whole suggestion",
+			action:       "forward-char",
+			expectedLine: "git commit -m fix",
+		},
+		{
+			name:         "end-of-line accepts the whole suggestion",
+			action:       "end-of-line",
+			expectedLine: "git commit -m fix",
+		},
+		{
+			name:         "forward-word accepts one word",
+			action:       "forward-word",
+			expectedLine: "git commit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			e := New(nil, &out)
+			e.SetSuggester(staticSuggester{"git": "git commit -m fix"})
+
+			st := &lineState{buf: []rune("git"), pos: 3}
+			e.refresh(st)
+			assert.Contains(t, out.String(), " commit -m fix")
+
+			_, err := e.runAction(st, tt.action)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedLine, string(st.buf))
+			assert.Equal(t, len(st.buf), st.pos)
+		})
+	}
+
+	t.Run("suggestion hidden when cursor is not at the end", func(t *testing.T) {
+		var out bytes.Buffer
+		e := New(nil, &out)
+		e.SetSuggester(staticSuggester{"git": "git commit -m fix"})
+
+		st := &lineState{buf: []rune("git"), pos: 1}
+		e.refresh(st)
+		assert.NotContains(t, out.String(), "commit")
+
+		e.runAction(st, "forward-char")
+		assert.Equal(t, "git", string(st.buf))
+		assert.Equal(t, 2, st.pos)
+	})
+}
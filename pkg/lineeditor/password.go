@@ -0,0 +1,27 @@
+package lineeditor
+
+import (
+	"errors"
+	"io"
+
+	"golang.org/x/term"
+)
+
+// ErrNotTerminal is returned by ReadPassword when the input is not a terminal.
+var ErrNotTerminal = errors.New("input is not a terminal")
+
+// ReadPassword displays the prompt and reads a line with echo disabled.
+func (e *Editor) ReadPassword(prompt string) (string, error) {
+	if e.in == nil || !e.IsTerminal() {
+		return "", ErrNotTerminal
+	}
+
+	io.WriteString(e.out, prompt)
+	password, err := term.ReadPassword(int(e.in.Fd()))
+	io.WriteString(e.out, "\r\n")
+	if err != nil {
+		return "", err
+	}
+
+	return string(password), nil
+}
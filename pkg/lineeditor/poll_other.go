@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package lineeditor
+
+import (
+	"os"
+	"time"
+)
+
+// pollInput reports input as available, as waiting for it with a timeout is
+// not supported on this platform: deadlines only apply to buffered input.
+func pollInput(f *os.File, timeout time.Duration) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,619 @@
+package lineeditor
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// viState holds the vi mode state of the line being edited.
+type viState struct {
+	command         bool     // in command (normal) mode rather than insert mode
+	pending         []Key    // keys of the command being typed
+	recording       []Key    // keys of the change being recorded for "."
+	recordingInsert bool     // the recorded change continues in insert mode
+	replaying       bool     // a change is being repeated with "."
+	lastFind        [2]rune  // command (f, F, t or T) and character of the last find
+	undo            []viUndo // snapshots taken before each change
+}
+
+// viUndo is a snapshot of the line taken before a change.
+type viUndo struct {
+	buf []rune
+	pos int
+}
+
+// viCommand is a parsed vi command such as "3dw", "x" or "fa".
+type viCommand struct {
+	count  int
+	op     rune // operator (d, c or y) or simple command
+	motion rune
+	arg    rune // character argument of f, F, t, T and r
+	keys   []Key
+}
+
+// parse results of a pending vi command
+const (
+	viIncomplete = iota
+	viInvalid
+	viComplete
+)
+
+const (
+	viMotions     = "hlwbeWBE0^$fFtT;,"
+	viArgMotions  = "fFtT"
+	viOperators   = "dcy"
+	viSimple      = "xXr~sSDCYiaIApPu.jkv"
+	viChanges     = "xXr~sSDCpPdc"
+	viInsertModes = "sSCciaIA"
+)
+
+// enterViCommandMode leaves insert mode. The cursor moves back onto the
+// last inserted character like in vi.
+func (e *Editor) enterViCommandMode(st *lineState) {
+	st.vi.command = true
+	st.vi.pending = nil
+	st.pos = max(st.pos-1, 0)
+
+	if st.vi.recordingInsert {
+		e.viLastChange = st.vi.recording
+		st.vi.recording = nil
+		st.vi.recordingInsert = false
+	}
+}
+
+// enterViInsertMode switches to insert mode, continuing the recording of
+// the change that started it.
+func (e *Editor) enterViInsertMode(st *lineState) {
+	st.vi.command = false
+	st.vi.recordingInsert = !st.vi.replaying
+}
+
+// handleViCommandKey handles a key press in vi command mode.
+func (e *Editor) handleViCommandKey(st *lineState, key Key) (bool, error) {
+	if len(st.vi.pending) == 0 {
+		if action, ok := e.lookup(KeymapViCommand, keyName(key)); ok {
+			return e.runAction(st, action)
+		}
+
+		// Named keys behave like their vi equivalents
+		switch key.Name {
+		case "enter":
+			return e.runAction(st, "accept-line")
+		case "ctrl-c":
+			return e.runAction(st, "interrupt")
+		case "ctrl-d":
+			if len(st.buf) == 0 {
+				return true, io.EOF
+			}
+			return false, nil
+		case "left", "backspace":
+			key = Key{Rune: 'h'}
+		case "right":
+			key = Key{Rune: 'l'}
+		case "up":
+			key = Key{Rune: 'k'}
+		case "down":
+			key = Key{Rune: 'j'}
+		case "home":
+			key = Key{Rune: '0'}
+		case "end":
+			key = Key{Rune: '$'}
+		case "delete":
+			key = Key{Rune: 'x'}
+		}
+	}
+
+	st.vi.pending = append(st.vi.pending, key)
+	cmd, status := parseViCommand(st.vi.pending)
+	switch status {
+	case viIncomplete:
+		return false, nil
+	case viInvalid:
+		st.vi.pending = nil
+		return false, nil
+	}
+	st.vi.pending = nil
+
+	return e.executeViCommand(st, cmd)
+}
+
+// parseViCommand parses "[count] op [count] motion", "[count] motion" or
+// "[count] command" from the keys typed so far.
+func parseViCommand(keys []Key) (viCommand, int) {
+	cmd := viCommand{keys: keys}
+	i := 0
+
+	next := func() (rune, int) {
+		if i >= len(keys) {
+			return 0, viIncomplete
+		}
+		k := keys[i]
+		i++
+		if k.Name != "" {
+			return 0, viInvalid
+		}
+		return k.Rune, viComplete
+	}
+	readCount := func() (int, int) {
+		n := 0
+		for i < len(keys) && keys[i].Name == "" && unicode.IsDigit(keys[i].Rune) && (n > 0 || keys[i].Rune != '0') {
+			n = n*10 + int(keys[i].Rune-'0')
+			i++
+		}
+		if i >= len(keys) {
+			return n, viIncomplete
+		}
+		return max(n, 1), viComplete
+	}
+
+	count, status := readCount()
+	if status != viComplete {
+		return cmd, status
+	}
+	cmd.count = count
+
+	r, status := next()
+	if status != viComplete {
+		return cmd, status
+	}
+
+	switch {
+	case strings.ContainsRune(viOperators, r):
+		cmd.op = r
+		count, status := readCount()
+		if status != viComplete {
+			return cmd, status
+		}
+		cmd.count *= count
+
+		m, status := next()
+		if status != viComplete {
+			return cmd, status
+		}
+		if m != r && !strings.ContainsRune(viMotions, m) {
+			return cmd, viInvalid
+		}
+		cmd.motion = m
+
+	case strings.ContainsRune(viMotions, r):
+		cmd.motion = r
+
+	case strings.ContainsRune(viSimple, r):
+		cmd.op = r
+		if r != 'r' {
+			return cmd, viComplete
+		}
+		arg, status := next()
+		cmd.arg = arg
+		return cmd, status
+
+	default:
+		return cmd, viInvalid
+	}
+
+	if strings.ContainsRune(viArgMotions, cmd.motion) {
+		arg, status := next()
+		cmd.arg = arg
+		return cmd, status
+	}
+	return cmd, viComplete
+}
+
+// executeViCommand runs a parsed vi command.
+func (e *Editor) executeViCommand(st *lineState, cmd viCommand) (bool, error) {
+	if strings.ContainsRune(viChanges, cmd.op) || strings.ContainsRune(viInsertModes, cmd.op) {
+		if !st.vi.replaying {
+			st.vi.undo = append(st.vi.undo, viUndo{buf: append([]rune(nil), st.buf...), pos: st.pos})
+			st.vi.recording = append([]Key(nil), cmd.keys...)
+		}
+	}
+
+	done, err := e.applyViCommand(st, cmd)
+
+	if !st.vi.command {
+		return done, err
+	}
+	if strings.ContainsRune(viChanges, cmd.op) && !st.vi.replaying {
+		e.viLastChange = st.vi.recording
+		st.vi.recording = nil
+	}
+	if len(st.buf) > 0 && st.pos >= len(st.buf) {
+		st.pos = len(st.buf) - 1
+	}
+	return done, err
+}
+
+// applyViCommand performs the effect of a vi command on the line.
+func (e *Editor) applyViCommand(st *lineState, cmd viCommand) (bool, error) {
+	switch cmd.op {
+	case 0:
+		if target, _, ok := e.viMotion(st, cmd.motion, cmd.arg, cmd.count, false); ok {
+			st.pos = target
+		}
+	case 'd', 'c', 'y':
+		e.viOperator(st, cmd)
+
+	case 'x':
+		e.killed = st.delete(st.pos, st.pos+cmd.count)
+	case 'X':
+		e.killed = st.delete(st.pos-cmd.count, st.pos)
+	case 'r':
+		if st.pos+cmd.count <= len(st.buf) {
+			for i := 0; i < cmd.count; i++ {
+				st.buf[st.pos+i] = cmd.arg
+			}
+			st.pos += cmd.count - 1
+		}
+	case '~':
+		for i := 0; i < cmd.count && st.pos < len(st.buf); i++ {
+			r := st.buf[st.pos]
+			if unicode.IsUpper(r) {
+				st.buf[st.pos] = unicode.ToLower(r)
+			} else {
+				st.buf[st.pos] = unicode.ToUpper(r)
+			}
+			st.pos++
+		}
+	case 's':
+		e.killed = st.delete(st.pos, st.pos+cmd.count)
+		e.enterViInsertMode(st)
+	case 'S':
+		e.killed = st.delete(0, len(st.buf))
+		e.enterViInsertMode(st)
+	case 'D':
+		e.killed = st.delete(st.pos, len(st.buf))
+	case 'C':
+		e.killed = st.delete(st.pos, len(st.buf))
+		e.enterViInsertMode(st)
+	case 'Y':
+		e.killed = append([]rune(nil), st.buf...)
+
+	case 'i':
+		e.enterViInsertMode(st)
+	case 'a':
+		st.pos = min(st.pos+1, len(st.buf))
+		e.enterViInsertMode(st)
+	case 'I':
+		st.pos = firstNonBlank(st.buf)
+		e.enterViInsertMode(st)
+	case 'A':
+		st.pos = len(st.buf)
+		e.enterViInsertMode(st)
+
+	case 'p':
+		if len(e.killed) > 0 {
+			st.pos = min(st.pos+1, len(st.buf))
+			for i := 0; i < cmd.count; i++ {
+				st.insert(e.killed...)
+			}
+			st.pos--
+		}
+	case 'P':
+		if len(e.killed) > 0 {
+			for i := 0; i < cmd.count; i++ {
+				st.insert(e.killed...)
+			}
+			st.pos--
+		}
+
+	case 'u':
+		if n := len(st.vi.undo); n > 0 {
+			snapshot := st.vi.undo[n-1]
+			st.vi.undo = st.vi.undo[:n-1]
+			st.buf = snapshot.buf
+			st.pos = snapshot.pos
+		}
+	case '.':
+		return e.repeatViChange(st)
+
+	case 'j':
+		e.moveHistory(st, cmd.count)
+		st.pos = 0
+	case 'k':
+		e.moveHistory(st, -cmd.count)
+		st.pos = 0
+	case 'v':
+		return e.editAndExecute(st)
+	}
+
+	return false, nil
+}
+
+// viOperator applies d, c or y over a motion, or over the whole line when
+// the operator is doubled (dd, cc, yy).
+func (e *Editor) viOperator(st *lineState, cmd viCommand) {
+	from, to := 0, len(st.buf)
+
+	if cmd.motion != cmd.op {
+		motion := cmd.motion
+		// cw on a word changes to the end of the word, like ce
+		if cmd.op == 'c' && (motion == 'w' || motion == 'W') && st.pos < len(st.buf) && !unicode.IsSpace(st.buf[st.pos]) {
+			motion += 'e' - 'w'
+		}
+
+		target, inclusive, ok := e.viMotion(st, motion, cmd.arg, cmd.count, true)
+		if !ok {
+			return
+		}
+		from, to = st.pos, target
+		if target < st.pos {
+			from, to = target, st.pos
+		} else if inclusive {
+			to++
+		}
+	}
+
+	switch cmd.op {
+	case 'd':
+		e.killed = st.delete(from, to)
+	case 'c':
+		e.killed = st.delete(from, to)
+		e.enterViInsertMode(st)
+	case 'y':
+		e.killed = append([]rune(nil), st.buf[from:min(to, len(st.buf))]...)
+		st.pos = from
+	}
+}
+
+// viMotion returns the target of a motion and whether the character at the
+// target is included when an operator is applied.
+func (e *Editor) viMotion(st *lineState, motion, arg rune, count int, operator bool) (int, bool, bool) {
+	buf, pos := st.buf, st.pos
+
+	switch motion {
+	case 'h':
+		return max(pos-count, 0), false, true
+	case 'l':
+		return min(pos+count, len(buf)), false, true
+	case '0':
+		return 0, false, true
+	case '^':
+		return firstNonBlank(buf), false, true
+	case '$':
+		if operator {
+			return len(buf), false, true
+		}
+		return max(len(buf)-1, 0), true, true
+	case 'w', 'W':
+		for i := 0; i < count; i++ {
+			pos = nextWordStart(buf, pos, motion == 'W')
+		}
+		return pos, false, true
+	case 'b', 'B':
+		for i := 0; i < count; i++ {
+			pos = prevWordStart(buf, pos, motion == 'B')
+		}
+		return pos, false, true
+	case 'e', 'E':
+		for i := 0; i < count; i++ {
+			pos = wordEndPos(buf, pos, motion == 'E')
+		}
+		return pos, true, true
+	case 'f', 'F', 't', 'T':
+		st.vi.lastFind = [2]rune{motion, arg}
+		return findChar(buf, pos, motion, arg, count)
+	case ';', ',':
+		find, ch := st.vi.lastFind[0], st.vi.lastFind[1]
+		if find == 0 {
+			return pos, false, false
+		}
+		if motion == ',' {
+			find = reverseFind(find)
+		}
+		return findChar(buf, pos, find, ch, count)
+	}
+
+	return pos, false, false
+}
+
+// repeatViChange replays the keys of the last change.
+func (e *Editor) repeatViChange(st *lineState) (bool, error) {
+	keys := e.viLastChange
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	st.vi.undo = append(st.vi.undo, viUndo{buf: append([]rune(nil), st.buf...), pos: st.pos})
+	st.vi.replaying = true
+	defer func() { st.vi.replaying = false }()
+
+	for _, key := range keys {
+		if _, err := e.handleKey(st, key); err != nil {
+			return false, err
+		}
+	}
+
+	// A change that ended in insert mode returns to command mode
+	if !st.vi.command {
+		e.enterViCommandMode(st)
+	}
+	return false, nil
+}
+
+// editAndExecute opens the line in $VISUAL or $EDITOR and runs the result.
+func (e *Editor) editAndExecute(st *lineState) (bool, error) {
+	f, err := os.CreateTemp("", "goshell-*.sh")
+	if err != nil {
+		return false, nil
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(string(st.buf) + "\n")
+	f.Close()
+	if err != nil {
+		return false, nil
+	}
+
+	fields := editorCommand()
+
+	io.WriteString(e.out, "\r\n")
+	fd := int(e.in.Fd())
+	if e.termState != nil {
+		term.Restore(fd, e.termState)
+	}
+
+	cmd := exec.Command(fields[0], append(fields[1:], f.Name())...)
+	cmd.Stdin = e.in
+	cmd.Stdout = e.out
+	cmd.Stderr = e.out
+	runErr := cmd.Run()
+
+	if e.termState != nil {
+		term.MakeRaw(fd)
+	}
+	if runErr != nil {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return false, nil
+	}
+
+	st.setLine([]rune(strings.TrimRight(string(data), "\n")))
+	st.vi = viState{}
+	return e.runAction(st, "accept-line")
+}
+
+// editorCommand returns the command line of $VISUAL, or else of $EDITOR, or
+// vi. A variable that is empty or only white space is taken as not set.
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// findChar finds the count-th occurrence of ch for the f, F, t and T motions.
+func findChar(buf []rune, pos int, find, ch rune, count int) (int, bool, bool) {
+	forward := find == 'f' || find == 't'
+
+	i := pos
+	// Repeating t or T must not find the character right next to the cursor again
+	if find == 't' && i+1 < len(buf) && buf[i+1] == ch {
+		i++
+	}
+	if find == 'T' && i > 0 && buf[i-1] == ch {
+		i--
+	}
+
+	for n := 0; n < count; n++ {
+		for {
+			if forward {
+				i++
+			} else {
+				i--
+			}
+			if i < 0 || i >= len(buf) {
+				return pos, false, false
+			}
+			if buf[i] == ch {
+				break
+			}
+		}
+	}
+
+	switch find {
+	case 't':
+		return i - 1, true, true
+	case 'T':
+		return i + 1, false, true
+	case 'f':
+		return i, true, true
+	}
+	return i, false, true
+}
+
+// reverseFind returns the find command searching in the opposite direction.
+func reverseFind(find rune) rune {
+	switch find {
+	case 'f':
+		return 'F'
+	case 'F':
+		return 'f'
+	case 't':
+		return 'T'
+	}
+	return 't'
+}
+
+// charClass classifies a rune for vi word motions: blanks, word characters
+// and punctuation. Big words only distinguish blanks from the rest.
+func charClass(r rune, big bool) int {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case big || isWordRune(r):
+		return 1
+	}
+	return 2
+}
+
+// nextWordStart returns the start of the next word (w, W).
+func nextWordStart(buf []rune, pos int, big bool) int {
+	if pos >= len(buf) {
+		return len(buf)
+	}
+
+	i := pos
+	if c := charClass(buf[i], big); c != 0 {
+		for i < len(buf) && charClass(buf[i], big) == c {
+			i++
+		}
+	}
+	for i < len(buf) && charClass(buf[i], big) == 0 {
+		i++
+	}
+	return i
+}
+
+// prevWordStart returns the start of the previous word (b, B).
+func prevWordStart(buf []rune, pos int, big bool) int {
+	i := pos
+	for i > 0 && charClass(buf[i-1], big) == 0 {
+		i--
+	}
+	if i == 0 {
+		return 0
+	}
+
+	c := charClass(buf[i-1], big)
+	for i > 0 && charClass(buf[i-1], big) == c {
+		i--
+	}
+	return i
+}
+
+// wordEndPos returns the end of the current or next word (e, E).
+func wordEndPos(buf []rune, pos int, big bool) int {
+	i := pos + 1
+	for i < len(buf) && charClass(buf[i], big) == 0 {
+		i++
+	}
+	if i >= len(buf) {
+		return max(len(buf)-1, 0)
+	}
+
+	c := charClass(buf[i], big)
+	for i+1 < len(buf) && charClass(buf[i+1], big) == c {
+		i++
+	}
+	return i
+}
+
+// firstNonBlank returns the index of the first non-blank character.
+func firstNonBlank(buf []rune) int {
+	for i, r := range buf {
+		if !unicode.IsSpace(r) {
+			return i
+		}
+	}
+	return len(buf)
+}
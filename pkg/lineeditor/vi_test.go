@@ -0,0 +1,173 @@
+package lineeditor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// viKeys converts a string to key presses, with '\x1b' as the escape key.
+func viKeys(s string) []Key {
+	var keys []Key
+	for _, r := range s {
+		if r == '\x1b' {
+			keys = append(keys, Key{Name: "escape"})
+			continue
+		}
+		keys = append(keys, Key{Rune: r})
+	}
+	return keys
+}
+
+func TestViMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		keys         string
+		expectedLine string
+		expectedPos  int
+	}{
+		{
+			name:         "insert then escape moves back one",
+			line:         "",
+			keys:         "echo hi\x1b",
+			expectedLine: "echo hi",
+			expectedPos:  6,
+		},
+		{
+			name:         "motions",
+			line:         "git commit -m msg",
+			keys:         "\x1b0wwl",
+			expectedLine: "git commit -m msg",
+			expectedPos:  12,
+		},
+		{
+			name:         "dd clears the line",
+			line:         "rm -rf build",
+			keys:         "\x1bdd",
+			expectedLine: "",
+			expectedPos:  0,
+		},
+		{
+			name:         "dw with count",
+			line:         "one two three four",
+			keys:         "\x1b02dw",
+			expectedLine: "three four",
+			expectedPos:  0,
+		},
+		{
+			name:         "cw changes to end of word",
+			line:         "git comit -m",
+			keys:         "\x1b0wcwcommit\x1b",
+			expectedLine: "git commit -m",
+			expectedPos:  9,
+		},
+		{
+			name:         "dot repeats the last change",
+			line:         "a b c d",
+			keys:         "\x1b0x..",
+			expectedLine: " c d",
+			expectedPos:  0,
+		},
+		{
+			name:         "dot repeats an insert",
+			line:         "x",
+			keys:         "\x1bA!\x1b.",
+			expectedLine: "x!!",
+			expectedPos:  2,
+		},
+		{
+			name:         "find and delete till",
+			line:         "cp src/main.go dst",
+			keys:         "\x1b0dt/",
+			expectedLine: "/main.go dst",
+			expectedPos:  0,
+		},
+		{
+			name:         "delete to end and undo",
+			line:         "ls -la /tmp",
+			keys:         "\x1b0wDu",
+			expectedLine: "ls -la /tmp",
+			expectedPos:  3,
+		},
+		{
+			name:         "replace and toggle case",
+			line:         "Gti",
+			keys:         "\x1b0~rI",
+			expectedLine: "gIi",
+			expectedPos:  1,
+		},
+		{
+			name:         "yank and put",
+			line:         "ab",
+			keys:         "\x1b0ylp",
+			expectedLine: "aab",
+			expectedPos:  1,
+		},
+		{
+			name:         "invalid command is ignored",
+			line:         "ls",
+			keys:         "\x1bdq0",
+			expectedLine: "ls",
+			expectedPos:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			e := New(nil, &out)
+			assert.NoError(t, e.SetMode(ModeVi))
+
+			st := &lineState{buf: []rune(tt.line), pos: len([]rune(tt.line))}
+			for _, key := range viKeys(tt.keys) {
+				done, err := e.handleKey(st, key)
+				assert.NoError(t, err)
+				assert.False(t, done)
+			}
+
+			assert.Equal(t, tt.expectedLine, string(st.buf))
+			assert.Equal(t, tt.expectedPos, st.pos)
+		})
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "  ")
+	t.Setenv("EDITOR", "code --wait")
+	assert.Equal(t, []string{"code", "--wait"}, editorCommand())
+
+	t.Setenv("EDITOR", "\t")
+	assert.Equal(t, []string{"vi"}, editorCommand())
+
+	t.Setenv("VISUAL", "nano")
+	assert.Equal(t, []string{"nano"}, editorCommand())
+}
+
+func TestBindings(t *testing.T) {
+	var out bytes.Buffer
+	e := New(nil, &out)
+
+	err := e.SetBindings(map[string]map[string]string{
+		KeymapEmacs:     {"ctrl-o": "beginning-of-line"},
+		KeymapViCommand: {"x": "kill-line"},
+	})
+	assert.NoError(t, err)
+
+	st := &lineState{buf: []rune("echo hi"), pos: 7}
+	e.handleKey(st, Key{Name: "ctrl-o"})
+	assert.Equal(t, 0, st.pos)
+
+	e.SetMode(ModeVi)
+	st = &lineState{buf: []rune("echo hi"), pos: 7}
+	e.handleKey(st, Key{Name: "escape"})
+	e.handleKey(st, Key{Rune: '0'})
+	e.handleKey(st, Key{Rune: 'w'})
+	e.handleKey(st, Key{Rune: 'x'})
+	assert.Equal(t, "echo ", string(st.buf))
+
+	assert.EqualError(t, e.SetBindings(map[string]map[string]string{KeymapEmacs: {"ctrl-o": "fly"}}), "unknown action: fly")
+	assert.EqualError(t, e.SetBindings(map[string]map[string]string{KeymapEmacs: {"hyper-o": "yank"}}), "unknown key: hyper-o")
+	assert.EqualError(t, e.SetBindings(map[string]map[string]string{"vim": {"x": "yank"}}), "unknown keymap: vim")
+}